@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/response"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type GetAgreementsResponse = response.APIResponse[[]service.AgreementInformation]
+type GetAgreementDisbursementsResponse = response.APIResponse[[]service.AgreementDisbursementInformation]
+
+// @Summary		Get agreements
+// @Description	Get the agreements (convênios and other voluntary transfers) granted by the management units.
+// @Tags			Agreements
+// @Produce		json
+// @Param			start_date				query		string					false	"Start of validity for filtering (YYYY-MM-DD)"
+// @Param			end_date				query		string					false	"End of validity for filtering (YYYY-MM-DD)"
+// @Param			management_unit_codes	query		string					false	"Comma-separated list of granting management unit codes"
+// @Param			situation				query		string					false	"Agreement situation (e.g. EM EXECUÇÃO)"
+// @Success		200						{object}	GetAgreementsResponse	"Successfully retrieved agreements"
+// @Failure		500						{object}	response.ErrorResponse	"Failed to get agreements"
+// @Router			/agreements [get]
+func (app *application) handleGetAgreements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startParam := r.URL.Query().Get("start_date")
+	endParam := r.URL.Query().Get("end_date")
+	managementUnitCodesParam := r.URL.Query().Get("management_unit_codes")
+
+	var filter service.GetAgreementsFilter
+
+	filter.StartDate, _ = time.Parse("2006-01-02", parseDateOrDefault(startParam, "2000-01-01"))
+	filter.EndDate, _ = time.Parse("2006-01-02", parseDateOrDefault(endParam, "2100-12-31"))
+	filter.Situation = r.URL.Query().Get("situation")
+
+	if managementUnitCodesParam != "" {
+		filter.ManagementUnitCodes = strings.Split(managementUnitCodesParam, ",")
+	}
+
+	data, err := app.store.Agreement.GetAgreements(ctx, filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get agreements: "+err.Error())
+		return
+	}
+
+	response := &GetAgreementsResponse{
+		Success: true,
+		Data:    data,
+		Message: "Successfully retrieved agreements",
+	}
+
+	if err := writeJSON(w, http.StatusOK, response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
+
+// @Summary		Get agreement disbursements
+// @Description	Get the releases of funds recorded for an agreement.
+// @Tags			Agreements
+// @Produce		json
+// @Param			agreementNumber	path		string								true	"Agreement number"
+// @Success		200				{object}	GetAgreementDisbursementsResponse	"Successfully retrieved agreement disbursements"
+// @Failure		500				{object}	response.ErrorResponse				"Failed to get agreement disbursements"
+// @Router			/agreements/{agreementNumber}/disbursements [get]
+func (app *application) handleGetAgreementDisbursements(w http.ResponseWriter, r *http.Request) {
+	agreementNumber := chi.URLParam(r, "agreementNumber")
+
+	ctx := r.Context()
+	data, err := app.store.Agreement.GetAgreementDisbursements(ctx, agreementNumber)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get agreement disbursements: "+err.Error())
+		return
+	}
+
+	response := &GetAgreementDisbursementsResponse{
+		Success: true,
+		Data:    data,
+		Message: "Successfully retrieved agreement disbursements",
+	}
+
+	if err := writeJSON(w, http.StatusOK, response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
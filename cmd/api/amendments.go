@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/response"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+type GetAmendmentAuthorsResponse = response.APIResponse[[]service.AmendmentAuthorInformation]
+type GetAmendmentExecutionResponse = response.APIResponse[[]service.AmendmentExecutionRow]
+
+// @Summary		Get amendment authors
+// @Description	Get the parliamentary amendment authors with the number of amendments ingested for each.
+// @Tags			Amendments
+// @Produce		json
+// @Success		200	{object}	GetAmendmentAuthorsResponse	"Successfully retrieved amendment authors"
+// @Failure		500	{object}	response.ErrorResponse		"Failed to get amendment authors"
+// @Router			/amendments/authors [get]
+func (app *application) handleGetAmendmentAuthors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	data, err := app.store.Amendment.GetAuthors(ctx)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get amendment authors: "+err.Error())
+		return
+	}
+
+	response := &GetAmendmentAuthorsResponse{
+		Success: true,
+		Data:    data,
+		Message: "Successfully retrieved amendment authors",
+	}
+
+	if err := writeJSON(w, http.StatusOK, response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
+
+// @Summary		Get amendment execution
+// @Description	Get committed, liquidated and paid values from expenses_execution broken down by amendment author, action and municipality.
+// @Tags			Amendments
+// @Produce		json
+// @Param			management_code			query		int								true	"Management code (required)"
+// @Param			management_unit_codes	query		string							false	"Comma-separated list of management unit codes (optional)"
+// @Param			author_codes			query		string							false	"Comma-separated list of amendment author codes (optional)"
+// @Param			start_date				query		string							false	"Start date for filtering (YYYY-MM-DD, optional)"
+// @Param			end_date				query		string							false	"End date for filtering (YYYY-MM-DD, optional)"
+// @Success		200						{object}	GetAmendmentExecutionResponse	"Successfully retrieved amendment execution"
+// @Failure		400						{object}	response.ErrorResponse			"Invalid request payload"
+// @Failure		500						{object}	response.ErrorResponse			"Failed to get amendment execution"
+// @Router			/amendments/execution [get]
+func (app *application) handleGetAmendmentExecution(w http.ResponseWriter, r *http.Request) {
+	expensesFilter, err := parseExpensesFilter(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := service.AmendmentsFilter{ExpensesFilter: expensesFilter}
+	if authorCodesParam := r.URL.Query().Get("author_codes"); authorCodesParam != "" {
+		for _, code := range strings.Split(authorCodesParam, ",") {
+			codeInt, err := strconv.Atoi(code)
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid author_code: %v", err))
+				return
+			}
+			filter.AuthorCodes = append(filter.AuthorCodes, codeInt)
+		}
+	}
+
+	ctx := r.Context()
+	data, err := app.store.Amendment.GetAmendmentExecution(ctx, filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get amendment execution: "+err.Error())
+		return
+	}
+
+	response := &GetAmendmentExecutionResponse{
+		Success: true,
+		Data:    data,
+		Message: "Successfully retrieved amendment execution",
+	}
+
+	if err := writeJSON(w, http.StatusOK, response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
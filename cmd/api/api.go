@@ -66,16 +66,37 @@ func (app *application) mount() http.Handler {
 			r.Get("/summary/by-management", app.handleGetExpensesSummaryByManagement)
 			r.Get("/budget-execution/report", app.handleGetBudgetExecutionReport)
 			r.Get("/top-favored", app.handleGetTopFavored)
+			r.Get("/sanctioned-favored", app.handleGetSanctionedFavored)
+		})
+		r.Route("/agreements", func(r chi.Router) {
+			r.Get("/", app.handleGetAgreements)
+			r.Get("/{agreementNumber}/disbursements", app.handleGetAgreementDisbursements)
+		})
+		r.Route("/amendments", func(r chi.Router) {
+			r.Get("/authors", app.handleGetAmendmentAuthors)
+			r.Get("/execution", app.handleGetAmendmentExecution)
+		})
+		r.Route("/travel", func(r chi.Router) {
+			r.Get("/costs/{groupBy}", app.handleGetTravelCosts)
 		})
 		r.Route("/budget-execution", func(r chi.Router) {
 			r.Get("/", app.handleGetBudgetExecution)
+			r.Get("/balance", app.handleGetBudgetBalance)
 		})
 		r.Route("/commitments", func(r chi.Router) {
 			r.Get("/", app.handleGetCommitmentsInformation)
 		})
+		r.Route("/contracts", func(r chi.Router) {
+			r.Get("/", app.handleGetContracts)
+		})
+		r.Route("/payments", func(r chi.Router) {
+			r.Get("/{paymentCode}", app.handleGetPaymentDetail)
+		})
 		r.Route("/ingestion", func(r chi.Router) {
 			r.Get("/history", app.handleGetIngestionHistory)
 			r.Post("/", app.handleCreateIngestion)
+			r.Get("/jobs/{id}", app.handleGetIngestionJob)
+			r.Get("/{id}/rejections", app.handleGetIngestionRejections)
 		})
 	})
 
@@ -7,7 +7,10 @@ import (
 	"github.com/farxc/envelopa-transparencia/internal/domain/service"
 )
 
-type GetBudgetExecutionResponse = response.APIResponse[[]service.BudgetExecutionRow]
+type (
+	GetBudgetExecutionResponse = response.APIResponse[[]service.BudgetExecutionRow]
+	GetBudgetBalanceResponse   = response.APIResponse[[]service.BudgetBalance]
+)
 
 // @Summary		Get budget execution rows
 // @Description	Get budget execution rows from expenses_execution table by applying various filters.
@@ -45,3 +48,40 @@ func (app *application) handleGetBudgetExecution(w http.ResponseWriter, r *http.
 		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
 	}
 }
+
+// @Summary		Get budget balance
+// @Description	Get collected revenue next to committed and paid values per month. Revenue is scoped to the units that executed expenses under the management code.
+// @Tags			BudgetExecution
+// @Produce		json
+// @Param			management_code			query		int							true	"Management code (required)"
+// @Param			management_unit_codes	query		string						false	"Comma-separated list of management unit codes (optional)"
+// @Param			start_date				query		string						false	"Start date for filtering (YYYY-MM-DD, optional)"
+// @Param			end_date				query		string						false	"End date for filtering (YYYY-MM-DD, optional)"
+// @Success		200						{object}	GetBudgetBalanceResponse	"Successfully retrieved budget balance"
+// @Failure		400						{object}	response.ErrorResponse		"Invalid request payload"
+// @Failure		500						{object}	response.ErrorResponse		"Failed to get budget balance"
+// @Router			/budget-execution/balance [get]
+func (app *application) handleGetBudgetBalance(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseExpensesFilter(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	data, err := app.store.Revenue.GetBudgetBalance(ctx, filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get budget balance: "+err.Error())
+		return
+	}
+
+	resp := &GetBudgetBalanceResponse{
+		Success: true,
+		Data:    data,
+		Message: "Successfully retrieved budget balance",
+	}
+
+	if err := writeJSON(w, http.StatusOK, resp); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/response"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+type GetContractsResponse = response.APIResponse[[]service.ContractInformation]
+
+// @Summary		Get contracts
+// @Description	Get contracts signed by the managing units, with the commitments linked to them through the process number.
+// @Tags			Contracts
+// @Produce		json
+// @Param			start_date				query		string					false	"Start signature date for filtering (YYYY-MM-DD)"
+// @Param			end_date				query		string					false	"End signature date for filtering (YYYY-MM-DD)"
+// @Param			management_unit_codes	query		string					false	"Comma-separated list of management unit codes for filtering"
+// @Param			contract_numbers		query		string					false	"Comma-separated list of contract numbers for filtering"
+// @Success		200						{object}	GetContractsResponse	"Successfully retrieved contracts"
+// @Failure		500						{object}	response.ErrorResponse	"Failed to get contracts"
+// @Router			/contracts [get]
+func (app *application) handleGetContracts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startParam := r.URL.Query().Get("start_date")
+	endParam := r.URL.Query().Get("end_date")
+	managementUnitCodesParam := r.URL.Query().Get("management_unit_codes")
+	contractNumbersParam := r.URL.Query().Get("contract_numbers")
+
+	var filter service.GetContractsFilter
+
+	filter.StartDate, _ = time.Parse("2006-01-02", parseDateOrDefault(startParam, "2000-01-01"))
+	filter.EndDate, _ = time.Parse("2006-01-02", parseDateOrDefault(endParam, "2100-12-31"))
+
+	if managementUnitCodesParam != "" {
+		filter.ManagementUnitCodes = strings.Split(managementUnitCodesParam, ",")
+	}
+
+	if contractNumbersParam != "" {
+		filter.ContractNumbers = strings.Split(contractNumbersParam, ",")
+	}
+
+	data, err := app.store.Contract.GetContracts(ctx, filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get contracts: "+err.Error())
+		return
+	}
+
+	response := &GetContractsResponse{
+		Success: true,
+		Data:    data,
+		Message: "Successfully retrieved contracts",
+	}
+
+	if err := writeJSON(w, http.StatusOK, response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
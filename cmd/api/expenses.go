@@ -13,6 +13,7 @@ type (
 	GetExpensesSummaryResponse    = response.APIResponse[service.SummaryByUnits]
 	GetGlobalSummaryResponse      = response.APIResponse[service.GlobalSummary]
 	GetTopFavoredResponse         = response.APIResponse[[]service.TopFavored]
+	GetSanctionedFavoredResponse  = response.APIResponse[[]service.SanctionedFavored]
 	GetExpensesByCategoryResponse = response.APIResponse[[]service.ExpensesByCategory]
 )
 
@@ -128,7 +129,7 @@ func (app *application) handleGetBudgetExecutionReport(w http.ResponseWriter, r
 }
 
 // @Summary		Get top favored entities
-// @Description	Get a list of top favored entities by applying various filters.
+// @Description	Get a list of top favored entities by applying various filters. Regular payments and government payment card (CPGF) transactions are ranked together.
 // @Tags			Expenses
 // @Produce		json
 // @Param			management_code			query		int						true	"Management code (required)"
@@ -172,3 +173,40 @@ func (app *application) handleGetTopFavored(w http.ResponseWriter, r *http.Reque
 		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
 	}
 }
+
+// @Summary		Get sanctioned favored entities
+// @Description	List commitments and payments issued to favored entities that were on the CEIS or CNEP sanction lists on the document date.
+// @Tags			Expenses
+// @Produce		json
+// @Param			management_code			query		int								true	"Management code (required)"
+// @Param			management_unit_codes	query		string							false	"Comma-separated list of management unit codes (optional)"
+// @Param			start_date				query		string							false	"Start date for filtering (YYYY-MM-DD, optional)"
+// @Param			end_date				query		string							false	"End date for filtering (YYYY-MM-DD, optional)"
+// @Success		200						{object}	GetSanctionedFavoredResponse	"Successfully retrieved sanctioned favored entities"
+// @Failure		400						{object}	response.ErrorResponse			"Invalid request payload"
+// @Failure		500						{object}	response.ErrorResponse			"Failed to get sanctioned favored"
+// @Router			/expenses/sanctioned-favored [get]
+func (app *application) handleGetSanctionedFavored(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseExpensesFilter(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	data, err := app.store.Sanction.GetSanctionedFavored(ctx, filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get sanctioned favored: "+err.Error())
+		return
+	}
+
+	response := &GetSanctionedFavoredResponse{
+		Success: true,
+		Data:    data,
+		Message: "Successfully retrieved sanctioned favored entities",
+	}
+
+	if err := writeJSON(w, http.StatusOK, response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/farxc/envelopa-transparencia/internal/domain/model"
 	"github.com/farxc/envelopa-transparencia/internal/domain/response"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+	"github.com/go-chi/chi/v5"
 )
 
 type GetIngestionHistoryResponse = response.APIResponse[[]model.IngestionHistory]
-type CreateIngestionResponse = response.APIResponse[*model.IngestionHistory]
+type IngestionJobResponse = response.APIResponse[*model.IngestionJob]
+type GetIngestionRejectionsResponse = response.APIResponse[[]model.RowRejection]
 
 // @Summary		Get ingestion history
 // @Description	Get a list of the latest ingestion records.
@@ -47,23 +53,24 @@ func (app *application) handleGetIngestionHistory(w http.ResponseWriter, r *http
 	}
 }
 
-// @Summary		Create ingestion record
-// @Description	Creates a new ingestion record with IN_PROGRESS status.
+// @Summary		Queue an ingestion
+// @Description	Queues an ingestion of a kind of data for a date range and unit codes. `etl serve` runs it; follow it with GET /ingestion/jobs/{id}. Periods already processed are skipped.
 // @Tags			Ingestion
 // @Accept			json
 // @Produce		json
-// @Param			ingestion	body		object{reference_date:string,source_file:string,trigger_type:string,scope_type:string,processed_codes:[]int64}	true	"Ingestion record details"
-// @Success		201			{object}	CreateIngestionResponse																							"Ingestion record initialized"
-// @Failure		400			{object}	response.ErrorResponse																							"Invalid request payload or missing fields"
-// @Failure		500			{object}	response.ErrorResponse																							"Failed to create ingestion record"
+// @Param			ingestion	body		object{kind:string,start_date:string,end_date:string,codes:[]int64,scope_type:string,trigger_type:string}	true	"Ingestion request (scope_type and trigger_type default to MANAGEMENT_UNIT and MANUAL)"
+// @Success		202			{object}	IngestionJobResponse																					"Ingestion job queued"
+// @Failure		400			{object}	response.ErrorResponse																					"Invalid request payload or missing fields"
+// @Failure		500			{object}	response.ErrorResponse																					"Failed to queue ingestion job"
 // @Router			/ingestion [post]
 func (app *application) handleCreateIngestion(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		ReferenceDate  string  `json:"reference_date"`
-		SourceFile     string  `json:"source_file"`
-		TriggerType    string  `json:"trigger_type"`
-		ScopeType      string  `json:"scope_type"`
-		ProcessedCodes []int64 `json:"processed_codes"`
+		Kind        string  `json:"kind"`
+		StartDate   string  `json:"start_date"`
+		EndDate     string  `json:"end_date"`
+		Codes       []int64 `json:"codes"`
+		ScopeType   string  `json:"scope_type"`
+		TriggerType string  `json:"trigger_type"`
 	}
 
 	if err := readJSON(w, r, &input); err != nil {
@@ -71,41 +78,153 @@ func (app *application) handleCreateIngestion(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Basic validation (can be expanded)
-	if input.ReferenceDate == "" || input.TriggerType == "" || input.ScopeType == "" {
-		writeJSONError(w, http.StatusBadRequest, "missing required fields")
+	if input.Kind == "" || input.StartDate == "" || len(input.Codes) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "missing required fields (kind, start_date, codes)")
+		return
+	}
+	if !slices.Contains(store.Datasets, input.Kind) {
+		writeJSONError(w, http.StatusBadRequest, "invalid kind (valid: "+strings.Join(store.Datasets, ", ")+")")
 		return
 	}
 
-	// Use parseDateOrDefault logic if needed, but here we expect a specific format
-	refDate, err := parseTime(input.ReferenceDate)
+	startDate, err := parseTime(input.StartDate)
 	if err != nil {
-		writeJSONError(w, http.StatusBadRequest, "invalid reference_date format (YYYY-MM-DD expected)")
+		writeJSONError(w, http.StatusBadRequest, "invalid start_date format (YYYY-MM-DD expected)")
+		return
+	}
+	endDate := startDate
+	if input.EndDate != "" {
+		if endDate, err = parseTime(input.EndDate); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid end_date format (YYYY-MM-DD expected)")
+			return
+		}
+	}
+	if endDate.Before(startDate) {
+		writeJSONError(w, http.StatusBadRequest, "end_date is before start_date")
 		return
 	}
 
-	history := &model.IngestionHistory{
-		ReferenceDate:  refDate,
-		SourceFile:     input.SourceFile,
-		TriggerType:    input.TriggerType,
-		ScopeType:      input.ScopeType,
-		Status:         store.StatusInProgress,
-		ProcessedCodes: input.ProcessedCodes,
+	if input.ScopeType == "" {
+		input.ScopeType = store.ScopeTypeManagingUnit
+	}
+	if input.ScopeType != store.ScopeTypeManagingUnit && input.ScopeType != store.ScopeTypeManagement {
+		writeJSONError(w, http.StatusBadRequest, "invalid scope_type (MANAGEMENT_UNIT or MANAGEMENT expected)")
+		return
+	}
+	if input.TriggerType == "" {
+		input.TriggerType = store.TriggerTypeManual
+	}
+	if input.TriggerType != store.TriggerTypeManual && input.TriggerType != store.TriggerTypeScheduled {
+		writeJSONError(w, http.StatusBadRequest, "invalid trigger_type (MANUAL or SCHEDULED expected)")
+		return
+	}
+
+	job := &model.IngestionJob{
+		Kind:        input.Kind,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Codes:       input.Codes,
+		ScopeType:   input.ScopeType,
+		TriggerType: input.TriggerType,
+		Ingestions:  []model.IngestionHistory{},
 	}
 
 	ctx := r.Context()
-	if err := app.store.IngestionHistory.InsertIngestionHistory(ctx, history); err != nil {
-		writeJSONError(w, http.StatusInternalServerError, "failed to create ingestion record: "+err.Error())
+	if err := app.store.IngestionJob.EnqueueJob(ctx, job); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to queue ingestion job: "+err.Error())
 		return
 	}
 
-	response := &CreateIngestionResponse{
+	response := &IngestionJobResponse{
 		Success: true,
-		Data:    history,
-		Message: "Ingestion record initialized with IN_PROGRESS status",
+		Data:    job,
+		Message: "Ingestion job queued",
 	}
 
-	if err := writeJSON(w, http.StatusCreated, response); err != nil {
+	if err := writeJSON(w, http.StatusAccepted, response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
+
+// @Summary		Get ingestion job
+// @Description	Get the live status of a queued ingestion job: status, attempts, last error and the ingestion records of its periods so far.
+// @Tags			Ingestion
+// @Produce		json
+// @Param			id	path		int						true	"Ingestion job ID"
+// @Success		200	{object}	IngestionJobResponse	"Successfully retrieved ingestion job"
+// @Failure		400	{object}	response.ErrorResponse	"Invalid ingestion job ID"
+// @Failure		404	{object}	response.ErrorResponse	"Ingestion job not found"
+// @Failure		500	{object}	response.ErrorResponse	"Failed to get ingestion job"
+// @Router			/ingestion/jobs/{id} [get]
+func (app *application) handleGetIngestionJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid ingestion job id")
+		return
+	}
+
+	ctx := r.Context()
+	job, err := app.store.IngestionJob.GetJob(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeJSONError(w, http.StatusNotFound, "ingestion job not found")
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get ingestion job: "+err.Error())
+		return
+	}
+
+	response := &IngestionJobResponse{
+		Success: true,
+		Data:    job,
+		Message: "Successfully retrieved ingestion job",
+	}
+
+	if err := writeJSON(w, http.StatusOK, response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
+
+// @Summary		Get ingestion rejections
+// @Description	Get the rows an ingestion could not load, with the file, line, column, raw value and reason. The ID is an ingestion record ID, not a job ID.
+// @Tags			Ingestion
+// @Produce		json
+// @Param			id		path		int								true	"Ingestion ID"
+// @Param			limit	query		int								false	"Limit the number of results"	default(100)
+// @Param			offset	query		int								false	"Number of results to skip"		default(0)
+// @Success		200		{object}	GetIngestionRejectionsResponse	"Successfully retrieved ingestion rejections"
+// @Failure		400		{object}	response.ErrorResponse			"Invalid ingestion ID"
+// @Failure		500		{object}	response.ErrorResponse			"Failed to get ingestion rejections"
+// @Router			/ingestion/{id}/rejections [get]
+func (app *application) handleGetIngestionRejections(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid ingestion id")
+		return
+	}
+
+	limit, offset := 100, 0
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o > 0 {
+		offset = o
+	}
+
+	ctx := r.Context()
+	data, err := app.store.IngestionRejection.GetRejections(ctx, id, limit, offset)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get ingestion rejections: "+err.Error())
+		return
+	}
+
+	response := &GetIngestionRejectionsResponse{
+		Success: true,
+		Data:    data,
+		Message: "Successfully retrieved ingestion rejections",
+	}
+
+	if err := writeJSON(w, http.StatusOK, response); err != nil {
 		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
 	}
 }
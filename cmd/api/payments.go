@@ -0,0 +1,47 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/response"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type GetPaymentDetailResponse = response.APIResponse[*service.PaymentDetail]
+
+// @Summary		Get payment detail
+// @Description	Get a payment with its final beneficiaries and its bank, invoice and court-order (precatório) lists.
+// @Tags			Payments
+// @Produce		json
+// @Param			paymentCode	path		string						true	"Payment code"
+// @Success		200			{object}	GetPaymentDetailResponse	"Successfully retrieved payment detail"
+// @Failure		404			{object}	response.ErrorResponse		"Payment not found"
+// @Failure		500			{object}	response.ErrorResponse		"Failed to get payment detail"
+// @Router			/payments/{paymentCode} [get]
+func (app *application) handleGetPaymentDetail(w http.ResponseWriter, r *http.Request) {
+	paymentCode := chi.URLParam(r, "paymentCode")
+
+	ctx := r.Context()
+	data, err := app.store.Payment.GetPaymentDetail(ctx, paymentCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeJSONError(w, http.StatusNotFound, "payment not found")
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get payment detail: "+err.Error())
+		return
+	}
+
+	response := &GetPaymentDetailResponse{
+		Success: true,
+		Data:    data,
+		Message: "Successfully retrieved payment detail",
+	}
+
+	if err := writeJSON(w, http.StatusOK, response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
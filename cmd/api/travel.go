@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/response"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type GetTravelCostsResponse = response.APIResponse[[]service.TravelCost]
+
+// @Summary		Get travel costs
+// @Description	Get official travel costs (per diem, tickets and other expenses, minus returned amounts) aggregated by traveler role, destination or paying unit.
+// @Tags			Travel
+// @Produce		json
+// @Param			groupBy					path		string					true	"Aggregation key"	Enums(role, destination, unit)
+// @Param			start_date				query		string					false	"Start trip date for filtering (YYYY-MM-DD)"
+// @Param			end_date				query		string					false	"End trip date for filtering (YYYY-MM-DD)"
+// @Param			management_unit_codes	query		string					false	"Comma-separated list of paying management unit codes for filtering"
+// @Success		200						{object}	GetTravelCostsResponse	"Successfully retrieved travel costs"
+// @Failure		400						{object}	response.ErrorResponse	"Invalid grouping"
+// @Failure		500						{object}	response.ErrorResponse	"Failed to get travel costs"
+// @Router			/travel/costs/{groupBy} [get]
+func (app *application) handleGetTravelCosts(w http.ResponseWriter, r *http.Request) {
+	var filter service.GetTravelCostsFilter
+
+	filter.GroupBy = chi.URLParam(r, "groupBy")
+	switch filter.GroupBy {
+	case service.TravelGroupByRole, service.TravelGroupByDestination, service.TravelGroupByUnit:
+	default:
+		writeJSONError(w, http.StatusBadRequest, "groupBy must be one of: role, destination, unit")
+		return
+	}
+
+	startParam := r.URL.Query().Get("start_date")
+	endParam := r.URL.Query().Get("end_date")
+	managementUnitCodesParam := r.URL.Query().Get("management_unit_codes")
+
+	filter.StartDate, _ = time.Parse("2006-01-02", parseDateOrDefault(startParam, "2000-01-01"))
+	filter.EndDate, _ = time.Parse("2006-01-02", parseDateOrDefault(endParam, "2100-12-31"))
+
+	if managementUnitCodesParam != "" {
+		filter.ManagementUnitCodes = strings.Split(managementUnitCodesParam, ",")
+	}
+
+	ctx := r.Context()
+	data, err := app.store.Travel.GetTravelCosts(ctx, filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to get travel costs: "+err.Error())
+		return
+	}
+
+	response := &GetTravelCostsResponse{
+		Success: true,
+		Data:    data,
+		Message: "Successfully retrieved travel costs",
+	}
+
+	if err := writeJSON(w, http.StatusOK, response); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to write response")
+	}
+}
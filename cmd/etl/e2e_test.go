@@ -0,0 +1,494 @@
+package main
+
+import (
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/client/portal/portaltest"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// TestETLEndToEnd runs the etl binary against a fake portal and a throwaway
+// database. It needs E2E_DB_ADDR, a Postgres server where it may create and
+// drop databases; `make test-e2e` starts one in Docker.
+func TestETLEndToEnd(t *testing.T) {
+	serverAddr := os.Getenv("E2E_DB_ADDR")
+	if serverAddr == "" {
+		t.Skip("E2E_DB_ADDR not set; run `make test-e2e`")
+	}
+
+	dbAddr, database := newTestDatabase(t, serverAddr)
+	fake := portaltest.NewServer()
+	defer fake.Close()
+	fake.Unpublish("despesas/20250103")
+	etl := newETLRunner(t, dbAddr, fake.URL())
+
+	expenses := []string{"-kind", "expenses", "-init", "2025-01-02", "-end", "2025-01-03", "-codes", "158454,158148"}
+	etl.run(t, expenses...)
+
+	assertCount(t, database, "commitments", len(portaltest.DefaultUnits))
+	assertCount(t, database, "liquidations", len(portaltest.DefaultUnits))
+	assertCount(t, database, "payments", len(portaltest.DefaultUnits))
+	assertCount(t, database, "commitment_items", len(portaltest.DefaultUnits))
+	assertStatuses(t, database, "expenses", map[string]string{
+		"2025-01-02": "SUCCESS",
+		"2025-01-03": "RESCHEDULED",
+	})
+	assertCodeOutcomes(t, database, "expenses", "2025-01-02", model.CodeOutcomes{
+		{Code: 158454, Status: model.CodeLoaded},
+		{Code: 158148, Status: model.CodeLoaded},
+	})
+
+	// A second run skips the ingested day and tries the unpublished one again.
+	etl.run(t, expenses...)
+	if got := fake.Requests("despesas/20250102"); got != 1 {
+		t.Fatalf("ingested day requested %d times, want 1", got)
+	}
+	if got := fake.Requests("despesas/20250103"); got != 2 {
+		t.Fatalf("unpublished day requested %d times, want 2", got)
+	}
+	assertCount(t, database, "commitments", len(portaltest.DefaultUnits))
+
+	etl.run(t, "-kind", "expenses_execution", "-init", "2025-01-01", "-end", "2025-01-31", "-codes", "158454,158148")
+	assertCount(t, database, "expenses_execution", len(portaltest.DefaultUnits))
+	assertStatuses(t, database, "expenses_execution", map[string]string{"2025-01-01": "SUCCESS"})
+}
+
+// TestETLServeEndToEnd runs `etl serve` until it has caught up the days and
+// the month inside its catch-up window, then stops it with SIGTERM.
+func TestETLServeEndToEnd(t *testing.T) {
+	serverAddr := os.Getenv("E2E_DB_ADDR")
+	if serverAddr == "" {
+		t.Skip("E2E_DB_ADDR not set; run `make test-e2e`")
+	}
+
+	dbAddr, database := newTestDatabase(t, serverAddr)
+	fake := portaltest.NewServer()
+	defer fake.Close()
+	etl := newETLRunner(t, dbAddr, fake.URL())
+
+	cmd := etl.serve(t, "-catchupDays", "2", "-interval", "1h", "-codes", "158454,158148")
+	waitFor(t, time.Minute, func() bool {
+		var done int
+		database.Get(&done, `SELECT COUNT(*) FROM ingestion_history WHERE status = 'SUCCESS' AND trigger_type = 'SCHEDULED'`)
+		// Three days of expenses and the previous month of execution.
+		return done == 4
+	})
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to stop etl serve: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("etl serve exited with %v", err)
+	}
+
+	assertCount(t, database, "expenses_execution", len(portaltest.DefaultUnits))
+	var manual int
+	if err := database.Get(&manual, `SELECT COUNT(*) FROM ingestion_history WHERE trigger_type <> 'SCHEDULED'`); err != nil || manual != 0 {
+		t.Fatalf("found %d ingestions not triggered by the scheduler (err=%v)", manual, err)
+	}
+}
+
+// TestETLQueueEndToEnd queues an ingestion job like the API does and checks
+// that `etl serve` runs it and links its ingestion records to it.
+func TestETLQueueEndToEnd(t *testing.T) {
+	serverAddr := os.Getenv("E2E_DB_ADDR")
+	if serverAddr == "" {
+		t.Skip("E2E_DB_ADDR not set; run `make test-e2e`")
+	}
+
+	dbAddr, database := newTestDatabase(t, serverAddr)
+	fake := portaltest.NewServer()
+	defer fake.Close()
+	etl := newETLRunner(t, dbAddr, fake.URL())
+
+	var jobID int64
+	err := database.Get(&jobID, `
+		INSERT INTO ingestion_jobs (kind, start_date, end_date, codes, scope_type, trigger_type)
+		VALUES ('expenses', '2025-01-02', '2025-01-03', '{158454,158148}', 'MANAGEMENT_UNIT', 'MANUAL')
+		RETURNING id`)
+	if err != nil {
+		t.Fatalf("failed to queue job: %v", err)
+	}
+
+	cmd := etl.serve(t, "-schedules", "", "-poll", "200ms")
+	var status string
+	waitFor(t, time.Minute, func() bool {
+		database.Get(&status, `SELECT status FROM ingestion_jobs WHERE id = $1`, jobID)
+		return status != "QUEUED" && status != "RUNNING"
+	})
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to stop etl serve: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("etl serve exited with %v", err)
+	}
+
+	if status != "SUCCESS" {
+		t.Fatalf("job status = %q, want SUCCESS", status)
+	}
+	var linked int
+	if err := database.Get(&linked, `SELECT COUNT(*) FROM ingestion_history WHERE job_id = $1 AND status = 'SUCCESS'`, jobID); err != nil || linked != 2 {
+		t.Fatalf("job has %d successful ingestions, want 2 (err=%v)", linked, err)
+	}
+}
+
+// TestETLQueueReclaimsStaleJobs leaves ingestion jobs RUNNING past their
+// visibility timeout, as a crashed worker would, and checks that `etl serve`
+// runs the one with attempts left and fails the one without.
+func TestETLQueueReclaimsStaleJobs(t *testing.T) {
+	serverAddr := os.Getenv("E2E_DB_ADDR")
+	if serverAddr == "" {
+		t.Skip("E2E_DB_ADDR not set; run `make test-e2e`")
+	}
+
+	dbAddr, database := newTestDatabase(t, serverAddr)
+	fake := portaltest.NewServer()
+	defer fake.Close()
+	etl := newETLRunner(t, dbAddr, fake.URL())
+
+	var jobIDs []int64
+	err := database.Select(&jobIDs, `
+		INSERT INTO ingestion_jobs (kind, start_date, end_date, codes, scope_type, trigger_type, status, attempts, visible_at)
+		VALUES
+			('expenses', '2025-01-02', '2025-01-02', '{158454}', 'MANAGEMENT_UNIT', 'MANUAL', 'RUNNING', 1, NOW() - INTERVAL '1 minute'),
+			('expenses', '2025-01-03', '2025-01-03', '{158454}', 'MANAGEMENT_UNIT', 'MANUAL', 'RUNNING', 3, NOW() - INTERVAL '1 minute')
+		RETURNING id`)
+	if err != nil || len(jobIDs) != 2 {
+		t.Fatalf("failed to leave stale jobs: ids=%v err=%v", jobIDs, err)
+	}
+
+	cmd := etl.serve(t, "-schedules", "", "-poll", "200ms")
+	waitFor(t, time.Minute, func() bool {
+		var live int
+		database.Get(&live, `SELECT COUNT(*) FROM ingestion_jobs WHERE status IN ('QUEUED', 'RUNNING')`)
+		return live == 0
+	})
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to stop etl serve: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("etl serve exited with %v", err)
+	}
+
+	var reclaimed model.IngestionJob
+	if err := database.Get(&reclaimed, `SELECT status, attempts FROM ingestion_jobs WHERE id = $1`, jobIDs[0]); err != nil {
+		t.Fatalf("failed to read reclaimed job: %v", err)
+	}
+	if reclaimed.Status != "SUCCESS" || reclaimed.Attempts != 2 {
+		t.Fatalf("reclaimed job: status=%q attempts=%d, want SUCCESS after 2 attempts", reclaimed.Status, reclaimed.Attempts)
+	}
+	var exhausted model.IngestionJob
+	if err := database.Get(&exhausted, `SELECT status, last_error FROM ingestion_jobs WHERE id = $1`, jobIDs[1]); err != nil {
+		t.Fatalf("failed to read exhausted job: %v", err)
+	}
+	if exhausted.Status != "FAILURE" || !strings.Contains(exhausted.LastError, "visibility timeout") {
+		t.Fatalf("exhausted job: status=%q last_error=%q, want FAILURE on visibility timeout", exhausted.Status, exhausted.LastError)
+	}
+}
+
+// TestETLDurableQueueEndToEnd leaves a job in pipeline_jobs as a crashed worker
+// would, RUNNING past its visibility timeout, and checks that the next run on
+// the durable queue reclaims it instead of queueing the day again.
+func TestETLDurableQueueEndToEnd(t *testing.T) {
+	serverAddr := os.Getenv("E2E_DB_ADDR")
+	if serverAddr == "" {
+		t.Skip("E2E_DB_ADDR not set; run `make test-e2e`")
+	}
+
+	dbAddr, database := newTestDatabase(t, serverAddr)
+	fake := portaltest.NewServer()
+	defer fake.Close()
+	etl := newETLRunner(t, dbAddr, fake.URL())
+	etl.env = append(etl.env, "QUEUE_BACKEND=postgres", "QUEUE_POLL_INTERVAL=100ms")
+
+	_, err := database.Exec(`
+		INSERT INTO pipeline_jobs (dataset, job_key, payload, status, attempts, visible_at, locked_by)
+		VALUES ('expenses', '2025-01-02', '{"Date":"2025-01-02T00:00:00Z","Codes":[158454,158148],"Trigger":"MANUAL"}',
+			'RUNNING', 1, NOW() - INTERVAL '1 minute', 'crashed-host-1')`)
+	if err != nil {
+		t.Fatalf("failed to seed pipeline job: %v", err)
+	}
+
+	etl.run(t, "-kind", "expenses", "-init", "2025-01-02", "-end", "2025-01-03", "-codes", "158454,158148")
+
+	assertStatuses(t, database, "expenses", map[string]string{
+		"2025-01-02": "SUCCESS",
+		"2025-01-03": "SUCCESS",
+	})
+	var jobs []model.PipelineJob
+	if err := database.Select(&jobs, `SELECT id, dataset, job_key, payload, ingestion_job_id, status, attempts, visible_at, locked_by, last_error, created_at, updated_at FROM pipeline_jobs ORDER BY job_key`); err != nil {
+		t.Fatalf("failed to read pipeline jobs: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("found %d pipeline jobs, want one per day", len(jobs))
+	}
+	for _, job := range jobs {
+		if job.Status != "DONE" {
+			t.Errorf("job %s status = %s, want DONE", job.JobKey, job.Status)
+		}
+	}
+	if jobs[0].Attempts != 2 {
+		t.Errorf("reclaimed job made %d attempts, want 2", jobs[0].Attempts)
+	}
+}
+
+// TestETLOverlappingRunsEndToEnd starts two runs over overlapping ranges at
+// once and checks that the ingestion leases let only one of them process each
+// day.
+func TestETLOverlappingRunsEndToEnd(t *testing.T) {
+	serverAddr := os.Getenv("E2E_DB_ADDR")
+	if serverAddr == "" {
+		t.Skip("E2E_DB_ADDR not set; run `make test-e2e`")
+	}
+
+	dbAddr, database := newTestDatabase(t, serverAddr)
+	fake := portaltest.NewServer()
+	defer fake.Close()
+	etl := newETLRunner(t, dbAddr, fake.URL())
+
+	first := etl.start(t, "-kind", "expenses", "-init", "2025-01-02", "-end", "2025-01-04", "-codes", "158454,158148")
+	second := etl.start(t, "-kind", "expenses", "-init", "2025-01-03", "-end", "2025-01-05", "-codes", "158454,158148")
+	for _, cmd := range []*exec.Cmd{first, second} {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("etl %v exited with %v", cmd.Args, err)
+		}
+	}
+
+	for _, day := range []string{"20250102", "20250103", "20250104", "20250105"} {
+		if got := fake.Requests("despesas/" + day); got != 1 {
+			t.Errorf("day %s requested %d times, want 1", day, got)
+		}
+	}
+	var ingestions int
+	if err := database.Get(&ingestions, `SELECT COUNT(*) FROM ingestion_history WHERE dataset = 'expenses'`); err != nil || ingestions != 4 {
+		t.Fatalf("found %d expenses ingestions, want 4 (err=%v)", ingestions, err)
+	}
+	assertCount(t, database, "ingestion_leases", 0)
+}
+
+// TestETLInterruptedRunEndToEnd stops a run with SIGINT while a download hangs
+// and checks that the day is recorded as interrupted, its lease released, and
+// that the next run ingests only that day.
+func TestETLInterruptedRunEndToEnd(t *testing.T) {
+	serverAddr := os.Getenv("E2E_DB_ADDR")
+	if serverAddr == "" {
+		t.Skip("E2E_DB_ADDR not set; run `make test-e2e`")
+	}
+
+	dbAddr, database := newTestDatabase(t, serverAddr)
+	fake := portaltest.NewServer()
+	defer fake.Close()
+	fake.Stall("despesas/20250103")
+	etl := newETLRunner(t, dbAddr, fake.URL())
+
+	cmd := etl.start(t, "-kind", "expenses", "-init", "2025-01-02", "-end", "2025-01-03", "-codes", "158454,158148")
+	waitFor(t, time.Minute, func() bool {
+		var done int
+		database.Get(&done, `SELECT COUNT(*) FROM ingestion_history WHERE status = 'SUCCESS' AND reference_date = '2025-01-02'`)
+		return done == 1 && fake.Requests("despesas/20250103") > 0
+	})
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("failed to interrupt etl: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("interrupted etl exited with %v", err)
+	}
+
+	assertStatuses(t, database, "expenses", map[string]string{"2025-01-02": "SUCCESS", "2025-01-03": "FAILURE"})
+	var lastError string
+	if err := database.Get(&lastError, `SELECT last_error FROM ingestion_history WHERE reference_date = '2025-01-03'`); err != nil || lastError != "interrupted" {
+		t.Fatalf("last_error = %q, want %q (err=%v)", lastError, "interrupted", err)
+	}
+	assertCount(t, database, "ingestion_leases", 0)
+
+	fake.Release("despesas/20250103")
+	etl.run(t, "-kind", "expenses", "-init", "2025-01-02", "-end", "2025-01-03", "-codes", "158454,158148")
+	assertStatuses(t, database, "expenses", map[string]string{"2025-01-02": "SUCCESS", "2025-01-03": "SUCCESS"})
+	if got := fake.Requests("despesas/20250102"); got != 1 {
+		t.Fatalf("day 20250102 requested %d times, want 1", got)
+	}
+}
+
+// newTestDatabase creates a fresh database on the server at serverAddr, applies
+// every migration and drops it when the test ends.
+func newTestDatabase(t *testing.T, serverAddr string) (string, *sqlx.DB) {
+	t.Helper()
+
+	admin, err := sqlx.Connect("postgres", serverAddr)
+	if err != nil {
+		t.Fatalf("failed to connect to %s: %v", serverAddr, err)
+	}
+	name := "etl_e2e_" + time.Now().Format("20060102150405")
+	if _, err := admin.Exec("CREATE DATABASE " + name); err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	u, err := url.Parse(serverAddr)
+	if err != nil {
+		t.Fatalf("invalid E2E_DB_ADDR: %v", err)
+	}
+	u.Path = "/" + name
+	addr := u.String()
+
+	database, err := sqlx.Connect("postgres", addr)
+	if err != nil {
+		t.Fatalf("failed to connect to %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		database.Close()
+		admin.Exec("DROP DATABASE IF EXISTS " + name + " WITH (FORCE)")
+		admin.Close()
+	})
+
+	migrations, err := filepath.Glob("../migrate/migrations/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, path := range migrations {
+		query, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if _, err := database.Exec(string(query)); err != nil {
+			t.Fatalf("failed to apply %s: %v", filepath.Base(path), err)
+		}
+	}
+	return addr, database
+}
+
+type etlRunner struct {
+	binary string
+	dir    string
+	env    []string
+}
+
+// newETLRunner builds the etl binary and runs it from a scratch directory
+// against the given database and portal.
+func newETLRunner(t *testing.T, dbAddr, portalURL string) *etlRunner {
+	t.Helper()
+
+	binary := filepath.Join(t.TempDir(), "etl")
+	build := exec.Command("go", "build", "-o", binary, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build etl: %v\n%s", err, out)
+	}
+	return &etlRunner{
+		binary: binary,
+		dir:    t.TempDir(),
+		env:    append(os.Environ(), "DB_ADDR="+dbAddr, "PORTAL_URL="+portalURL, "SOURCE_BACKEND=portal"),
+	}
+}
+
+func (r *etlRunner) run(t *testing.T, args ...string) {
+	t.Helper()
+	cmd := exec.Command(r.binary, append([]string{"-concurrency", "2"}, args...)...)
+	cmd.Dir = r.dir
+	cmd.Env = r.env
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("etl %v failed: %v\n%s", args, err, out)
+	}
+}
+
+// start runs etl in the background; the test must wait for it.
+func (r *etlRunner) start(t *testing.T, args ...string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(r.binary, append([]string{"-concurrency", "2"}, args...)...)
+	cmd.Dir = r.dir
+	cmd.Env = r.env
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start etl %v: %v", args, err)
+	}
+	return cmd
+}
+
+// serve starts `etl serve` in the background; the test must stop it.
+func (r *etlRunner) serve(t *testing.T, args ...string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(r.binary, append([]string{"serve", "-concurrency", "2"}, args...)...)
+	cmd.Dir = r.dir
+	cmd.Env = r.env
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start etl serve %v: %v", args, err)
+	}
+	t.Cleanup(func() { cmd.Process.Kill() })
+	return cmd
+}
+
+// waitFor polls cond until it holds or timeout passes.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met after %s", timeout)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func assertCount(t *testing.T, database *sqlx.DB, table string, want int) {
+	t.Helper()
+	var got int
+	if err := database.Get(&got, "SELECT COUNT(*) FROM "+table); err != nil {
+		t.Fatalf("failed to count %s: %v", table, err)
+	}
+	if got != want {
+		t.Fatalf("%s has %d rows, want %d", table, got, want)
+	}
+}
+
+// assertStatuses checks the latest ingestion_history status of each reference date.
+func assertStatuses(t *testing.T, database *sqlx.DB, dataset string, want map[string]string) {
+	t.Helper()
+	var rows []struct {
+		ReferenceDate time.Time `db:"reference_date"`
+		Status        string    `db:"status"`
+	}
+	err := database.Select(&rows, `
+		SELECT DISTINCT ON (reference_date) reference_date, status
+		FROM ingestion_history
+		WHERE dataset = $1
+		ORDER BY reference_date, processed_at DESC, id DESC`, dataset)
+	if err != nil {
+		t.Fatalf("failed to read ingestion history: %v", err)
+	}
+
+	got := make(map[string]string, len(rows))
+	for _, row := range rows {
+		got[row.ReferenceDate.Format(time.DateOnly)] = row.Status
+	}
+	for date, status := range want {
+		if got[date] != status {
+			t.Fatalf("%s %s status = %q, want %q (all: %v)", dataset, date, got[date], status, got)
+		}
+	}
+}
+
+// assertCodeOutcomes checks the per-code outcomes of the latest ingestion of a reference date.
+func assertCodeOutcomes(t *testing.T, database *sqlx.DB, dataset, date string, want model.CodeOutcomes) {
+	t.Helper()
+	var got model.CodeOutcomes
+	err := database.Get(&got, `
+		SELECT code_outcomes
+		FROM ingestion_history
+		WHERE dataset = $1 AND reference_date = $2
+		ORDER BY processed_at DESC, id DESC
+		LIMIT 1`, dataset, date)
+	if err != nil {
+		t.Fatalf("failed to read code outcomes: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s %s code outcomes = %+v, want %+v", dataset, date, got, want)
+	}
+}
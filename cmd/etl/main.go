@@ -6,13 +6,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"runtime"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/farxc/envelopa-transparencia/internal/application"
-	"github.com/farxc/envelopa-transparencia/internal/domain/model"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/client/portal"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/db"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/env"
@@ -21,7 +22,31 @@ import (
 )
 
 type config struct {
-	db dbConfig
+	db                dbConfig
+	source            portal.SourceConfig
+	zipDir            string
+	schemaDriftPolicy string
+	layoutDir         string
+	queue             queueConfig
+}
+
+// queueConfig selects where orchestrators keep their jobs: "memory" (default)
+// or "postgres", the durable pipeline_jobs queue shared by every ETL process.
+type queueConfig struct {
+	backend string
+	opts    application.DurableQueueOptions
+}
+
+// durableQueue returns the durable queue options, or nil for the in-memory queue.
+func (c queueConfig) durableQueue() (*application.DurableQueueOptions, error) {
+	switch c.backend {
+	case "memory", "":
+		return nil, nil
+	case "postgres":
+		return &c.opts, nil
+	default:
+		return nil, fmt.Errorf("unknown queue backend %q (valid: memory, postgres)", c.backend)
+	}
 }
 
 type dbConfig struct {
@@ -94,13 +119,12 @@ func (m *MemoryMonitor) Stop() ProfilerStats {
 
 func createTmpDirs(appLogger *logger.Logger) error {
 	const component = "TempDirCreator"
-	dirs := []string{"tmp", "tmp/zips", "tmp/data", "tmp/zips/expenses_execution", "tmp/zips/expenses", "tmp/data/expenses_execution", "tmp/data/expenses"}
+	// Zip directories are created by the portal client as archives are fetched.
+	dirs := []string{"tmp", "tmp/data", "tmp/data/expenses_execution", "tmp/data/expenses"}
 	for _, dir := range dirs {
-		if _, err := os.Stat(dir); os.IsNotExist(err) {
-			err := os.Mkdir(dir, os.ModePerm)
-			if err != nil {
-				return err
-			}
+		// MkdirAll, since runs started together may create them at once.
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
 		}
 	}
 	appLogger.Info(component, "Temporary directories created or already exist: dirs=%v", dirs)
@@ -127,6 +151,31 @@ func main() {
 			maxIdleConns: env.GetInt("DB_MAX_IDLE_CONNS", 25),
 			maxIdleTime:  env.GetString("DB_MAX_IDLE_TIME", "15m"),
 		},
+		source: portal.SourceConfig{
+			Backend:   env.GetString("SOURCE_BACKEND", "portal"),
+			PortalURL: env.GetString("PORTAL_URL", portal.PortalTransparenciaURL),
+			Dir:       env.GetString("SOURCE_DIR", ""),
+			Mirror:    env.GetString("SOURCE_MIRROR", "false") == "true",
+			S3: portal.S3Config{
+				Endpoint:  env.GetString("S3_ENDPOINT", "http://localhost:9000"),
+				Bucket:    env.GetString("S3_BUCKET", "transparencia"),
+				Prefix:    env.GetString("S3_PREFIX", ""),
+				Region:    env.GetString("S3_REGION", "us-east-1"),
+				AccessKey: env.GetString("S3_ACCESS_KEY", ""),
+				SecretKey: env.GetString("S3_SECRET_KEY", ""),
+			},
+		},
+		zipDir:            env.GetString("ZIP_DIR", portal.DefaultZipDir),
+		schemaDriftPolicy: env.GetString("SCHEMA_DRIFT_POLICY", string(portal.DriftFail)),
+		layoutDir:         env.GetString("LAYOUT_DIR", ""),
+		queue: queueConfig{
+			backend: env.GetString("QUEUE_BACKEND", "memory"),
+			opts: application.DurableQueueOptions{
+				Visibility:   env.GetDuration("QUEUE_VISIBILITY_TIMEOUT", 10*time.Minute),
+				PollInterval: env.GetDuration("QUEUE_POLL_INTERVAL", 2*time.Second),
+				RetryDelay:   env.GetDuration("QUEUE_RETRY_DELAY", 30*time.Second),
+			},
+		},
 	}
 
 	database, err := db.New(
@@ -143,20 +192,63 @@ func main() {
 
 	storage := store.NewStorage(database)
 	loader := store.NewStorageLoader(storage, appLogger)
-	ctx := context.Background()
+	// SIGINT and SIGTERM cancel ctx: downloads and transactions in flight are
+	// abandoned and the interrupted jobs recorded as FAILURE, for the next run
+	// to resume.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// `etl serve` runs the schedules in-process instead of a single date range.
+	serveMode := len(os.Args) > 1 && os.Args[1] == "serve"
+	if serveMode {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
 
-	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
-	initDatePtr := flag.String("init", yesterday, "Initial date for data extraction")
-	endDatePtr := flag.String("end", yesterday, "End date for data extraction")
+	defaultDate := yesterday(time.Now()).Format(time.DateOnly)
+	initDatePtr := flag.String("init", defaultDate, "Initial date for data extraction")
+	endDatePtr := flag.String("end", defaultDate, "End date for data extraction")
 	byManagingCodePtr := flag.Bool("byManagingCode", false, "Extract data by managing code or managing unit code")
 	triggerPtr := flag.String("trigger", "MANUAL", "Trigger source: MANUAL, SCHEDULED")
-	kindPtr := flag.String("kind", "expenses_execution", "Kind of data to extract: expenses_execution, expenses")
+	kindPtr := flag.String("kind", "expenses_execution", "Kind of data to extract: expenses_execution, expenses, contracts, licitacoes, convenios, emendas, cpgf, sancoes, receitas, viagens")
 	codesPtr := flag.String("codes", "158454,158148,158341,158342,158343,158345,158376,158332,158533,158635,158636", "Comma-separated list of Unit Codes to extract")
 	logLevelPtr := flag.String("loglevel", "info", "Log level: debug, info, warn, error")
 	concurrencyPtr := flag.Int("concurrency", 10, "Number of concurrent workers")
-	debugPtr := flag.Bool("debug", false, "Debug mode: saves matched dataframes to CSV and bypasses ingestion history checks")
+	debugPtr := flag.Bool("debug", false, "Debug mode: saves matched rows to CSV and bypasses ingestion history checks")
+	recheckPtr := flag.Bool("recheck", false, "Recheck mode: conditionally re-request processed files and re-ingest the ones republished upstream")
+	schedulesPtr := flag.String("schedules", strings.Join(scheduledKinds, ","), "serve: comma-separated kinds to run on their schedule (expenses daily, expenses_execution monthly)")
+	intervalPtr := flag.Duration("interval", time.Hour, "serve: how often the schedules are checked for due periods")
+	catchupPtr := flag.Int("catchupDays", 35, "serve: how many days back missed periods are caught up")
+	queuePtr := flag.Bool("queue", true, "serve: also run the ingestion jobs queued through the API")
+	pollPtr := flag.Duration("poll", 10*time.Second, "serve: how often the ingestion queue is checked for new jobs")
 	flag.Parse()
-	transparency_portal_client := portal.NewTransparencyClient(appLogger, *debugPtr)
+	source, err := portal.NewSource(appLogger, cfg.source)
+	if err != nil {
+		appLogger.Fatal(component, "Invalid archive source: backend=%s error=%v", cfg.source.Backend, err)
+		return
+	}
+	driftPolicy, err := portal.ParseDriftPolicy(cfg.schemaDriftPolicy)
+	if err != nil {
+		appLogger.Fatal(component, "Invalid schema drift policy: error=%v", err)
+		return
+	}
+	durableQueue, err := cfg.queue.durableQueue()
+	if err != nil {
+		appLogger.Fatal(component, "Invalid queue backend: error=%v", err)
+		return
+	}
+	if cfg.layoutDir != "" {
+		loaded, err := portal.LoadLayouts(cfg.layoutDir)
+		if err != nil {
+			appLogger.Fatal(component, "Invalid column layouts: dir=%s error=%v", cfg.layoutDir, err)
+			return
+		}
+		appLogger.Info(component, "Column layouts loaded: dir=%s versions=%d", cfg.layoutDir, loaded)
+	}
+	transparency_portal_client := portal.NewTransparencyClient(appLogger, source, portal.ClientOptions{
+		ZipDir:      cfg.zipDir,
+		Debug:       *debugPtr,
+		DriftPolicy: driftPolicy,
+	})
 
 	// Set log level based on flag
 	switch strings.ToLower(*logLevelPtr) {
@@ -193,6 +285,25 @@ func main() {
 		return
 	}
 
+	if serveMode {
+		err = serve(ctx, transparency_portal_client, loader, storage, appLogger, serveConfig{
+			kinds:          strings.Split(*schedulesPtr, ","),
+			interval:       *intervalPtr,
+			catchupDays:    *catchupPtr,
+			concurrency:    *concurrencyPtr,
+			codes:          codesArr,
+			isManagingCode: isManagingCode,
+			queue:          *queuePtr,
+			pollInterval:   *pollPtr,
+			jobVisibility:  cfg.queue.opts.Visibility,
+			durableQueue:   durableQueue,
+		})
+		if err != nil {
+			appLogger.Fatal(component, "Scheduler failed: error=%v", err)
+		}
+		return
+	}
+
 	init_parsed_date, err := time.Parse(time.DateOnly, init_date)
 	if err != nil {
 		appLogger.Fatal(component, "Invalid init date format: date=%s error=%v", init_date, err)
@@ -204,75 +315,32 @@ func main() {
 		return
 	}
 
-	// Initialize and run the orchestrator for the requested extraction kind.
-	switch *kindPtr {
-
-	case "expenses":
-		pipeline := application.NewExpensesDailyPipeline(transparency_portal_client, loader, appLogger)
-		orch := application.NewOrchestrator(pipeline, storage.IngestionHistory, appLogger, *concurrencyPtr)
-
-		start, end := pipeline.HistoryRange(init_parsed_date, end_parsed_date)
-		if err = orch.InitializeState(ctx, start, end, codesArr); err != nil {
-			appLogger.Fatal(component, "Failed to initialize orchestrator state: error=%v", err)
-			return
-		}
-
-		orch.Start(ctx)
-
-		for d := init_parsed_date; !d.After(end_parsed_date); d = d.AddDate(0, 0, 1) {
-			job := model.ExpensesDailyJob{
-				Date:           d,
-				Codes:          codesArr,
-				IsManagingCode: isManagingCode,
-				Trigger:        *triggerPtr,
-			}
-			if *debugPtr || orch.ShouldProcess(pipeline.StatusKey(job)) {
-				orch.AddJob(job)
-			} else {
-				appLogger.Info(component, "Skipping date (already processed or active): date=%s", d.Format(time.DateOnly))
-			}
-		}
-
-		orch.Close()
-		orch.Wait()
-
-	case "expenses_execution":
-		pipeline := application.NewExpensesExecutionPipeline(transparency_portal_client, loader, appLogger)
-		orch := application.NewOrchestrator(pipeline, storage.IngestionHistory, appLogger, *concurrencyPtr)
-
-		start, end := pipeline.HistoryRange(init_parsed_date, end_parsed_date)
-		if err = orch.InitializeState(ctx, start, end, codesArr); err != nil {
-			appLogger.Fatal(component, "Failed to initialize orchestrator state: error=%v", err)
-			return
-		}
-
-		orch.Start(ctx)
-
-		startMonth := time.Date(init_parsed_date.Year(), init_parsed_date.Month(), 1, 0, 0, 0, 0, init_parsed_date.Location())
-		endMonth := time.Date(end_parsed_date.Year(), end_parsed_date.Month(), 1, 0, 0, 0, 0, end_parsed_date.Location())
-		for m := startMonth; !m.After(endMonth); m = m.AddDate(0, 1, 0) {
-			job := model.ExpensesExecutionJob{
-				Year:           m.Format("2006"),
-				Month:          m.Format("01"),
-				Codes:          codesArr,
-				IsManagingCode: isManagingCode,
-				Trigger:        *triggerPtr,
-			}
-			if *debugPtr || orch.ShouldProcess(pipeline.StatusKey(job)) {
-				orch.AddJob(job)
-			} else {
-				appLogger.Info(component, "Skipping month (already processed or active): month=%s-%s", job.Year, job.Month)
-			}
-		}
-
-		orch.Close()
-		orch.Wait()
+	runCfg := runConfig{
+		concurrency:    *concurrencyPtr,
+		debug:          *debugPtr,
+		codes:          codesArr,
+		isManagingCode: isManagingCode,
+		trigger:        *triggerPtr,
+		startDate:      init_parsed_date,
+		endDate:        end_parsed_date,
+		durableQueue:   durableQueue,
+	}
+	if *recheckPtr {
+		runCfg.recheck = transparency_portal_client.RecheckSource
+	}
 
-	default:
-		appLogger.Fatal(component, "Unknown extraction kind: kind=%s (valid: expenses, expenses_execution)", *kindPtr)
+	// Initialize and run the orchestrator for the requested extraction kind.
+	err = runKind(ctx, *kindPtr, transparency_portal_client, loader, storage, appLogger, runCfg)
+	if err != nil && ctx.Err() != nil {
+		appLogger.Warn(component, "Run interrupted, the next run resumes it: kind=%s duration=%.2f minutes", *kindPtr, time.Since(starting_time).Minutes())
+		return
+	}
+	if err != nil {
+		appLogger.Fatal(component, "Failed to run pipeline: kind=%s error=%v", *kindPtr, err)
 		return
 	}
 
 	timeTaken := time.Since(starting_time)
-	appLogger.Info(component, "Application completed successfully: duration=%.2f minutes", timeTaken.Minutes())
+	stats := monitor.Stop()
+	appLogger.Info(component, "Application completed successfully: duration=%.2f minutes peakMemoryMB=%d peakGoroutines=%d", timeTaken.Minutes(), stats.PeakMemoryMB, stats.PeakGoroutines)
 }
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/application"
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/repository"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+)
+
+// maxJobAttempts is how many times an ingestion job is claimed before a
+// worker that keeps dying on it fails it for good.
+const maxJobAttempts = 3
+
+// processQueue runs the ingestion jobs queued through the API, oldest first
+// and one at a time, until ctx is cancelled. Cancelling ctx also interrupts
+// the job in progress, which goes back to the queue for the next worker.
+func processQueue(ctx context.Context, client service.TransparencyPortalClient, loader service.Loader, storage *store.Storage, appLogger *logger.Logger, cfg serveConfig) {
+	const component = "Queue"
+	appLogger.Info(component, "Processing queued ingestion jobs: pollInterval=%s", cfg.pollInterval)
+
+	for ctx.Err() == nil {
+		job, err := storage.IngestionJob.ClaimJob(ctx, cfg.jobVisibility, maxJobAttempts)
+		if err != nil {
+			appLogger.Error(component, "Failed to claim job: err=%v", err)
+		}
+		if job != nil {
+			runQueuedJob(ctx, job, client, loader, storage, appLogger, cfg)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(cfg.pollInterval):
+		}
+	}
+}
+
+func runQueuedJob(ctx context.Context, job *model.IngestionJob, client service.TransparencyPortalClient, loader service.Loader, storage *store.Storage, appLogger *logger.Logger, cfg serveConfig) {
+	const component = "Queue"
+	appLogger.Info(component, "Running job: id=%d kind=%s range=%s to %s attempt=%d", job.ID, job.Kind, job.StartDate.Format(time.DateOnly), job.EndDate.Format(time.DateOnly), job.Attempts)
+
+	stopHeartbeat := heartbeat(ctx, job, storage, appLogger, cfg.jobVisibility)
+	runErr := runKind(application.WithIngestionJob(ctx, job.ID), job.Kind, client, loader, storage, appLogger, runConfig{
+		concurrency:    cfg.concurrency,
+		codes:          job.Codes,
+		isManagingCode: job.ScopeType == store.ScopeTypeManagement,
+		trigger:        job.TriggerType,
+		startDate:      job.StartDate,
+		endDate:        job.EndDate,
+		durableQueue:   cfg.durableQueue,
+	})
+	stopHeartbeat()
+
+	// The job is settled even when ctx was cancelled, so it does not stay
+	// RUNNING after a shutdown.
+	flushCtx := context.WithoutCancel(ctx)
+	if ctx.Err() != nil {
+		// Its periods recorded what they loaded, so the next claim resumes it.
+		if err := storage.IngestionJob.ReleaseJob(flushCtx, job.ID, job.Attempts, "interrupted"); errors.Is(err, repository.ErrJobLost) {
+			appLogger.Warn(component, "Job was claimed again by another worker, leaving it: id=%d attempt=%d", job.ID, job.Attempts)
+			return
+		} else if err != nil {
+			appLogger.Error(component, "Failed to release job: id=%d err=%v", job.ID, err)
+			return
+		}
+		appLogger.Warn(component, "Job interrupted, queued again: id=%d", job.ID)
+		return
+	}
+
+	status, lastError := store.StatusFailure, ""
+	if runErr != nil {
+		lastError = runErr.Error()
+	} else if finished, err := storage.IngestionJob.GetJob(flushCtx, job.ID); err != nil {
+		lastError = fmt.Sprintf("failed to read job ingestions: %v", err)
+	} else {
+		status, lastError = summarizeIngestions(finished.Ingestions)
+	}
+
+	if err := storage.IngestionJob.FinishJob(flushCtx, job.ID, job.Attempts, status, lastError); errors.Is(err, repository.ErrJobLost) {
+		appLogger.Warn(component, "Job was claimed again by another worker, leaving it: id=%d attempt=%d status=%s", job.ID, job.Attempts, status)
+		return
+	} else if err != nil {
+		appLogger.Error(component, "Failed to finish job: id=%d status=%s err=%v", job.ID, status, err)
+		return
+	}
+	appLogger.Info(component, "Job finished: id=%d status=%s", job.ID, status)
+}
+
+// heartbeat extends the job's visibility every third of the period until the
+// returned func is called, so only a dead worker's job is claimed again.
+func heartbeat(ctx context.Context, job *model.IngestionJob, storage *store.Storage, appLogger *logger.Logger, visibility time.Duration) context.CancelFunc {
+	const component = "Queue"
+	return application.RenewEvery(ctx, visibility/3, func(ctx context.Context) {
+		if err := storage.IngestionJob.ExtendJob(ctx, job.ID, job.Attempts, visibility); err != nil && ctx.Err() == nil {
+			appLogger.Warn(component, "Failed to extend job visibility: id=%d err=%v", job.ID, err)
+		}
+	})
+}
+
+// summarizeIngestions derives a job's status from the latest ingestion of each
+// of its periods: FAILURE when every period failed, PARTIAL when some failed
+// or were partial, SUCCESS otherwise (periods already processed are skipped
+// and leave no ingestion). The error lists the failed periods.
+func summarizeIngestions(ingestions []model.IngestionHistory) (string, string) {
+	latest := make(map[string]model.IngestionHistory)
+	var periods []string
+	for _, h := range ingestions {
+		period := h.ReferenceDate.Format(time.DateOnly)
+		existing, ok := latest[period]
+		if !ok {
+			periods = append(periods, period)
+		}
+		if !ok || !h.ProcessedAt.Before(existing.ProcessedAt) {
+			latest[period] = h
+		}
+	}
+
+	var failed []string
+	partial := false
+	for _, period := range periods {
+		switch latest[period].Status {
+		case store.StatusFailure, store.StatusInProgress:
+			failed = append(failed, period)
+		case store.StatusPartial:
+			partial = true
+		}
+	}
+
+	switch {
+	case len(failed) > 0 && len(failed) == len(periods):
+		return store.StatusFailure, "every period failed: " + strings.Join(failed, ", ")
+	case len(failed) > 0:
+		return store.StatusPartial, fmt.Sprintf("%d of %d periods failed: %s", len(failed), len(periods), strings.Join(failed, ", "))
+	case partial:
+		return store.StatusPartial, ""
+	default:
+		return store.StatusSuccess, ""
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/application"
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+)
+
+type runConfig struct {
+	concurrency int
+	debug       bool
+	codes       []int64
+	// isManagingCode matches codes on the management instead of the unit.
+	isManagingCode bool
+	trigger        string
+	startDate      time.Time
+	endDate        time.Time
+	// recheck, when set, re-ingests processed keys whose source files were
+	// republished upstream.
+	recheck application.SourceChecker
+	// durableQueue, when set, keeps the orchestrator's jobs in pipeline_jobs.
+	durableQueue *application.DurableQueueOptions
+}
+
+// newOrchestrator builds the orchestrator of pipeline, on the durable queue
+// when durableQueue is set.
+func newOrchestrator[J any](pipeline application.Pipeline[J], storage *store.Storage, appLogger *logger.Logger, concurrency int, durableQueue *application.DurableQueueOptions) *application.Orchestrator[J] {
+	orch := application.NewOrchestrator(pipeline, storage.IngestionHistory, storage.IngestionRejection, storage.IngestionLease, appLogger, concurrency)
+	if durableQueue != nil {
+		orch.UseDurableQueue(storage.PipelineJob, *durableQueue)
+	}
+	return orch
+}
+
+// runPipeline drives jobs through a generic orchestrator, skipping the ones
+// ingestion_history already marks as processed (unless in debug mode) and
+// narrowing partially loaded ones to the codes still missing.
+func runPipeline[J any](ctx context.Context, pipeline application.Pipeline[J], storage *store.Storage, appLogger *logger.Logger, cfg runConfig, jobs []J) error {
+	const component = "Main"
+	orch := newOrchestrator(pipeline, storage, appLogger, cfg.concurrency, cfg.durableQueue)
+
+	start, end := pipeline.HistoryRange(cfg.startDate, cfg.endDate)
+	if err := orch.InitializeState(ctx, start, end, cfg.codes); err != nil {
+		return err
+	}
+	if cfg.recheck != nil {
+		orch.Recheck(ctx, cfg.recheck)
+	}
+
+	orch.Start(ctx)
+
+	for _, job := range jobs {
+		key := pipeline.StatusKey(job)
+		switch {
+		case cfg.debug:
+			orch.AddJob(ctx, job)
+		case orch.ShouldProcess(key):
+			orch.AddJob(ctx, orch.Resume(job))
+		default:
+			appLogger.Info(component, "Skipping job (already processed or active): dataset=%s key=%s", pipeline.Dataset(), key)
+		}
+	}
+
+	orch.Close()
+	orch.Wait()
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s run interrupted: %w", pipeline.Dataset(), ctx.Err())
+	}
+	return nil
+}
+
+// runKind builds the jobs of kind for the configured range and runs them
+// through the kind's pipeline.
+func runKind(ctx context.Context, kind string, client service.TransparencyPortalClient, loader service.Loader, storage *store.Storage, appLogger *logger.Logger, cfg runConfig) error {
+	switch kind {
+
+	case "expenses":
+		jobs := expensesJobs(cfg.startDate, cfg.endDate, cfg.codes, cfg.isManagingCode, cfg.trigger)
+		pipeline := application.NewExpensesDailyPipeline(client, loader, appLogger)
+		return runPipeline(ctx, pipeline, storage, appLogger, cfg, jobs)
+
+	case "expenses_execution":
+		jobs := expensesExecutionJobs(cfg.startDate, cfg.endDate, cfg.codes, cfg.isManagingCode, cfg.trigger)
+		pipeline := application.NewExpensesExecutionPipeline(client, loader, appLogger)
+		return runPipeline(ctx, pipeline, storage, appLogger, cfg, jobs)
+
+	case "contracts":
+		var jobs []model.ContractsJob
+		for _, m := range monthsBetween(cfg.startDate, cfg.endDate) {
+			jobs = append(jobs, model.ContractsJob{
+				Year:           m.Format("2006"),
+				Month:          m.Format("01"),
+				Codes:          cfg.codes,
+				IsManagingCode: cfg.isManagingCode,
+				Trigger:        cfg.trigger,
+			})
+		}
+		pipeline := application.NewContractsPipeline(client, loader, appLogger)
+		return runPipeline(ctx, pipeline, storage, appLogger, cfg, jobs)
+
+	case "licitacoes":
+		var jobs []model.LicitacaoJob
+		for _, m := range monthsBetween(cfg.startDate, cfg.endDate) {
+			jobs = append(jobs, model.LicitacaoJob{
+				Year:           m.Format("2006"),
+				Month:          m.Format("01"),
+				Codes:          cfg.codes,
+				IsManagingCode: cfg.isManagingCode,
+				Trigger:        cfg.trigger,
+			})
+		}
+		pipeline := application.NewLicitacoesPipeline(client, loader, appLogger)
+		return runPipeline(ctx, pipeline, storage, appLogger, cfg, jobs)
+
+	case "convenios":
+		var jobs []model.AgreementsJob
+		for _, m := range monthsBetween(cfg.startDate, cfg.endDate) {
+			jobs = append(jobs, model.AgreementsJob{
+				Year:           m.Format("2006"),
+				Month:          m.Format("01"),
+				Codes:          cfg.codes,
+				IsManagingCode: cfg.isManagingCode,
+				Trigger:        cfg.trigger,
+			})
+		}
+		pipeline := application.NewAgreementsPipeline(client, loader, appLogger)
+		return runPipeline(ctx, pipeline, storage, appLogger, cfg, jobs)
+
+	case "emendas":
+		// The portal only serves the current snapshot, so a single job is dated with the end date.
+		jobs := []model.AmendmentsJob{{
+			Date:    cfg.endDate,
+			Codes:   cfg.codes,
+			Trigger: cfg.trigger,
+		}}
+		pipeline := application.NewAmendmentsPipeline(client, loader, appLogger)
+		return runPipeline(ctx, pipeline, storage, appLogger, cfg, jobs)
+
+	case "cpgf":
+		var jobs []model.CardExpensesJob
+		for _, m := range monthsBetween(cfg.startDate, cfg.endDate) {
+			jobs = append(jobs, model.CardExpensesJob{
+				Year:    m.Format("2006"),
+				Month:   m.Format("01"),
+				Codes:   cfg.codes,
+				Trigger: cfg.trigger,
+			})
+		}
+		pipeline := application.NewCardExpensesPipeline(client, loader, appLogger)
+		return runPipeline(ctx, pipeline, storage, appLogger, cfg, jobs)
+
+	case "sancoes":
+		// CEIS/CNEP are full daily snapshots; the end date picks which one to load.
+		jobs := []model.SanctionsJob{{
+			Date:    cfg.endDate,
+			Codes:   cfg.codes,
+			Trigger: cfg.trigger,
+		}}
+		pipeline := application.NewSanctionsPipeline(client, loader, appLogger)
+		return runPipeline(ctx, pipeline, storage, appLogger, cfg, jobs)
+
+	case "receitas":
+		var jobs []model.RevenueJob
+		for year := cfg.startDate.Year(); year <= cfg.endDate.Year(); year++ {
+			jobs = append(jobs, model.RevenueJob{
+				Year:    strconv.Itoa(year),
+				Codes:   cfg.codes,
+				Trigger: cfg.trigger,
+			})
+		}
+		pipeline := application.NewRevenuePipeline(client, loader, appLogger)
+		return runPipeline(ctx, pipeline, storage, appLogger, cfg, jobs)
+
+	case "viagens":
+		var jobs []model.TravelJob
+		for year := cfg.startDate.Year(); year <= cfg.endDate.Year(); year++ {
+			jobs = append(jobs, model.TravelJob{
+				Year:    strconv.Itoa(year),
+				Codes:   cfg.codes,
+				Trigger: cfg.trigger,
+			})
+		}
+		pipeline := application.NewTravelPipeline(client, loader, appLogger)
+		return runPipeline(ctx, pipeline, storage, appLogger, cfg, jobs)
+
+	default:
+		return fmt.Errorf("unknown extraction kind %q (valid: %s)", kind, strings.Join(store.Datasets, ", "))
+	}
+
+}
+
+// expensesJobs returns one daily expenses job per day from start to end, inclusive.
+func expensesJobs(start, end time.Time, codes []int64, isManagingCode bool, trigger string) []model.ExpensesDailyJob {
+	var jobs []model.ExpensesDailyJob
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		jobs = append(jobs, model.ExpensesDailyJob{
+			Date:           d,
+			Codes:          codes,
+			IsManagingCode: isManagingCode,
+			Trigger:        trigger,
+		})
+	}
+	return jobs
+}
+
+// expensesExecutionJobs returns one execution job per month from start to end, inclusive.
+func expensesExecutionJobs(start, end time.Time, codes []int64, isManagingCode bool, trigger string) []model.ExpensesExecutionJob {
+	var jobs []model.ExpensesExecutionJob
+	for _, m := range monthsBetween(start, end) {
+		jobs = append(jobs, model.ExpensesExecutionJob{
+			Year:           m.Format("2006"),
+			Month:          m.Format("01"),
+			Codes:          codes,
+			IsManagingCode: isManagingCode,
+			Trigger:        trigger,
+		})
+	}
+	return jobs
+}
+
+// monthsBetween returns the first day of every month from start to end, inclusive.
+func monthsBetween(start, end time.Time) []time.Time {
+	startMonth := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, start.Location())
+	endMonth := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, end.Location())
+
+	var months []time.Time
+	for m := startMonth; !m.After(endMonth); m = m.AddDate(0, 1, 0) {
+		months = append(months, m)
+	}
+	return months
+}
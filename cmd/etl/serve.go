@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/application"
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+)
+
+// scheduledKinds are the kinds `etl serve` can run.
+var scheduledKinds = []string{"expenses", "expenses_execution"}
+
+type serveConfig struct {
+	kinds          []string
+	interval       time.Duration
+	catchupDays    int
+	concurrency    int
+	codes          []int64
+	isManagingCode bool
+	// queue, when set, also runs the ingestion jobs queued through the API,
+	// checking for new ones every pollInterval. A claimed job is hidden from
+	// other workers for jobVisibility past its last heartbeat.
+	queue         bool
+	pollInterval  time.Duration
+	jobVisibility time.Duration
+	// durableQueue, when set, keeps the orchestrators' jobs in pipeline_jobs.
+	durableQueue *application.DurableQueueOptions
+}
+
+// schedule feeds one kind's long-lived orchestrator with the periods that are due.
+type schedule interface {
+	start(ctx context.Context)
+	tick(ctx context.Context, now time.Time)
+	stop()
+}
+
+type periodSchedule[J any] struct {
+	kind        string
+	pipeline    application.Pipeline[J]
+	orch        *application.Orchestrator[J]
+	appLogger   *logger.Logger
+	codes       []int64
+	catchupDays int
+	// due returns the last day whose period can be ingested at now.
+	due func(now time.Time) time.Time
+	// jobs builds the jobs of every period from start to end.
+	jobs func(start, end time.Time) []J
+}
+
+func newPeriodSchedule[J any](kind string, pipeline application.Pipeline[J], storage *store.Storage, appLogger *logger.Logger, cfg serveConfig, due func(time.Time) time.Time, jobs func(start, end time.Time) []J) *periodSchedule[J] {
+	return &periodSchedule[J]{
+		kind:        kind,
+		pipeline:    pipeline,
+		orch:        newOrchestrator(pipeline, storage, appLogger, cfg.concurrency, cfg.durableQueue),
+		appLogger:   appLogger,
+		codes:       cfg.codes,
+		catchupDays: cfg.catchupDays,
+		due:         due,
+		jobs:        jobs,
+	}
+}
+
+func (s *periodSchedule[J]) start(ctx context.Context) {
+	s.orch.Start(ctx)
+}
+
+// tick reloads the history of the catch-up window and adds every period in it
+// that is not processed or already queued, so periods missed while the
+// scheduler was down are picked up with the new ones.
+func (s *periodSchedule[J]) tick(ctx context.Context, now time.Time) {
+	const component = "Scheduler"
+	end := s.due(now)
+	start := end.AddDate(0, 0, -s.catchupDays)
+
+	if err := s.orch.InitializeState(ctx, start, end, s.codes); err != nil {
+		s.appLogger.Error(component, "Failed to sync state: kind=%s err=%v", s.kind, err)
+		return
+	}
+
+	queued := 0
+	for _, job := range s.jobs(start, end) {
+		if s.orch.ShouldProcess(s.pipeline.StatusKey(job)) && s.orch.AddJob(ctx, s.orch.Resume(job)) {
+			queued++
+		}
+	}
+	s.appLogger.Info(component, "Schedule checked: kind=%s range=%s to %s queued=%d", s.kind, start.Format(time.DateOnly), end.Format(time.DateOnly), queued)
+}
+
+func (s *periodSchedule[J]) stop() {
+	s.orch.Close()
+	s.orch.Wait()
+}
+
+// yesterday is the due day of daily files: the portal publishes a day's
+// expenses the day after. Due days follow the local calendar, like the CLI's
+// default dates, and are kept at UTC midnight like the dates it parses.
+func yesterday(now time.Time) time.Time {
+	now = now.Local()
+	return time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+}
+
+// lastMonthEnd is the due day of monthly files: the last day of the previous
+// month, on the local calendar.
+func lastMonthEnd(now time.Time) time.Time {
+	now = now.Local()
+	return time.Date(now.Year(), now.Month(), 0, 0, 0, 0, 0, time.UTC)
+}
+
+// serve runs the configured schedules until ctx is cancelled, checking them on
+// start and then every cfg.interval. Jobs are recorded with the SCHEDULED
+// trigger. With cfg.queue it also works through the API's ingestion queue.
+// Cancelling ctx interrupts the jobs in progress; serve returns once they
+// have recorded their status.
+func serve(ctx context.Context, client service.TransparencyPortalClient, loader service.Loader, storage *store.Storage, appLogger *logger.Logger, cfg serveConfig) error {
+	const component = "Scheduler"
+	trigger := store.TriggerTypeScheduled
+
+	var schedules []schedule
+	for _, kind := range cfg.kinds {
+		switch kind {
+		case "":
+			// -schedules "" leaves only the queue.
+		case "expenses":
+			pipeline := application.NewExpensesDailyPipeline(client, loader, appLogger)
+			schedules = append(schedules, newPeriodSchedule(kind, pipeline, storage, appLogger, cfg, yesterday, func(start, end time.Time) []model.ExpensesDailyJob {
+				return expensesJobs(start, end, cfg.codes, cfg.isManagingCode, trigger)
+			}))
+		case "expenses_execution":
+			pipeline := application.NewExpensesExecutionPipeline(client, loader, appLogger)
+			schedules = append(schedules, newPeriodSchedule(kind, pipeline, storage, appLogger, cfg, lastMonthEnd, func(start, end time.Time) []model.ExpensesExecutionJob {
+				return expensesExecutionJobs(start, end, cfg.codes, cfg.isManagingCode, trigger)
+			}))
+		default:
+			return fmt.Errorf("kind %q cannot be scheduled (valid: %v)", kind, scheduledKinds)
+		}
+	}
+
+	for _, s := range schedules {
+		s.start(ctx)
+	}
+	queueDone := make(chan struct{})
+	if cfg.queue {
+		go func() {
+			defer close(queueDone)
+			processQueue(ctx, client, loader, storage, appLogger, cfg)
+		}()
+	} else {
+		close(queueDone)
+	}
+	appLogger.Info(component, "Scheduler started: kinds=%v interval=%s catchupDays=%d queue=%t", cfg.kinds, cfg.interval, cfg.catchupDays, cfg.queue)
+
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		for _, s := range schedules {
+			s.tick(ctx, now)
+		}
+
+		select {
+		case <-ctx.Done():
+			appLogger.Info(component, "Shutdown requested, waiting for running jobs to stop")
+			for _, s := range schedules {
+				s.stop()
+			}
+			<-queueDone
+			appLogger.Info(component, "Scheduler stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,15 @@
+-- Final beneficiaries of payments made through intermediaries (banks, payroll, ...)
+CREATE TABLE IF NOT EXISTS payment_final_beneficiaries (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    payment_code VARCHAR(255) NOT NULL,
+    final_beneficiary_code VARCHAR(100) NOT NULL,
+    final_beneficiary_name VARCHAR(255),
+    received_value_brl NUMERIC(18, 2) DEFAULT 0,
+    inserted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT payment_final_beneficiaries_pk UNIQUE (payment_code, final_beneficiary_code)
+);
+
+CREATE INDEX idx_payment_final_beneficiaries_payment_code ON payment_final_beneficiaries(payment_code);
+CREATE INDEX idx_payment_final_beneficiaries_final_beneficiary_code ON payment_final_beneficiaries(final_beneficiary_code);
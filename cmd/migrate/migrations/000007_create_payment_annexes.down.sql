@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS payment_court_orders;
+DROP TABLE IF EXISTS payment_invoices;
+DROP TABLE IF EXISTS payment_bank_transfers;
@@ -0,0 +1,49 @@
+-- Bank list (ListaBancos) entries linked to payments
+CREATE TABLE IF NOT EXISTS payment_bank_transfers (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    payment_code VARCHAR(255) NOT NULL,
+    list_code VARCHAR(100) NOT NULL,
+    bank_code VARCHAR(20) NOT NULL,
+    bank_name VARCHAR(255),
+    value_brl NUMERIC(18, 2) DEFAULT 0,
+    inserted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT payment_bank_transfers_pk UNIQUE (payment_code, list_code, bank_code)
+);
+
+CREATE INDEX idx_payment_bank_transfers_payment_code ON payment_bank_transfers(payment_code);
+
+-- Invoice list (ListaFaturas) entries linked to payments
+CREATE TABLE IF NOT EXISTS payment_invoices (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    payment_code VARCHAR(255) NOT NULL,
+    list_code VARCHAR(100) NOT NULL,
+    invoice_number VARCHAR(100) NOT NULL,
+    favored_code VARCHAR(100),
+    favored_name VARCHAR(255),
+    value_brl NUMERIC(18, 2) DEFAULT 0,
+    inserted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT payment_invoices_pk UNIQUE (payment_code, list_code, invoice_number)
+);
+
+CREATE INDEX idx_payment_invoices_payment_code ON payment_invoices(payment_code);
+
+-- Court-ordered payment list (ListaPrecatorios) entries linked to payments
+CREATE TABLE IF NOT EXISTS payment_court_orders (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    payment_code VARCHAR(255) NOT NULL,
+    list_code VARCHAR(100) NOT NULL,
+    process_number VARCHAR(100) NOT NULL,
+    favored_code VARCHAR(100),
+    favored_name VARCHAR(255),
+    value_brl NUMERIC(18, 2) DEFAULT 0,
+    inserted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT payment_court_orders_pk UNIQUE (payment_code, list_code, process_number)
+);
+
+CREATE INDEX idx_payment_court_orders_payment_code ON payment_court_orders(payment_code);
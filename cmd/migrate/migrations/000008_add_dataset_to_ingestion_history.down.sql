@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_ingest_dataset_ref_date;
+ALTER TABLE ingestion_history DROP COLUMN IF EXISTS dataset;
@@ -0,0 +1,7 @@
+-- Each pipeline keeps its own state in ingestion_history
+ALTER TABLE ingestion_history ADD COLUMN dataset VARCHAR(50) NOT NULL DEFAULT 'expenses';
+
+-- Monthly execution runs were recorded with source files like 202501_despesas.zip
+UPDATE ingestion_history SET dataset = 'expenses_execution' WHERE source_file ~ '^\d{6}_despesas\.zip$';
+
+CREATE INDEX idx_ingest_dataset_ref_date ON ingestion_history (dataset, reference_date);
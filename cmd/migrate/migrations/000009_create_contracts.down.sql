@@ -0,0 +1,4 @@
+DROP VIEW IF EXISTS contract_commitments;
+DROP INDEX IF EXISTS idx_commitments_process_digits;
+DROP TABLE IF EXISTS contract_items;
+DROP TABLE IF EXISTS contracts;
@@ -0,0 +1,62 @@
+CREATE TABLE IF NOT EXISTS contracts (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    contract_number         VARCHAR(50)     NOT NULL,
+    object                  TEXT,
+    legal_basis             TEXT,
+    purchase_modality       VARCHAR(255),
+    situation               VARCHAR(100),
+    superior_organ_code     INTEGER,
+    superior_organ_name     VARCHAR(255),
+    organ_code              INTEGER,
+    organ_name              VARCHAR(255),
+    management_unit_code    INTEGER         NOT NULL,
+    management_unit_name    VARCHAR(255),
+    process                 VARCHAR(100),
+    signature_date          DATE,
+    publication_date        DATE,
+    start_date              DATE,
+    end_date                DATE,
+    contractor_code         VARCHAR(50),
+    contractor_name         VARCHAR(255),
+    initial_value           NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    final_value             NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    bidding_number          VARCHAR(50),
+    reference_month         VARCHAR(10)     NOT NULL,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_contracts_number_unit UNIQUE (contract_number, management_unit_code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_contracts_unit_signature ON contracts (management_unit_code, signature_date);
+
+CREATE TABLE IF NOT EXISTS contract_items (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    contract_number         VARCHAR(50)     NOT NULL,
+    management_unit_code    INTEGER         NOT NULL,
+    item_code               VARCHAR(50)     NOT NULL,
+    description             TEXT,
+    complement_description  TEXT,
+    quantity                NUMERIC(18, 4)  NOT NULL DEFAULT 0,
+    value                   NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_contract_items UNIQUE (contract_number, management_unit_code, item_code),
+    CONSTRAINT fk_contract_items_contract FOREIGN KEY (contract_number, management_unit_code)
+        REFERENCES contracts (contract_number, management_unit_code) ON DELETE CASCADE
+);
+
+-- Contracts and commitments share the administrative process number, written
+-- with different punctuation in each file, so they are matched on its digits.
+CREATE INDEX IF NOT EXISTS idx_contracts_process_digits ON contracts ((regexp_replace(process, '\D', '', 'g')), management_unit_code);
+CREATE INDEX IF NOT EXISTS idx_commitments_process_digits ON commitments ((regexp_replace(process, '\D', '', 'g')), management_unit_code);
+
+CREATE OR REPLACE VIEW contract_commitments AS
+SELECT
+    ct.contract_number,
+    ct.management_unit_code,
+    c.commitment_code
+FROM contracts ct
+JOIN commitments c
+    ON regexp_replace(c.process, '\D', '', 'g') = regexp_replace(ct.process, '\D', '', 'g')
+   AND c.management_unit_code = ct.management_unit_code
+WHERE regexp_replace(ct.process, '\D', '', 'g') <> '';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS bidding_participants;
+DROP TABLE IF EXISTS bidding_items;
+DROP TABLE IF EXISTS biddings;
+
+ALTER TABLE commitments DROP COLUMN IF EXISTS bidding_modality;
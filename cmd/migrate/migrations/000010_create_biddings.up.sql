@@ -0,0 +1,64 @@
+ALTER TABLE commitments ADD COLUMN IF NOT EXISTS bidding_modality VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS biddings (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    bidding_number          VARCHAR(50)     NOT NULL,
+    management_unit_code    INTEGER         NOT NULL,
+    management_unit_name    VARCHAR(255),
+    modality_code           SMALLINT        NOT NULL,
+    modality                VARCHAR(255),
+    process                 VARCHAR(100),
+    object                  TEXT,
+    situation               VARCHAR(100),
+    superior_organ_code     INTEGER,
+    superior_organ_name     VARCHAR(255),
+    organ_code              INTEGER,
+    organ_name              VARCHAR(255),
+    federative_unit         VARCHAR(10),
+    municipality            VARCHAR(255),
+    result_date             DATE,
+    opening_date            DATE,
+    value                   NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    reference_month         VARCHAR(10)     NOT NULL,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_biddings UNIQUE (bidding_number, management_unit_code, modality_code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_biddings_unit_opening ON biddings (management_unit_code, opening_date);
+
+CREATE TABLE IF NOT EXISTS bidding_items (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    bidding_number          VARCHAR(50)     NOT NULL,
+    management_unit_code    INTEGER         NOT NULL,
+    modality_code           SMALLINT        NOT NULL,
+    item_code               VARCHAR(50)     NOT NULL,
+    description             TEXT,
+    quantity                NUMERIC(18, 4)  NOT NULL DEFAULT 0,
+    value                   NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    winner_code             VARCHAR(50),
+    winner_name             VARCHAR(255),
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_bidding_items UNIQUE (bidding_number, management_unit_code, modality_code, item_code),
+    CONSTRAINT fk_bidding_items_bidding FOREIGN KEY (bidding_number, management_unit_code, modality_code)
+        REFERENCES biddings (bidding_number, management_unit_code, modality_code) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS bidding_participants (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    bidding_number          VARCHAR(50)     NOT NULL,
+    management_unit_code    INTEGER         NOT NULL,
+    modality_code           SMALLINT        NOT NULL,
+    item_code               VARCHAR(50)     NOT NULL,
+    participant_code        VARCHAR(50)     NOT NULL,
+    participant_name        VARCHAR(255),
+    winner                  BOOLEAN         NOT NULL DEFAULT FALSE,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_bidding_participants UNIQUE (bidding_number, management_unit_code, modality_code, item_code, participant_code),
+    CONSTRAINT fk_bidding_participants_bidding FOREIGN KEY (bidding_number, management_unit_code, modality_code)
+        REFERENCES biddings (bidding_number, management_unit_code, modality_code) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_bidding_participants_code ON bidding_participants (participant_code);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS agreement_disbursements;
+DROP TABLE IF EXISTS agreements;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS agreements (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    agreement_number        VARCHAR(50)     NOT NULL,
+    process_number          VARCHAR(100),
+    instrument_type         VARCHAR(100),
+    situation               VARCHAR(100),
+    object                  TEXT,
+    superior_organ_code     INTEGER,
+    superior_organ_name     VARCHAR(255),
+    grantor_code            INTEGER         NOT NULL,
+    grantor_name            VARCHAR(255),
+    grantee_code            VARCHAR(50),
+    grantee_name            VARCHAR(255),
+    grantee_type            VARCHAR(100),
+    federative_unit         VARCHAR(10),
+    municipality_code       VARCHAR(20),
+    municipality_name       VARCHAR(255),
+    agreement_value         NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    released_value          NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    counterpart_value       NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    publication_date        DATE,
+    start_date              DATE,
+    end_date                DATE,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_agreements_number UNIQUE (agreement_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_agreements_grantor_start ON agreements (grantor_code, start_date);
+
+CREATE TABLE IF NOT EXISTS agreement_disbursements (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    agreement_number        VARCHAR(50)     NOT NULL REFERENCES agreements (agreement_number) ON DELETE CASCADE,
+    release_date            DATE            NOT NULL,
+    value                   NUMERIC(18, 2)  NOT NULL,
+    reference_month         VARCHAR(10)     NOT NULL,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_agreement_disbursements UNIQUE (agreement_number, release_date, value)
+);
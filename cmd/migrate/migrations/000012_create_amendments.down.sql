@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_expenses_execution_author;
+DROP TABLE IF EXISTS amendments;
+DROP TABLE IF EXISTS amendment_authors;
@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS amendment_authors (
+    author_code             INTEGER         PRIMARY KEY,
+    author_name             VARCHAR(255),
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW()
+);
+
+-- Authors already known from the execution data.
+INSERT INTO amendment_authors (author_code, author_name)
+SELECT author_amendament_code, MAX(author_amendament_name)
+FROM expenses_execution
+WHERE author_amendament_code <> 0
+GROUP BY author_amendament_code
+ON CONFLICT (author_code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS amendments (
+    id                              BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    amendment_code                  VARCHAR(50)     NOT NULL,
+    year                            SMALLINT        NOT NULL,
+    type                            VARCHAR(100),
+    author_code                     INTEGER         NOT NULL REFERENCES amendment_authors (author_code),
+    amendment_number                VARCHAR(50),
+    locality                        VARCHAR(255),
+    municipality_code               VARCHAR(20),
+    municipality                    VARCHAR(255),
+    federative_unit                 VARCHAR(10),
+    function_code                   SMALLINT,
+    function_name                   VARCHAR(255),
+    subfunction_code                SMALLINT,
+    subfunction_name                VARCHAR(255),
+    program_code                    INTEGER,
+    program_name                    VARCHAR(255),
+    action_code                     VARCHAR(10),
+    action_name                     VARCHAR(255),
+    committed_value_brl             NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    liquidated_value_brl            NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    paid_value_brl                  NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    registered_payables_value_brl   NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    canceled_payables_value_brl     NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    paid_payables_value_brl         NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    inserted_at                     TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at                      TIMESTAMP       NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_amendments_code UNIQUE (amendment_code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_amendments_author ON amendments (author_code);
+CREATE INDEX IF NOT EXISTS idx_expenses_execution_author ON expenses_execution (author_amendament_code) WHERE author_amendament_code <> 0;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS card_expenses (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    superior_organ_code     INTEGER,
+    superior_organ_name     VARCHAR(255),
+    organ_code              INTEGER,
+    organ_name              VARCHAR(255),
+    management_unit_code    INTEGER         NOT NULL,
+    management_unit_name    VARCHAR(255),
+    reference_month         VARCHAR(10)     NOT NULL,
+    cardholder_cpf          VARCHAR(20),
+    cardholder_name         VARCHAR(255),
+    favored_code            VARCHAR(20),
+    favored_name            VARCHAR(255),
+    transaction_type        VARCHAR(100),
+    transaction_date        DATE,
+    value                   NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_card_expenses_unit_month ON card_expenses (management_unit_code, reference_month);
+CREATE INDEX IF NOT EXISTS idx_card_expenses_favored ON card_expenses (favored_code);
@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS is_sanctioned(TEXT, DATE);
+DROP TABLE IF EXISTS sanctions;
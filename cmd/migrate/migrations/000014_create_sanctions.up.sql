@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS sanctions (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    list                    VARCHAR(10)     NOT NULL,
+    sanction_code           VARCHAR(50)     NOT NULL,
+    person_type             VARCHAR(20),
+    document_number         VARCHAR(20)     NOT NULL,
+    sanctioned_name         VARCHAR(500),
+    process_number          VARCHAR(255),
+    category                VARCHAR(255),
+    start_date              DATE            NOT NULL,
+    end_date                DATE,
+    publication_date        DATE,
+    sanctioning_organ       VARCHAR(255),
+    sanctioning_organ_uf    VARCHAR(10),
+    legal_basis             TEXT,
+    fine_value              NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    last_seen_date          DATE            NOT NULL,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_sanctions_list_code UNIQUE (list, sanction_code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sanctions_document_number ON sanctions (document_number);
+
+-- is_sanctioned reports whether a favored code (CPF/CNPJ, formatted or not)
+-- was under any CEIS/CNEP sanction on the given date.
+CREATE OR REPLACE FUNCTION is_sanctioned(code TEXT, on_date DATE) RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM sanctions s
+        WHERE s.document_number = regexp_replace(code, '\D', '', 'g')
+          AND on_date BETWEEN s.start_date AND COALESCE(s.end_date, 'infinity'::date)
+    );
+$$ LANGUAGE SQL STABLE;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS revenues (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    superior_organ_code     INTEGER,
+    superior_organ_name     VARCHAR(255),
+    organ_code              INTEGER,
+    organ_name              VARCHAR(255),
+    management_unit_code    INTEGER         NOT NULL,
+    management_unit_name    VARCHAR(255),
+    fiscal_year             INTEGER         NOT NULL,
+    reference_month         VARCHAR(10)     NOT NULL,
+    economic_category       VARCHAR(255),
+    revenue_origin          VARCHAR(255),
+    revenue_species         VARCHAR(255),
+    revenue_detail          VARCHAR(500),
+    forecast_value_brl      NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    launched_value_brl      NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    collected_value_brl     NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW(),
+
+    UNIQUE (
+        management_unit_code,
+        reference_month,
+        economic_category,
+        revenue_origin,
+        revenue_species,
+        revenue_detail
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_revenues_unit_year ON revenues (management_unit_code, fiscal_year);
+CREATE INDEX IF NOT EXISTS idx_revenues_reference_month ON revenues (reference_month);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS travel_passages;
+DROP TABLE IF EXISTS travel_payments;
+DROP TABLE IF EXISTS travel_trips;
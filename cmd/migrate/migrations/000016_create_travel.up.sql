@@ -0,0 +1,64 @@
+CREATE TABLE IF NOT EXISTS travel_trips (
+    id                          BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    trip_id                     VARCHAR(50)     NOT NULL UNIQUE,
+    proposal_number             VARCHAR(50),
+    situation                   VARCHAR(100),
+    urgent                      BOOLEAN         NOT NULL DEFAULT FALSE,
+    superior_organ_code         INTEGER,
+    superior_organ_name         VARCHAR(255),
+    requesting_organ_code       INTEGER,
+    requesting_organ_name       VARCHAR(255),
+    management_unit_code        INTEGER         NOT NULL,
+    management_unit_name        VARCHAR(255),
+    traveler_name               VARCHAR(255),
+    traveler_role               VARCHAR(255),
+    traveler_function           VARCHAR(255),
+    start_date                  DATE,
+    end_date                    DATE,
+    destinations                TEXT,
+    reason                      TEXT,
+    per_diem_value_brl          NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    tickets_value_brl           NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    returned_value_brl          NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    other_expenses_value_brl    NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    fiscal_year                 INTEGER         NOT NULL,
+    inserted_at                 TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at                  TIMESTAMP       NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_travel_trips_unit ON travel_trips (management_unit_code);
+CREATE INDEX IF NOT EXISTS idx_travel_trips_start_date ON travel_trips (start_date);
+
+CREATE TABLE IF NOT EXISTS travel_payments (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    trip_id                 VARCHAR(50)     NOT NULL REFERENCES travel_trips (trip_id) ON DELETE CASCADE,
+    paying_organ_code       INTEGER,
+    paying_organ_name       VARCHAR(255),
+    management_unit_code    INTEGER,
+    management_unit_name    VARCHAR(255),
+    payment_type            VARCHAR(100),
+    value                   NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_travel_payments_trip ON travel_payments (trip_id);
+
+CREATE TABLE IF NOT EXISTS travel_passages (
+    id                      BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    trip_id                 VARCHAR(50)     NOT NULL REFERENCES travel_trips (trip_id) ON DELETE CASCADE,
+    transport               VARCHAR(100),
+    origin_country          VARCHAR(100),
+    origin_uf               VARCHAR(10),
+    origin_city             VARCHAR(255),
+    destination_country     VARCHAR(100),
+    destination_uf          VARCHAR(10),
+    destination_city        VARCHAR(255),
+    value                   NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    service_fee             NUMERIC(18, 2)  NOT NULL DEFAULT 0,
+    issue_date              DATE,
+    inserted_at             TIMESTAMP       NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMP       NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_travel_passages_trip ON travel_passages (trip_id);
@@ -0,0 +1,4 @@
+UPDATE ingestion_history SET status = 'FAILURE' WHERE status = 'RESCHEDULED';
+ALTER TABLE ingestion_history DROP CONSTRAINT IF EXISTS ingestion_history_status_check;
+ALTER TABLE ingestion_history ADD CONSTRAINT ingestion_history_status_check
+    CHECK (status IN ('SUCCESS', 'PARTIAL', 'FAILURE', 'IN_PROGRESS', 'SKIPPED'));
@@ -0,0 +1,3 @@
+ALTER TABLE ingestion_history DROP CONSTRAINT IF EXISTS ingestion_history_status_check;
+ALTER TABLE ingestion_history ADD CONSTRAINT ingestion_history_status_check
+    CHECK (status IN ('SUCCESS', 'PARTIAL', 'FAILURE', 'IN_PROGRESS', 'SKIPPED', 'RESCHEDULED'));
@@ -0,0 +1 @@
+ALTER TABLE ingestion_history DROP COLUMN IF EXISTS source_fingerprints;
@@ -0,0 +1,3 @@
+-- ETag, Last-Modified, size and SHA-256 of every file a job downloaded,
+-- used by the recheck mode to detect republished portal files.
+ALTER TABLE ingestion_history ADD COLUMN IF NOT EXISTS source_fingerprints JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,3 @@
+-- Header differences (added, removed and likely renamed columns) found in the
+-- files of each ingestion.
+ALTER TABLE ingestion_history ADD COLUMN IF NOT EXISTS schema_drift JSONB NOT NULL DEFAULT '[]'::jsonb;
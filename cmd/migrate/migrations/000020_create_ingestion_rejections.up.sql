@@ -0,0 +1,14 @@
+-- Rows left out of an ingestion because a value could not be parsed
+CREATE TABLE IF NOT EXISTS ingestion_rejections (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    ingestion_id INTEGER NOT NULL REFERENCES ingestion_history(id) ON DELETE CASCADE,
+    data_type VARCHAR(100) NOT NULL,
+    source_file VARCHAR(255) NOT NULL,
+    line INTEGER NOT NULL,
+    column_name VARCHAR(255) NOT NULL,
+    raw_value TEXT,
+    reason TEXT NOT NULL,
+    rejected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ingestion_rejections_ingestion_id ON ingestion_rejections(ingestion_id, source_file, line);
@@ -0,0 +1,3 @@
+-- Outcome of every requested unit code (loaded, empty or failed), so a later
+-- run can retry only the codes a PARTIAL ingestion missed.
+ALTER TABLE ingestion_history ADD COLUMN IF NOT EXISTS code_outcomes JSONB NOT NULL DEFAULT '[]'::jsonb;
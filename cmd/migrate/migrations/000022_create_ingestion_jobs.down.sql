@@ -0,0 +1,2 @@
+ALTER TABLE ingestion_history DROP COLUMN IF EXISTS job_id;
+DROP TABLE IF EXISTS ingestion_jobs;
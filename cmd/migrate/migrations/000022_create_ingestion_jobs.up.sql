@@ -0,0 +1,22 @@
+-- Ingestions requested through the API, waiting for `etl serve` to run them
+CREATE TABLE IF NOT EXISTS ingestion_jobs (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    kind VARCHAR(50) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    codes BIGINT[] NOT NULL,
+    scope_type VARCHAR(50) CHECK (scope_type IN ('MANAGEMENT_UNIT', 'MANAGEMENT')) NOT NULL,
+    trigger_type VARCHAR(50) CHECK (trigger_type IN ('MANUAL', 'SCHEDULED')) NOT NULL,
+    status VARCHAR(20) CHECK (status IN ('QUEUED', 'RUNNING', 'SUCCESS', 'PARTIAL', 'FAILURE')) NOT NULL DEFAULT 'QUEUED',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_ingestion_jobs_queued ON ingestion_jobs (id) WHERE status = 'QUEUED';
+
+-- The queued job an ingestion ran for, if any
+ALTER TABLE ingestion_history ADD COLUMN IF NOT EXISTS job_id BIGINT REFERENCES ingestion_jobs(id) ON DELETE SET NULL;
+CREATE INDEX idx_ingest_job_id ON ingestion_history (job_id);
@@ -0,0 +1,21 @@
+-- Durable orchestrator queue (QUEUE_BACKEND=postgres). A claimed job stays
+-- RUNNING while its worker keeps pushing visible_at forward; once visible_at
+-- passes, another worker may claim it again. Jobs out of attempts end as DEAD.
+CREATE TABLE IF NOT EXISTS pipeline_jobs (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    dataset VARCHAR(50) NOT NULL,
+    job_key VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    ingestion_job_id BIGINT REFERENCES ingestion_jobs(id) ON DELETE SET NULL,
+    status VARCHAR(20) CHECK (status IN ('QUEUED', 'RUNNING', 'DONE', 'DEAD')) NOT NULL DEFAULT 'QUEUED',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    visible_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_by VARCHAR(255) NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One live job per key; finished and dead jobs are kept for inspection
+CREATE UNIQUE INDEX idx_pipeline_jobs_live_key ON pipeline_jobs (dataset, job_key) WHERE status IN ('QUEUED', 'RUNNING');
+CREATE INDEX idx_pipeline_jobs_claim ON pipeline_jobs (dataset, visible_at, id) WHERE status IN ('QUEUED', 'RUNNING');
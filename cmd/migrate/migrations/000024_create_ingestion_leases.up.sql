@@ -0,0 +1,11 @@
+-- One row per period being ingested. Orchestrators in any process take the
+-- lease before running a job and renew it while the job runs; a lease past
+-- expires_at belongs to a dead run and may be taken over.
+CREATE TABLE IF NOT EXISTS ingestion_leases (
+    dataset VARCHAR(50) NOT NULL,
+    job_key VARCHAR(100) NOT NULL,
+    holder VARCHAR(255) NOT NULL,
+    acquired_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (dataset, job_key)
+);
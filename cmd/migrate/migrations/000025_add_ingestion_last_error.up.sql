@@ -0,0 +1,3 @@
+-- Why an ingestion ended as FAILURE, e.g. "interrupted" when the ETL was
+-- stopped while the job ran.
+ALTER TABLE ingestion_history ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE agreement_disbursements DROP CONSTRAINT IF EXISTS uq_agreement_disbursements;
+ALTER TABLE agreement_disbursements DROP COLUMN IF EXISTS released_total;
+ALTER TABLE agreement_disbursements ADD CONSTRAINT uq_agreement_disbursements UNIQUE (agreement_number, release_date, value);
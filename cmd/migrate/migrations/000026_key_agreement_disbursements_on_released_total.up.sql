@@ -0,0 +1,6 @@
+-- The convênios file has no release identifier, only the date and value of the
+-- last release and the total released so far. Two releases of the same value
+-- on the same day differ in that total, so it becomes part of the key.
+ALTER TABLE agreement_disbursements ADD COLUMN IF NOT EXISTS released_total NUMERIC(18, 2) NOT NULL DEFAULT 0;
+ALTER TABLE agreement_disbursements DROP CONSTRAINT IF EXISTS uq_agreement_disbursements;
+ALTER TABLE agreement_disbursements ADD CONSTRAINT uq_agreement_disbursements UNIQUE (agreement_number, release_date, value, released_total);
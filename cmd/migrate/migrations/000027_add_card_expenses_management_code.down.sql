@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_card_expenses_management_date;
+ALTER TABLE card_expenses DROP COLUMN IF EXISTS management_code;
@@ -0,0 +1,13 @@
+-- The CPGF file has no management (gestão) column. It is resolved when a unit
+-- is loaded, from the expenses already loaded for the unit, or else the organ
+-- code, which is the management code of autarquias and foundations.
+ALTER TABLE card_expenses ADD COLUMN IF NOT EXISTS management_code INTEGER;
+
+UPDATE card_expenses ce
+SET management_code = COALESCE(
+    (SELECT ee.management_code FROM expenses_execution ee WHERE ee.management_unit_code = ce.management_unit_code ORDER BY ee.id DESC LIMIT 1),
+    (SELECT p.management_code FROM payments p WHERE p.management_unit_code = ce.management_unit_code AND p.management_code IS NOT NULL ORDER BY p.id DESC LIMIT 1),
+    ce.organ_code
+);
+
+CREATE INDEX IF NOT EXISTS idx_card_expenses_management_date ON card_expenses (management_code, transaction_date);
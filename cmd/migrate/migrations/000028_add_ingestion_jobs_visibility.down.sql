@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_ingestion_jobs_claim;
+CREATE INDEX idx_ingestion_jobs_queued ON ingestion_jobs (id) WHERE status = 'QUEUED';
+
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS visible_at;
@@ -0,0 +1,7 @@
+-- A claimed ingestion job stays RUNNING while its worker keeps pushing
+-- visible_at forward; once visible_at passes, the worker is taken for dead and
+-- another one may claim the job again, like pipeline_jobs.
+ALTER TABLE ingestion_jobs ADD COLUMN IF NOT EXISTS visible_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+DROP INDEX IF EXISTS idx_ingestion_jobs_queued;
+CREATE INDEX idx_ingestion_jobs_claim ON ingestion_jobs (visible_at, id) WHERE status IN ('QUEUED', 'RUNNING');
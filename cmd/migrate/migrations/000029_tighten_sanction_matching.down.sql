@@ -0,0 +1,12 @@
+DROP FUNCTION IF EXISTS is_sanctioned(TEXT, TEXT, DATE);
+CREATE OR REPLACE FUNCTION is_sanctioned(code TEXT, on_date DATE) RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM sanctions s
+        WHERE s.document_number = regexp_replace(code, '\D', '', 'g')
+          AND on_date BETWEEN s.start_date AND COALESCE(s.end_date, 'infinity'::date)
+    );
+$$ LANGUAGE SQL STABLE;
+
+UPDATE sanctions SET start_date = DATE '0001-01-01' WHERE start_date IS NULL;
+ALTER TABLE sanctions ALTER COLUMN start_date SET NOT NULL;
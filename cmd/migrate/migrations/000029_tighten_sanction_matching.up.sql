@@ -0,0 +1,20 @@
+-- A blank start date decoded to 0001-01-01 made the sanction cover all of
+-- history; it is unknown instead, and such a sanction matches no date.
+ALTER TABLE sanctions ALTER COLUMN start_date DROP NOT NULL;
+UPDATE sanctions SET start_date = NULL WHERE start_date = DATE '0001-01-01';
+
+-- is_sanctioned reports whether a favored entity was under any CEIS/CNEP
+-- sanction on the given date. Only a full CNPJ (14 digits) identifies it on
+-- its own: CPFs are published masked (***.123.456-**), so their visible
+-- digits must come with the same name. Entries with no document never match.
+DROP FUNCTION IF EXISTS is_sanctioned(TEXT, DATE);
+CREATE OR REPLACE FUNCTION is_sanctioned(code TEXT, name TEXT, on_date DATE) RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM sanctions s
+        WHERE s.document_number <> ''
+          AND s.document_number = regexp_replace(code, '\D', '', 'g')
+          AND (length(s.document_number) = 14 OR upper(trim(s.sanctioned_name)) = upper(trim(name)))
+          AND on_date BETWEEN s.start_date AND COALESCE(s.end_date, 'infinity'::date)
+    );
+$$ LANGUAGE SQL STABLE;
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	// errLeaseBusy ends an attempt at a job whose period another run holds
+	// the lease of. The job did not run and is left for later.
+	errLeaseBusy = errors.New("period leased by another run")
+	// errProcessedElsewhere ends a job whose period another run finished
+	// after this orchestrator last synced its state.
+	errProcessedElsewhere = errors.New("period processed by another run")
+)
+
+// runID names this process in ingestion leases and claimed queue jobs.
+func runID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// RenewEvery calls renew every interval until the returned func is called,
+// which waits for a renewal in flight to return.
+func RenewEvery(ctx context.Context, interval time.Duration, renew func(ctx context.Context)) context.CancelFunc {
+	renewCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				renew(renewCtx)
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// acquireLease takes the key's ingestion lease and keeps renewing it until
+// the returned release is called. The lease lasts leaseTTL, so the lease of a
+// run that died expires no later than its IN_PROGRESS record turns stale or
+// its queued job becomes visible again. A nil release means another run holds
+// the lease.
+func (o *Orchestrator[J]) acquireLease(ctx context.Context, key string) (context.CancelFunc, error) {
+	const component = "Orchestrator-Lease"
+	dataset := o.pipeline.Dataset()
+
+	acquired, err := o.leaseRepo.AcquireLease(ctx, dataset, key, o.holder, o.leaseTTL)
+	if err != nil || !acquired {
+		return nil, err
+	}
+
+	stopRenewing := RenewEvery(ctx, o.leaseTTL/3, func(ctx context.Context) {
+		if err := o.leaseRepo.RenewLease(ctx, dataset, key, o.holder, o.leaseTTL); err != nil && ctx.Err() == nil {
+			o.appLogger.Warn(component, "Failed to renew lease: key=%s err=%v", key, err)
+		}
+	})
+	return func() {
+		stopRenewing()
+		// Released even when ctx is cancelled, or the next run would wait for
+		// the lease to expire.
+		if err := o.leaseRepo.ReleaseLease(context.WithoutCancel(ctx), dataset, key, o.holder); err != nil {
+			o.appLogger.Warn(component, "Failed to release lease: key=%s err=%v", key, err)
+		}
+	}, nil
+}
+
+// catchUp loads the ingestions of the job's key recorded since the job was
+// added, by runs that held the lease before this one, into the status map.
+// It reports whether they left nothing to do, and otherwise narrows the job
+// to the codes still missing.
+func (o *Orchestrator[J]) catchUp(ctx context.Context, envelope *jobEnvelope[J]) (bool, error) {
+	ref := o.pipeline.BuildHistoryRecord(envelope.job)
+	history, err := o.historyRepo.GetHistoryInRange(ctx, o.pipeline.Dataset(), ref.ReferenceDate, ref.ReferenceDate, ref.ProcessedCodes)
+	if err != nil {
+		return false, fmt.Errorf("failed to reload history: %w", err)
+	}
+
+	key := o.pipeline.StatusKey(envelope.job)
+	found := false
+	o.mu.Lock()
+	for _, h := range history {
+		if o.pipeline.HistoryKey(h) != key || h.ProcessedAt.Before(envelope.since) {
+			continue
+		}
+		found = true
+		if existing, ok := o.statusMap[key]; !ok || h.ProcessedAt.After(existing.ProcessedAt) {
+			o.statusMap[key] = h
+		}
+		o.markDone(key, h)
+	}
+	pending := !found || o.needsProcessing(key)
+	o.mu.Unlock()
+
+	if !pending {
+		return true, nil
+	}
+	if found {
+		envelope.job = o.Resume(envelope.job)
+	}
+	return false, nil
+}
@@ -2,12 +2,14 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/farxc/envelopa-transparencia/internal/domain/model"
 	"github.com/farxc/envelopa-transparencia/internal/domain/repository"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
 )
 
@@ -16,79 +18,135 @@ const (
 	statusSuccess    = "SUCCESS"
 	statusFailure    = "FAILURE"
 	statusSkipped    = "SKIPPED"
+	// statusRescheduled marks a period the portal has not published yet. It is
+	// not a failure, and the next run picks the job up again.
+	statusRescheduled = "RESCHEDULED"
+	// statusPartial marks a job that loaded but left something out: rows kept
+	// in ingestion_rejections, or codes whose outcome is FAILED. Failed codes
+	// are retried on the next run; rejected rows are not.
+	statusPartial = "PARTIAL"
 )
 
+// errInterrupted ends a job whose context was cancelled, e.g. by SIGINT or
+// SIGTERM. Its ingestion is recorded as FAILURE with this as the reason and
+// is not retried in this run; the next run resumes it.
+var errInterrupted = errors.New("interrupted")
+
 // jobEnvelope wraps a job with its retry attempt count.
 // The attempt count is tracked internally by the orchestrator
 // so job types remain free of orchestration concerns.
 type jobEnvelope[J any] struct {
 	job     J
 	attempt int
+	// ingestionJobID links the job's ingestion records to a queued ingestion
+	// job, when it was added under WithIngestionJob.
+	ingestionJobID int64
+	// since is when the orchestrator last synced the key's state; ingestions
+	// recorded after it come from runs this orchestrator does not know about.
+	since time.Time
+	// id and release are set by the durable queue: the pipeline_jobs row the
+	// job was claimed from and the stop of its visibility heartbeat.
+	id      int64
+	release context.CancelFunc
 }
 
+// jobResult carries a finished attempt and the ingestion record it left, which
+// is empty when the record could not be created.
 type jobResult[J any] struct {
 	envelope jobEnvelope[J]
-	id       int64
+	record   model.IngestionHistory
 	err      error
 }
 
 type Orchestrator[J any] struct {
-	pipeline    Pipeline[J]
-	historyRepo repository.IngestionHistoryInterface
-	appLogger   *logger.Logger
+	pipeline      Pipeline[J]
+	historyRepo   repository.IngestionHistoryInterface
+	rejectionRepo repository.IngestionRejectionInterface
+	leaseRepo     repository.IngestionLeaseInterface
+	appLogger     *logger.Logger
+	// holder names this process in the leases it takes.
+	holder string
 
 	maxConcurrency int
 	retryLimit     int
 	staleTimeout   time.Duration
+	// leaseTTL is how long an ingestion lease outlives its last renewal.
+	leaseTTL time.Duration
 
-	statusMap     map[string]model.IngestionHistory
-	mu            sync.RWMutex
-	wg            sync.WaitGroup
-	listenerWg    sync.WaitGroup
-	jobChanClosed bool
+	// syncedAt is when InitializeState last read the history.
+	syncedAt  time.Time
+	statusMap map[string]model.IngestionHistory
+	// doneCodes holds, per key, the codes some ingestion already loaded or
+	// found empty, so Resume can leave them out.
+	doneCodes  map[string]map[int64]bool
+	mu         sync.RWMutex
+	wg         sync.WaitGroup
+	listenerWg sync.WaitGroup
 
-	jobChan    chan jobEnvelope[J]
+	queue      jobQueue[J]
 	resultChan chan jobResult[J]
 }
 
 func NewOrchestrator[J any](
 	pipeline Pipeline[J],
 	historyRepo repository.IngestionHistoryInterface,
+	rejectionRepo repository.IngestionRejectionInterface,
+	leaseRepo repository.IngestionLeaseInterface,
 	appLogger *logger.Logger,
 	concurrency int,
 ) *Orchestrator[J] {
 	return &Orchestrator[J]{
 		pipeline:       pipeline,
 		historyRepo:    historyRepo,
+		rejectionRepo:  rejectionRepo,
+		leaseRepo:      leaseRepo,
 		appLogger:      appLogger,
+		holder:         runID(),
 		maxConcurrency: concurrency,
 		retryLimit:     3,
 		staleTimeout:   30 * time.Minute,
+		leaseTTL:       30 * time.Minute,
 		statusMap:      make(map[string]model.IngestionHistory),
-		jobChan:        make(chan jobEnvelope[J], 100),
+		doneCodes:      make(map[string]map[int64]bool),
+		queue:          newMemoryQueue(pipeline.StatusKey),
 		resultChan:     make(chan jobResult[J], 100),
 	}
 }
 
+// UseDurableQueue keeps the orchestrator's jobs in the pipeline_jobs table
+// instead of memory, so queued jobs and their attempt counts survive a crash
+// and orchestrators of the same dataset in other processes share them. Call
+// it before Start.
+func (o *Orchestrator[J]) UseDurableQueue(repo repository.PipelineJobInterface, opts DurableQueueOptions) {
+	queue := newDurableQueue(repo, o.appLogger, o.pipeline.Dataset(), o.pipeline.StatusKey, o.retryLimit+1, opts)
+	// A crashed worker's job becomes visible after Visibility; its lease must
+	// be gone by then, or whoever claims the job finds the period leased.
+	o.leaseTTL = min(o.staleTimeout, queue.opts.Visibility)
+	o.queue = queue
+}
+
 // InitializeState loads existing ingestion history from DB to populate the
 // in-memory status map, so already-processed jobs are skipped on startup.
 func (o *Orchestrator[J]) InitializeState(ctx context.Context, startDate, endDate time.Time, codes []int64) error {
 	const component = "Orchestrator-Init"
 	start, end := o.pipeline.HistoryRange(startDate, endDate)
 	o.appLogger.Info(component, "Syncing state from DB: range=%s to %s", start.Format(time.DateOnly), end.Format(time.DateOnly))
+	syncedAt := time.Now()
 
-	history, err := o.historyRepo.GetHistoryInRange(ctx, start, end, codes)
+	history, err := o.historyRepo.GetHistoryInRange(ctx, o.pipeline.Dataset(), start, end, codes)
 	if err != nil {
 		return fmt.Errorf("failed to load history: %w", err)
 	}
 
 	o.mu.Lock()
 	defer o.mu.Unlock()
+	o.syncedAt = syncedAt
 	for _, h := range history {
 		key := o.pipeline.HistoryKey(h)
 		if existing, ok := o.statusMap[key]; !ok || h.ProcessedAt.After(existing.ProcessedAt) {
 			o.statusMap[key] = h
 		}
+		o.markDone(key, h)
 	}
 
 	o.appLogger.Info(component, "State sync complete: uniqueKeysFound=%d", len(o.statusMap))
@@ -100,6 +158,15 @@ func (o *Orchestrator[J]) ShouldProcess(key string) bool {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
 
+	if o.queue.pending(key) {
+		return false
+	}
+	return o.needsProcessing(key)
+}
+
+// needsProcessing reports whether the key's latest known ingestion leaves work
+// to do. Callers must hold o.mu.
+func (o *Orchestrator[J]) needsProcessing(key string) bool {
 	h, ok := o.statusMap[key]
 	if !ok {
 		return true
@@ -107,6 +174,8 @@ func (o *Orchestrator[J]) ShouldProcess(key string) bool {
 	switch h.Status {
 	case statusInProgress:
 		return time.Since(h.ProcessedAt) > o.staleTimeout
+	case statusPartial:
+		return len(h.CodeOutcomes.Failed()) > 0
 	case statusSkipped, statusSuccess:
 		return false
 	default:
@@ -114,6 +183,108 @@ func (o *Orchestrator[J]) ShouldProcess(key string) bool {
 	}
 }
 
+// markDone adds the codes h loaded or found empty to the key's done codes.
+// Code outcomes count whatever the status, so the codes an interrupted
+// ingestion committed are not loaded again. Callers must hold o.mu.
+func (o *Orchestrator[J]) markDone(key string, h model.IngestionHistory) {
+	var codes []int64
+	switch {
+	case len(h.CodeOutcomes) > 0:
+		codes = h.CodeOutcomes.Done()
+	case h.Status != statusSuccess && h.Status != statusPartial && h.Status != statusSkipped:
+		return
+	default:
+		codes = h.ProcessedCodes
+	}
+	if len(codes) == 0 {
+		return
+	}
+	if o.doneCodes[key] == nil {
+		o.doneCodes[key] = make(map[int64]bool)
+	}
+	for _, code := range codes {
+		o.doneCodes[key][code] = true
+	}
+}
+
+// Resume narrows job to the codes no ingestion of its key has loaded yet, so a
+// period a PARTIAL or failed run left incomplete only retries what it missed.
+// Jobs of pipelines that are not a CodeScopedPipeline are returned unchanged,
+// as are jobs with nothing done yet.
+func (o *Orchestrator[J]) Resume(job J) J {
+	scoped, ok := o.pipeline.(CodeScopedPipeline[J])
+	if !ok {
+		return job
+	}
+
+	o.mu.RLock()
+	done := o.doneCodes[o.pipeline.StatusKey(job)]
+	o.mu.RUnlock()
+	if len(done) == 0 {
+		return job
+	}
+
+	var pending []int64
+	for _, code := range scoped.JobCodes(job) {
+		if !done[code] {
+			pending = append(pending, code)
+		}
+	}
+	if len(pending) == 0 {
+		return job
+	}
+	return scoped.WithCodes(job, pending)
+}
+
+// SourceChecker reports whether the upstream file behind a fingerprint changed,
+// e.g. service.TransparencyPortalClient.RecheckSource.
+type SourceChecker func(ctx context.Context, fp model.SourceFingerprint) (bool, error)
+
+// Recheck asks check whether any source of a successfully (or partially) ingested key was
+// republished upstream, and forgets the keys that were so ShouldProcess lets
+// them run again. Call it after InitializeState. It returns the number of keys
+// to re-ingest; sources that cannot be checked leave their key untouched.
+func (o *Orchestrator[J]) Recheck(ctx context.Context, check SourceChecker) int {
+	const component = "Orchestrator-Recheck"
+
+	o.mu.RLock()
+	candidates := make(map[string]model.SourceFingerprints)
+	for key, h := range o.statusMap {
+		if (h.Status == statusSuccess || h.Status == statusPartial) && len(h.Sources) > 0 {
+			candidates[key] = h.Sources
+		}
+	}
+	o.mu.RUnlock()
+
+	var stale []string
+	for key, sources := range candidates {
+		for _, fp := range sources {
+			if fp.URL == "" && fp.Key == "" {
+				continue
+			}
+			changed, err := check(ctx, fp)
+			if err != nil {
+				o.appLogger.Warn(component, "Failed to recheck source: key=%s url=%s err=%v", key, fp.URL, err)
+				continue
+			}
+			if changed {
+				stale = append(stale, key)
+				break
+			}
+		}
+	}
+
+	o.mu.Lock()
+	for _, key := range stale {
+		delete(o.statusMap, key)
+		delete(o.doneCodes, key)
+	}
+	o.mu.Unlock()
+
+	o.appLogger.Info(component, "Recheck complete: checked=%d changed=%d", len(candidates), len(stale))
+	return len(stale)
+}
+
 func (o *Orchestrator[J]) Start(ctx context.Context) {
 	const component = "Orchestrator"
 	o.appLogger.Info(component, "Starting orchestrator: concurrency=%d", o.maxConcurrency)
@@ -122,7 +293,7 @@ func (o *Orchestrator[J]) Start(ctx context.Context) {
 		go o.worker(ctx, &o.wg)
 	}
 	o.listenerWg.Add(1)
-	go o.listenToResults()
+	go o.listenToResults(ctx)
 }
 
 func (o *Orchestrator[J]) Wait() {
@@ -131,100 +302,225 @@ func (o *Orchestrator[J]) Wait() {
 	o.listenerWg.Wait()
 }
 
-func (o *Orchestrator[J]) AddJob(job J) bool {
+// AddJob queues job, linked to the ingestion job in ctx if any. It returns
+// false when the orchestrator is closed or the job could not be queued.
+func (o *Orchestrator[J]) AddJob(ctx context.Context, job J) bool {
 	o.mu.RLock()
-	closed := o.jobChanClosed
+	envelope := jobEnvelope[J]{job: job, since: o.syncedAt}
 	o.mu.RUnlock()
-	if closed {
-		return false
+	if jobID, ok := ingestionJobFrom(ctx); ok {
+		envelope.ingestionJobID = jobID
 	}
-	o.jobChan <- jobEnvelope[J]{job: job, attempt: 0}
-	return true
+	return o.queue.push(ctx, envelope)
 }
 
 func (o *Orchestrator[J]) Close() {
-	o.mu.Lock()
-	o.jobChanClosed = true
-	o.mu.Unlock()
-	close(o.jobChan)
+	o.queue.close()
 }
 
 func (o *Orchestrator[J]) worker(ctx context.Context, wg *sync.WaitGroup) {
-	const component = "Worker"
 	defer wg.Done()
 
-	for envelope := range o.jobChan {
-		key := o.pipeline.StatusKey(envelope.job)
-		o.appLogger.Debug(component, "Processing job: key=%s attempt=%d", key, envelope.attempt)
-
-		// Build and persist the IN_PROGRESS audit record before any ETL work.
-		history := o.pipeline.BuildHistoryRecord(envelope.job)
-		history.Status = statusInProgress
-		if err := o.historyRepo.InsertIngestionHistory(ctx, history); err != nil {
-			o.appLogger.Error(component, "Failed to create IN_PROGRESS record: key=%s err=%v", key, err)
-			o.resultChan <- jobResult[J]{envelope: envelope, err: err}
-			continue
+	for {
+		envelope, ok := o.queue.pop(ctx)
+		if !ok {
+			return
 		}
+		o.resultChan <- o.runJob(ctx, envelope)
+	}
+}
+
+// runJob runs one attempt of a job under the key's ingestion lease, recording
+// it in ingestion_history.
+func (o *Orchestrator[J]) runJob(ctx context.Context, envelope jobEnvelope[J]) jobResult[J] {
+	const component = "Worker"
+	key := o.pipeline.StatusKey(envelope.job)
+	if ctx.Err() != nil {
+		return jobResult[J]{envelope: envelope, err: errInterrupted}
+	}
+	o.appLogger.Debug(component, "Processing job: key=%s attempt=%d", key, envelope.attempt)
 
-		// Delegate all extraction logic to the pipeline.
-		etlErr := o.pipeline.Execute(ctx, envelope.job)
+	// Overlapping runs may both have found the key pending: only the lease
+	// holder runs it, after catching up with what earlier holders recorded.
+	release, err := o.acquireLease(ctx, key)
+	if err != nil {
+		o.appLogger.Error(component, "Failed to acquire lease: key=%s err=%v", key, err)
+		return jobResult[J]{envelope: envelope, err: err}
+	}
+	if release == nil {
+		o.appLogger.Info(component, "Period is being processed by another run: key=%s", key)
+		return jobResult[J]{envelope: envelope, err: errLeaseBusy}
+	}
+	defer release()
 
-		// Determine final status and update the audit record.
-		status := statusSuccess
-		if etlErr != nil {
-			if o.pipeline.ShouldSkip(etlErr, envelope.job) {
-				status = statusSkipped
-			} else {
-				status = statusFailure
-			}
+	done, err := o.catchUp(ctx, &envelope)
+	if err != nil {
+		o.appLogger.Error(component, "Failed to catch up with other runs: key=%s err=%v", key, err)
+		return jobResult[J]{envelope: envelope, err: err}
+	}
+	if done {
+		o.appLogger.Info(component, "Period was processed by another run: key=%s", key)
+		return jobResult[J]{envelope: envelope, err: errProcessedElsewhere}
+	}
+
+	// Build and persist the IN_PROGRESS audit record before any ETL work.
+	history := o.pipeline.BuildHistoryRecord(envelope.job)
+	history.Dataset = o.pipeline.Dataset()
+	history.Status = statusInProgress
+	if envelope.ingestionJobID != 0 {
+		history.JobID = &envelope.ingestionJobID
+	}
+	if err := o.historyRepo.InsertIngestionHistory(ctx, history); err != nil {
+		o.appLogger.Error(component, "Failed to create IN_PROGRESS record: key=%s err=%v", key, err)
+		return jobResult[J]{envelope: envelope, err: err}
+	}
+
+	// Delegate all extraction logic to the pipeline.
+	jobCtx, recorder := withJobRecorder(ctx)
+	etlErr := o.pipeline.Execute(jobCtx, envelope.job)
+	if etlErr != nil && ctx.Err() != nil {
+		o.appLogger.Warn(component, "Job interrupted: key=%s err=%v", key, etlErr)
+		etlErr = errInterrupted
+	}
+
+	// Whatever the job got done is recorded even when it was interrupted, so
+	// the next run resumes from it.
+	flushCtx := context.WithoutCancel(ctx)
+
+	if fingerprints := recorder.fingerprints(); len(fingerprints) > 0 {
+		if err := o.historyRepo.UpdateIngestionSources(flushCtx, history.ID, fingerprints); err != nil {
+			o.appLogger.Error(component, "Failed to record sources: id=%d err=%v", history.ID, err)
 		}
-		if err := o.historyRepo.UpdateIngestionStatus(ctx, history.ID, status); err != nil {
-			o.appLogger.Error(component, "Failed to update status: id=%d status=%s err=%v", history.ID, status, err)
+	}
+	if drift := recorder.schemaDrift(); len(drift) > 0 {
+		if err := o.historyRepo.UpdateIngestionSchemaDrift(flushCtx, history.ID, drift); err != nil {
+			o.appLogger.Error(component, "Failed to record schema drift: id=%d err=%v", history.ID, err)
 		}
+	}
 
-		o.resultChan <- jobResult[J]{envelope: envelope, id: history.ID, err: etlErr}
+	outcomes := recorder.codeOutcomes()
+	if len(outcomes) > 0 {
+		if err := o.historyRepo.UpdateIngestionCodeOutcomes(flushCtx, history.ID, outcomes); err != nil {
+			o.appLogger.Error(component, "Failed to record code outcomes: id=%d err=%v", history.ID, err)
+		}
+	}
+
+	rejected := recorder.rejectedRows()
+	if len(rejected) > 0 {
+		if err := o.rejectionRepo.InsertRejections(flushCtx, history.ID, rejected); err != nil {
+			o.appLogger.Error(component, "Failed to record rejected rows: id=%d rows=%d err=%v", history.ID, len(rejected), err)
+		}
+	}
+
+	// Determine final status and update the audit record.
+	status := statusSuccess
+	if len(rejected) > 0 {
+		status = statusPartial
 	}
+	if etlErr != nil {
+		switch {
+		case errors.Is(etlErr, errInterrupted):
+			status = statusFailure
+		case o.pipeline.ShouldSkip(etlErr, envelope.job):
+			status = statusSkipped
+		case errors.Is(etlErr, service.ErrNotPublished):
+			status = statusRescheduled
+		case len(outcomes.Failed()) > 0 && len(outcomes.Done()) > 0:
+			// Some codes loaded before others failed; only those are retried.
+			status = statusPartial
+		default:
+			status = statusFailure
+		}
+	}
+	var lastError string
+	if status == statusFailure {
+		lastError = etlErr.Error()
+	}
+	if err := o.historyRepo.UpdateIngestionStatus(flushCtx, history.ID, status, lastError); err != nil {
+		o.appLogger.Error(component, "Failed to update status: id=%d status=%s err=%v", history.ID, status, err)
+	}
+
+	history.Status = status
+	history.CodeOutcomes = outcomes
+	history.LastError = lastError
+	return jobResult[J]{envelope: envelope, record: *history, err: etlErr}
 }
 
-func (o *Orchestrator[J]) listenToResults() {
+func (o *Orchestrator[J]) listenToResults(ctx context.Context) {
 	const component = "Orchestrator-Feedback"
 	defer o.listenerWg.Done()
 
+	// Results are settled even after ctx is cancelled, so the queue and the
+	// status map reflect every attempt that ran.
+	flushCtx := context.WithoutCancel(ctx)
 	for res := range o.resultChan {
 		key := o.pipeline.StatusKey(res.envelope.job)
 
+		var finalErr error
 		if res.err != nil {
-			if res.envelope.attempt < o.retryLimit && !o.pipeline.ShouldSkip(res.err, res.envelope.job) {
+			switch {
+			case errors.Is(res.err, errProcessedElsewhere):
+				o.appLogger.Info(component, "Job dropped, period processed by another run: key=%s", key)
+			case errors.Is(res.err, errLeaseBusy):
+				// Not completed: the other run may still fail or stop.
+				o.appLogger.Info(component, "Job released, period leased by another run: key=%s", key)
+				finalErr = errLeaseBusy
+			case o.pipeline.ShouldSkip(res.err, res.envelope.job):
+				o.appLogger.Info(component, "Job marked as skipped: key=%s err=%v", key, res.err)
+			case errors.Is(res.err, service.ErrNotPublished):
+				o.appLogger.Info(component, "Data not published yet, rescheduled for the next run: key=%s", key)
+			case errors.Is(res.err, errInterrupted), ctx.Err() != nil:
+				// Not retried: the next run picks the job up where it stopped.
+				o.appLogger.Warn(component, "Job interrupted, left for the next run: key=%s err=%v", key, res.err)
+				finalErr = errInterrupted
+			case res.envelope.attempt < o.retryLimit && shouldRetry(res.err):
 				res.envelope.attempt++
-				if ok := o.enqueue(res.envelope); ok {
+				if scoped, ok := o.pipeline.(CodeScopedPipeline[J]); ok && res.record.Status == statusPartial {
+					res.envelope.job = scoped.WithCodes(res.envelope.job, res.record.CodeOutcomes.Failed())
+				}
+				if ok := o.queue.retry(flushCtx, res.envelope, res.err); ok {
 					o.appLogger.Warn(component, "Job failed, queuing for retry: key=%s attempt=%d err=%v", key, res.envelope.attempt, res.err)
-				} else {
-					o.appLogger.Error(component, "Job failed but channel closed, dropping retry: key=%s err=%v", key, res.err)
+					continue
 				}
-			} else if o.pipeline.ShouldSkip(res.err, res.envelope.job) {
-				o.appLogger.Info(component, "Job marked as skipped: key=%s err=%v", key, res.err)
-			} else {
-				o.appLogger.Error(component, "Job failed after max retries: key=%s err=%v", key, res.err)
+				o.appLogger.Error(component, "Job failed but could not be requeued, dropping retry: key=%s err=%v", key, res.err)
+				finalErr = res.err
+			default:
+				o.appLogger.Error(component, "Job failed: key=%s attempt=%d err=%v", key, res.envelope.attempt, res.err)
+				finalErr = res.err
 			}
+		} else if res.record.Status == statusPartial {
+			o.appLogger.Warn(component, "Job completed with rejected rows: key=%s id=%d", key, res.record.ID)
 		} else {
 			o.appLogger.Info(component, "Job completed successfully: key=%s", key)
-			o.mu.Lock()
-			o.statusMap[key] = model.IngestionHistory{
-				Status:      statusSuccess,
-				ProcessedAt: time.Now(),
-			}
-			o.mu.Unlock()
 		}
+		o.queue.finish(flushCtx, res.envelope, finalErr)
+		o.settle(key, res.record)
 	}
 }
 
-func (o *Orchestrator[J]) enqueue(envelope jobEnvelope[J]) bool {
-	o.mu.RLock()
-	closed := o.jobChanClosed
-	o.mu.RUnlock()
-	if closed {
+// settle keeps the record an attempt left, so ShouldProcess and Resume see the
+// outcome without reloading the history.
+func (o *Orchestrator[J]) settle(key string, record model.IngestionHistory) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if record.ID == 0 {
+		return
+	}
+	o.statusMap[key] = record
+	o.markDone(key, record)
+}
+
+// shouldRetry reports whether a failed job is worth another attempt in this run.
+// Download failures are only retried when transient, since the downloader has
+// already backed off, and schema drift never goes away by retrying; other
+// failures (extraction, load) are retried as before.
+func shouldRetry(err error) bool {
+	if errors.Is(err, service.ErrSchemaDrift) {
 		return false
 	}
-	o.jobChan <- envelope
+	var downloadErr *service.DownloadError
+	if errors.As(err, &downloadErr) {
+		return service.IsTransient(err)
+	}
 	return true
 }
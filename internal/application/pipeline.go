@@ -12,7 +12,8 @@ import (
 //
 // J is the job type specific to this pipeline (e.g. model.ExpensesDailyJob).
 //
-// The orchestrator owns the job lifecycle (IN_PROGRESS → SUCCESS/FAILURE/SKIP).
+// The orchestrator owns the job lifecycle (IN_PROGRESS → SUCCESS/PARTIAL/FAILURE/SKIP,
+// or RESCHEDULED when the portal returns service.ErrNotPublished).
 // The pipeline owns everything domain-specific: what to download, how to extract,
 // how to load, and how to interpret errors.
 type Pipeline[J any] interface {
@@ -22,6 +23,8 @@ type Pipeline[J any] interface {
 
 	BuildHistoryRecord(job J) *model.IngestionHistory
 
+	// ShouldSkip reports whether err means there is nothing to ingest for the
+	// job, e.g. errors.Is(err, service.ErrEmptyDataset). Skipped jobs are never retried.
 	ShouldSkip(err error, job J) bool
 
 	StatusKey(job J) string
@@ -29,4 +32,22 @@ type Pipeline[J any] interface {
 	HistoryKey(h model.IngestionHistory) string
 
 	HistoryRange(startDate, endDate time.Time) (time.Time, time.Time)
+
+	// Dataset identifies this pipeline's rows in ingestion_history, so that
+	// pipelines sharing the table never read each other's state.
+	Dataset() string
+}
+
+// CodeScopedPipeline is implemented by pipelines whose jobs cover several unit
+// codes and load each unit on its own. Such a pipeline records a
+// model.CodeOutcome per code, and the orchestrator narrows a job to the codes a
+// PARTIAL ingestion failed to load instead of running it again in full.
+type CodeScopedPipeline[J any] interface {
+	Pipeline[J]
+
+	// JobCodes returns the codes the job covers.
+	JobCodes(job J) []int64
+
+	// WithCodes returns a copy of job covering only codes.
+	WithCodes(job J, codes []int64) J
 }
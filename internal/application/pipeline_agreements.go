@@ -0,0 +1,124 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+	"github.com/lib/pq"
+)
+
+// AgreementsPipeline implements Pipeline[model.AgreementsJob].
+// It handles the monthly agreements (convênios) snapshot, matched on the granting unit.
+type AgreementsPipeline struct {
+	client    service.TransparencyPortalClient
+	loader    service.Loader
+	appLogger *logger.Logger
+}
+
+func NewAgreementsPipeline(
+	client service.TransparencyPortalClient,
+	loader service.Loader,
+	appLogger *logger.Logger,
+) *AgreementsPipeline {
+	return &AgreementsPipeline{
+		client:    client,
+		loader:    loader,
+		appLogger: appLogger,
+	}
+}
+
+func (p *AgreementsPipeline) Execute(ctx context.Context, job model.AgreementsJob) error {
+	// 1. Download
+	download, err := p.client.FetchAgreements(ctx, job.Month, job.Year)
+	if err != nil {
+		return err
+	}
+	recordSource(ctx, download)
+
+	// 2. Unzip
+	outputDir := "tmp/data/agreements_" + job.Year + job.Month
+	extraction := filesystem.UnzipFile(download.OutputPath, outputDir, p.appLogger)
+	if !extraction.Success {
+		return fmt.Errorf("extraction failed for %s-%s: %w", job.Year, job.Month, service.ErrCorruptArchive)
+	}
+
+	// 3. Build extraction config
+	codeStrings := make([]string, len(job.Codes))
+	for i, c := range job.Codes {
+		codeStrings[i] = fmt.Sprintf("%d", c)
+	}
+
+	cfg := service.AgreementsExtractionConfig{
+		Codes:          codeStrings,
+		IsManagingCode: job.IsManagingCode,
+		Extraction: service.OutputAgreementsExtractionFiles{
+			Month: job.Month,
+			Year:  job.Year,
+			File:  filepath.Join(extraction.OutputDir, job.Year+job.Month+service.ConvenioDataType),
+		},
+	}
+
+	if err := checkSchema(ctx, p.client, map[service.DataType]string{service.Convenio: cfg.Extraction.File}); err != nil {
+		return err
+	}
+
+	// 4. Extract
+	payload, err := p.client.ExtractAgreements(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	recordRejections(ctx, payload.Rejections)
+
+	// 5. Load
+	return p.loader.LoadAgreements(ctx, payload)
+}
+
+func (p *AgreementsPipeline) BuildHistoryRecord(job model.AgreementsJob) *model.IngestionHistory {
+	scope := store.ScopeTypeManagingUnit
+	if job.IsManagingCode {
+		scope = store.ScopeTypeManagement
+	}
+
+	year, _ := strconv.Atoi(job.Year)
+	month, _ := strconv.Atoi(job.Month)
+	refDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+
+	return &model.IngestionHistory{
+		ReferenceDate:  refDate,
+		TriggerType:    job.Trigger,
+		ScopeType:      scope,
+		SourceFile:     fmt.Sprintf("%s%s_convenios.zip", job.Year, job.Month),
+		ProcessedCodes: pq.Int64Array(job.Codes),
+	}
+}
+
+func (p *AgreementsPipeline) ShouldSkip(err error, job model.AgreementsJob) bool {
+	return errors.Is(err, service.ErrEmptyDataset)
+}
+
+func (p *AgreementsPipeline) StatusKey(job model.AgreementsJob) string {
+	return job.Year + "-" + job.Month
+}
+
+func (p *AgreementsPipeline) HistoryKey(h model.IngestionHistory) string {
+	return h.ReferenceDate.Format("2006-01")
+}
+
+func (p *AgreementsPipeline) HistoryRange(startDate, endDate time.Time) (time.Time, time.Time) {
+	start := time.Date(startDate.Year(), startDate.Month(), 1, 0, 0, 0, 0, startDate.Location())
+	end := time.Date(endDate.Year(), endDate.Month(), 1, 0, 0, 0, 0, endDate.Location())
+	return start, end
+}
+
+func (p *AgreementsPipeline) Dataset() string {
+	return store.DatasetAgreements
+}
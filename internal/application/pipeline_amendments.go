@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+	"github.com/lib/pq"
+)
+
+// AmendmentsPipeline implements Pipeline[model.AmendmentsJob].
+// It ingests the full parliamentary amendments (emendas) snapshot.
+type AmendmentsPipeline struct {
+	client    service.TransparencyPortalClient
+	loader    service.Loader
+	appLogger *logger.Logger
+}
+
+func NewAmendmentsPipeline(
+	client service.TransparencyPortalClient,
+	loader service.Loader,
+	appLogger *logger.Logger,
+) *AmendmentsPipeline {
+	return &AmendmentsPipeline{
+		client:    client,
+		loader:    loader,
+		appLogger: appLogger,
+	}
+}
+
+func (p *AmendmentsPipeline) Execute(ctx context.Context, job model.AmendmentsJob) error {
+	date := job.Date.Format("20060102")
+
+	// 1. Download
+	download, err := p.client.FetchAmendments(ctx, date)
+	if err != nil {
+		return err
+	}
+	recordSource(ctx, download)
+
+	// 2. Unzip
+	outputDir := "tmp/data/amendments_" + date
+	extraction := filesystem.UnzipFile(download.OutputPath, outputDir, p.appLogger)
+	if !extraction.Success {
+		return fmt.Errorf("extraction failed for %s: %w", date, service.ErrCorruptArchive)
+	}
+
+	// 3. Extract
+	cfg := service.AmendmentsExtractionConfig{
+		Extraction: service.OutputAmendmentsExtractionFiles{
+			Date: job.Date.Format(time.DateOnly),
+			File: filepath.Join(extraction.OutputDir, service.EmendasParlamentaresDataType),
+		},
+	}
+	if err := checkSchema(ctx, p.client, map[service.DataType]string{service.EmendasParlamentares: cfg.Extraction.File}); err != nil {
+		return err
+	}
+
+	payload, err := p.client.ExtractAmendments(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	recordRejections(ctx, payload.Rejections)
+
+	// 4. Load
+	return p.loader.LoadAmendments(ctx, payload)
+}
+
+func (p *AmendmentsPipeline) BuildHistoryRecord(job model.AmendmentsJob) *model.IngestionHistory {
+	return &model.IngestionHistory{
+		ReferenceDate:  job.Date,
+		TriggerType:    job.Trigger,
+		ScopeType:      store.ScopeTypeManagingUnit,
+		SourceFile:     fmt.Sprintf("emendas_%s.zip", job.Date.Format("20060102")),
+		ProcessedCodes: pq.Int64Array(job.Codes),
+	}
+}
+
+func (p *AmendmentsPipeline) ShouldSkip(err error, job model.AmendmentsJob) bool {
+	return errors.Is(err, service.ErrEmptyDataset)
+}
+
+func (p *AmendmentsPipeline) StatusKey(job model.AmendmentsJob) string {
+	return job.Date.Format(time.DateOnly)
+}
+
+func (p *AmendmentsPipeline) HistoryKey(h model.IngestionHistory) string {
+	return h.ReferenceDate.Format(time.DateOnly)
+}
+
+func (p *AmendmentsPipeline) HistoryRange(startDate, endDate time.Time) (time.Time, time.Time) {
+	return startDate, endDate
+}
+
+func (p *AmendmentsPipeline) Dataset() string {
+	return store.DatasetAmendments
+}
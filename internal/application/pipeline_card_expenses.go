@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+	"github.com/lib/pq"
+)
+
+// CardExpensesPipeline implements Pipeline[model.CardExpensesJob].
+// It handles the monthly government payment card (CPGF) transactions.
+type CardExpensesPipeline struct {
+	client    service.TransparencyPortalClient
+	loader    service.Loader
+	appLogger *logger.Logger
+}
+
+func NewCardExpensesPipeline(
+	client service.TransparencyPortalClient,
+	loader service.Loader,
+	appLogger *logger.Logger,
+) *CardExpensesPipeline {
+	return &CardExpensesPipeline{
+		client:    client,
+		loader:    loader,
+		appLogger: appLogger,
+	}
+}
+
+func (p *CardExpensesPipeline) Execute(ctx context.Context, job model.CardExpensesJob) error {
+	// 1. Download
+	download, err := p.client.FetchCardExpenses(ctx, job.Month, job.Year)
+	if err != nil {
+		return err
+	}
+	recordSource(ctx, download)
+
+	// 2. Unzip
+	outputDir := "tmp/data/card_expenses_" + job.Year + job.Month
+	extraction := filesystem.UnzipFile(download.OutputPath, outputDir, p.appLogger)
+	if !extraction.Success {
+		return fmt.Errorf("extraction failed for %s-%s: %w", job.Year, job.Month, service.ErrCorruptArchive)
+	}
+
+	// 3. Build extraction config
+	codeStrings := make([]string, len(job.Codes))
+	for i, c := range job.Codes {
+		codeStrings[i] = fmt.Sprintf("%d", c)
+	}
+
+	cfg := service.CardExpensesExtractionConfig{
+		Codes: codeStrings,
+		Extraction: service.OutputCardExpensesExtractionFiles{
+			Month: job.Month,
+			Year:  job.Year,
+			File:  filepath.Join(extraction.OutputDir, job.Year+job.Month+service.CartaoPagamentoDataType),
+		},
+	}
+
+	if err := checkSchema(ctx, p.client, map[service.DataType]string{service.CartaoPagamento: cfg.Extraction.File}); err != nil {
+		return err
+	}
+
+	// 4. Extract
+	payload, err := p.client.ExtractCardExpenses(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	recordRejections(ctx, payload.Rejections)
+
+	// 5. Load
+	return p.loader.LoadCardExpenses(ctx, payload)
+}
+
+func (p *CardExpensesPipeline) BuildHistoryRecord(job model.CardExpensesJob) *model.IngestionHistory {
+	year, _ := strconv.Atoi(job.Year)
+	month, _ := strconv.Atoi(job.Month)
+	refDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+
+	return &model.IngestionHistory{
+		ReferenceDate:  refDate,
+		TriggerType:    job.Trigger,
+		ScopeType:      store.ScopeTypeManagingUnit,
+		SourceFile:     fmt.Sprintf("%s%s_cpgf.zip", job.Year, job.Month),
+		ProcessedCodes: pq.Int64Array(job.Codes),
+	}
+}
+
+func (p *CardExpensesPipeline) ShouldSkip(err error, job model.CardExpensesJob) bool {
+	return errors.Is(err, service.ErrEmptyDataset)
+}
+
+func (p *CardExpensesPipeline) StatusKey(job model.CardExpensesJob) string {
+	return job.Year + "-" + job.Month
+}
+
+func (p *CardExpensesPipeline) HistoryKey(h model.IngestionHistory) string {
+	return h.ReferenceDate.Format("2006-01")
+}
+
+func (p *CardExpensesPipeline) HistoryRange(startDate, endDate time.Time) (time.Time, time.Time) {
+	start := time.Date(startDate.Year(), startDate.Month(), 1, 0, 0, 0, 0, startDate.Location())
+	end := time.Date(endDate.Year(), endDate.Month(), 1, 0, 0, 0, 0, endDate.Location())
+	return start, end
+}
+
+func (p *CardExpensesPipeline) Dataset() string {
+	return store.DatasetCardExpenses
+}
@@ -2,8 +2,9 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"strconv"
 	"time"
 
 	"github.com/farxc/envelopa-transparencia/internal/domain/model"
@@ -37,20 +38,18 @@ func NewExpensesDailyPipeline(
 func (p *ExpensesDailyPipeline) Execute(ctx context.Context, job model.ExpensesDailyJob) error {
 	dateCode := job.Date.Format("20060102")
 
-	// 1. Download if not already present
-	zipPath := "tmp/zips/expenses/despesas_" + dateCode + ".zip"
-	if _, err := os.Stat(zipPath); os.IsNotExist(err) {
-		download := p.client.FetchExpensesData(dateCode)
-		if !download.Success {
-			return fmt.Errorf("download failed")
-		}
+	// 1. Download, reusing a complete archive already present
+	download, err := p.client.FetchExpensesData(ctx, dateCode)
+	if err != nil {
+		return err
 	}
+	recordSource(ctx, download)
 
 	// 2. Unzip
 	outputDir := "tmp/data/despesas_" + dateCode
-	extraction := filesystem.UnzipFile(zipPath, outputDir, p.appLogger)
+	extraction := filesystem.UnzipFile(download.OutputPath, outputDir, p.appLogger)
 	if !extraction.Success {
-		return fmt.Errorf("extraction failed")
+		return fmt.Errorf("extraction failed: %w", service.ErrCorruptArchive)
 	}
 
 	// 3. Build extraction config
@@ -68,14 +67,62 @@ func (p *ExpensesDailyPipeline) Execute(ctx context.Context, job model.ExpensesD
 		},
 	}
 
+	if err := checkSchema(ctx, p.client, cfg.Extraction.Files); err != nil {
+		return err
+	}
+
 	// 4. Extract
-	payload, err := p.client.ExtractExpenses(cfg)
+	payload, err := p.client.ExtractExpenses(ctx, cfg)
+	if errors.Is(err, service.ErrEmptyDataset) {
+		recordCodeOutcomes(ctx, p.codeOutcomes(job, &service.ExpensesPayload{}, nil))
+	}
 	if err != nil {
 		return err
 	}
+	recordRejections(ctx, payload.Rejections)
 
-	// 5. Load
-	return p.loader.LoadExpenses(ctx, payload)
+	// 5. Load, one transaction per unit
+	loadErr := p.loader.LoadExpenses(ctx, payload)
+	recordCodeOutcomes(ctx, p.codeOutcomes(job, payload, loadErr))
+	return loadErr
+}
+
+// codeOutcomes maps the loaded units back to the job's codes: a code failed if
+// any of its units failed to load, was loaded if it had units and is empty
+// otherwise. It returns nil when the load failed as a whole.
+func (p *ExpensesDailyPipeline) codeOutcomes(job model.ExpensesDailyJob, payload *service.ExpensesPayload, loadErr error) model.CodeOutcomes {
+	var unitErr *service.UnitLoadError
+	if loadErr != nil && !errors.As(loadErr, &unitErr) {
+		return nil
+	}
+
+	outcomes := make(model.CodeOutcomes, len(job.Codes))
+	index := make(map[int64]int, len(job.Codes))
+	for i, code := range job.Codes {
+		outcomes[i] = model.CodeOutcome{Code: code, Status: model.CodeEmpty}
+		index[code] = i
+	}
+	for _, unit := range payload.UnitsExpenses {
+		unitCode := unit.UgCode
+		if job.IsManagingCode {
+			unitCode = unit.ManagementCode
+		}
+		code, err := strconv.ParseInt(unitCode, 10, 64)
+		if err != nil {
+			continue
+		}
+		i, ok := index[code]
+		if !ok {
+			continue
+		}
+		if unitErr != nil && unitErr.Units[unit.UgCode] != nil {
+			outcomes[i].Status = model.CodeFailed
+			outcomes[i].Error = unitErr.Units[unit.UgCode].Error()
+		} else if outcomes[i].Status == model.CodeEmpty {
+			outcomes[i].Status = model.CodeLoaded
+		}
+	}
+	return outcomes
 }
 
 func (p *ExpensesDailyPipeline) BuildHistoryRecord(job model.ExpensesDailyJob) *model.IngestionHistory {
@@ -93,8 +140,7 @@ func (p *ExpensesDailyPipeline) BuildHistoryRecord(job model.ExpensesDailyJob) *
 }
 
 func (p *ExpensesDailyPipeline) ShouldSkip(err error, job model.ExpensesDailyJob) bool {
-	return err.Error() == fmt.Sprintf("no matching data found for extraction date %s", job.Date.Format("2006-01-02")) ||
-		err.Error() == "dataframe is empty"
+	return errors.Is(err, service.ErrEmptyDataset)
 }
 
 func (p *ExpensesDailyPipeline) StatusKey(job model.ExpensesDailyJob) string {
@@ -108,3 +154,16 @@ func (p *ExpensesDailyPipeline) HistoryKey(h model.IngestionHistory) string {
 func (p *ExpensesDailyPipeline) HistoryRange(startDate, endDate time.Time) (time.Time, time.Time) {
 	return startDate, endDate
 }
+
+func (p *ExpensesDailyPipeline) Dataset() string {
+	return store.DatasetExpenses
+}
+
+func (p *ExpensesDailyPipeline) JobCodes(job model.ExpensesDailyJob) []int64 {
+	return job.Codes
+}
+
+func (p *ExpensesDailyPipeline) WithCodes(job model.ExpensesDailyJob, codes []int64) model.ExpensesDailyJob {
+	job.Codes = codes
+	return job
+}
@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strconv"
@@ -37,16 +38,17 @@ func NewExpensesExecutionPipeline(
 
 func (p *ExpensesExecutionPipeline) Execute(ctx context.Context, job model.ExpensesExecutionJob) error {
 	// 1. Download
-	download := p.client.FetchExpensesExecution(job.Month, job.Year)
-	if !download.Success {
-		return fmt.Errorf("download failed for %s-%s", job.Year, job.Month)
+	download, err := p.client.FetchExpensesExecution(ctx, job.Month, job.Year)
+	if err != nil {
+		return err
 	}
+	recordSource(ctx, download)
 
 	// 2. Unzip
 	outputDir := "tmp/data/expenses_execution_" + job.Year + job.Month
 	extraction := filesystem.UnzipFile(download.OutputPath, outputDir, p.appLogger)
 	if !extraction.Success {
-		return fmt.Errorf("extraction failed for %s-%s", job.Year, job.Month)
+		return fmt.Errorf("extraction failed for %s-%s: %w", job.Year, job.Month, service.ErrCorruptArchive)
 	}
 
 	// 3. Build extraction config
@@ -67,11 +69,16 @@ func (p *ExpensesExecutionPipeline) Execute(ctx context.Context, job model.Expen
 		},
 	}
 
+	if err := checkSchema(ctx, p.client, map[service.DataType]string{service.DespesasExecucao: cfg.Extraction.File}); err != nil {
+		return err
+	}
+
 	// 3. Extract
-	payload, err := p.client.ExtractExpensesExecution(cfg)
+	payload, err := p.client.ExtractExpensesExecution(ctx, cfg)
 	if err != nil {
 		return err
 	}
+	recordRejections(ctx, payload.Rejections)
 
 	// 4. Load (TODO: implement store for execution data)
 	err = p.loader.LoadExpensesExecution(ctx, payload)
@@ -101,7 +108,7 @@ func (p *ExpensesExecutionPipeline) BuildHistoryRecord(job model.ExpensesExecuti
 }
 
 func (p *ExpensesExecutionPipeline) ShouldSkip(err error, job model.ExpensesExecutionJob) bool {
-	return err.Error() == "dataframe is empty"
+	return errors.Is(err, service.ErrEmptyDataset)
 }
 
 func (p *ExpensesExecutionPipeline) StatusKey(job model.ExpensesExecutionJob) string {
@@ -117,3 +124,7 @@ func (p *ExpensesExecutionPipeline) HistoryRange(startDate, endDate time.Time) (
 	end := time.Date(endDate.Year(), endDate.Month(), 1, 0, 0, 0, 0, endDate.Location())
 	return start, end
 }
+
+func (p *ExpensesExecutionPipeline) Dataset() string {
+	return store.DatasetExpensesExecution
+}
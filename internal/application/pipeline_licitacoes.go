@@ -0,0 +1,129 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+	"github.com/lib/pq"
+)
+
+// LicitacoesPipeline implements Pipeline[model.LicitacaoJob].
+// It handles the monthly public procurement data from the licitacoes endpoint.
+type LicitacoesPipeline struct {
+	client    service.TransparencyPortalClient
+	loader    service.Loader
+	appLogger *logger.Logger
+}
+
+func NewLicitacoesPipeline(
+	client service.TransparencyPortalClient,
+	loader service.Loader,
+	appLogger *logger.Logger,
+) *LicitacoesPipeline {
+	return &LicitacoesPipeline{
+		client:    client,
+		loader:    loader,
+		appLogger: appLogger,
+	}
+}
+
+func (p *LicitacoesPipeline) Execute(ctx context.Context, job model.LicitacaoJob) error {
+	// 1. Download
+	download, err := p.client.FetchBiddings(ctx, job.Month, job.Year)
+	if err != nil {
+		return err
+	}
+	recordSource(ctx, download)
+
+	// 2. Unzip
+	outputDir := "tmp/data/licitacoes_" + job.Year + job.Month
+	extraction := filesystem.UnzipFile(download.OutputPath, outputDir, p.appLogger)
+	if !extraction.Success {
+		return fmt.Errorf("extraction failed for %s-%s: %w", job.Year, job.Month, service.ErrCorruptArchive)
+	}
+
+	// 3. Build extraction config
+	codeStrings := make([]string, len(job.Codes))
+	for i, c := range job.Codes {
+		codeStrings[i] = fmt.Sprintf("%d", c)
+	}
+
+	ref := job.Year + job.Month
+	cfg := service.BiddingsExtractionConfig{
+		Codes:          codeStrings,
+		IsManagingCode: job.IsManagingCode,
+		Extraction: service.OutputBiddingsExtractionFiles{
+			Month: job.Month,
+			Year:  job.Year,
+			Files: map[service.DataType]string{
+				service.Licitacao:             filepath.Join(extraction.OutputDir, ref+service.LicitacaoDataType),
+				service.LicitacaoItem:         filepath.Join(extraction.OutputDir, ref+service.LicitacaoItemDataType),
+				service.LicitacaoParticipante: filepath.Join(extraction.OutputDir, ref+service.LicitacaoParticipanteDataType),
+			},
+		},
+	}
+
+	if err := checkSchema(ctx, p.client, cfg.Extraction.Files); err != nil {
+		return err
+	}
+
+	// 4. Extract
+	payload, err := p.client.ExtractBiddings(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	recordRejections(ctx, payload.Rejections)
+
+	// 5. Load
+	return p.loader.LoadBiddings(ctx, payload)
+}
+
+func (p *LicitacoesPipeline) BuildHistoryRecord(job model.LicitacaoJob) *model.IngestionHistory {
+	scope := store.ScopeTypeManagingUnit
+	if job.IsManagingCode {
+		scope = store.ScopeTypeManagement
+	}
+
+	year, _ := strconv.Atoi(job.Year)
+	month, _ := strconv.Atoi(job.Month)
+	refDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+
+	return &model.IngestionHistory{
+		ReferenceDate:  refDate,
+		TriggerType:    job.Trigger,
+		ScopeType:      scope,
+		SourceFile:     fmt.Sprintf("%s%s_licitacoes.zip", job.Year, job.Month),
+		ProcessedCodes: pq.Int64Array(job.Codes),
+	}
+}
+
+func (p *LicitacoesPipeline) ShouldSkip(err error, job model.LicitacaoJob) bool {
+	return errors.Is(err, service.ErrEmptyDataset)
+}
+
+func (p *LicitacoesPipeline) StatusKey(job model.LicitacaoJob) string {
+	return job.Year + "-" + job.Month
+}
+
+func (p *LicitacoesPipeline) HistoryKey(h model.IngestionHistory) string {
+	return h.ReferenceDate.Format("2006-01")
+}
+
+func (p *LicitacoesPipeline) HistoryRange(startDate, endDate time.Time) (time.Time, time.Time) {
+	start := time.Date(startDate.Year(), startDate.Month(), 1, 0, 0, 0, 0, startDate.Location())
+	end := time.Date(endDate.Year(), endDate.Month(), 1, 0, 0, 0, 0, endDate.Location())
+	return start, end
+}
+
+func (p *LicitacoesPipeline) Dataset() string {
+	return store.DatasetBiddings
+}
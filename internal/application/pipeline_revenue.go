@@ -0,0 +1,116 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+	"github.com/lib/pq"
+)
+
+// RevenuePipeline implements Pipeline[model.RevenueJob].
+// It handles the yearly revenue (receitas) file, stored per unit and month.
+type RevenuePipeline struct {
+	client    service.TransparencyPortalClient
+	loader    service.Loader
+	appLogger *logger.Logger
+}
+
+func NewRevenuePipeline(
+	client service.TransparencyPortalClient,
+	loader service.Loader,
+	appLogger *logger.Logger,
+) *RevenuePipeline {
+	return &RevenuePipeline{
+		client:    client,
+		loader:    loader,
+		appLogger: appLogger,
+	}
+}
+
+func (p *RevenuePipeline) Execute(ctx context.Context, job model.RevenueJob) error {
+	// 1. Download
+	download, err := p.client.FetchRevenue(ctx, job.Year)
+	if err != nil {
+		return err
+	}
+	recordSource(ctx, download)
+
+	// 2. Unzip
+	outputDir := "tmp/data/revenue_" + job.Year
+	extraction := filesystem.UnzipFile(download.OutputPath, outputDir, p.appLogger)
+	if !extraction.Success {
+		return fmt.Errorf("extraction failed for %s: %w", job.Year, service.ErrCorruptArchive)
+	}
+
+	// 3. Build extraction config
+	codeStrings := make([]string, len(job.Codes))
+	for i, c := range job.Codes {
+		codeStrings[i] = fmt.Sprintf("%d", c)
+	}
+
+	cfg := service.RevenueExtractionConfig{
+		Codes: codeStrings,
+		Extraction: service.OutputRevenueExtractionFiles{
+			Year: job.Year,
+			File: filepath.Join(extraction.OutputDir, job.Year+service.ReceitasDataType),
+		},
+	}
+
+	if err := checkSchema(ctx, p.client, map[service.DataType]string{service.Receitas: cfg.Extraction.File}); err != nil {
+		return err
+	}
+
+	// 4. Extract
+	payload, err := p.client.ExtractRevenue(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	recordRejections(ctx, payload.Rejections)
+
+	// 5. Load
+	return p.loader.LoadRevenue(ctx, payload)
+}
+
+func (p *RevenuePipeline) BuildHistoryRecord(job model.RevenueJob) *model.IngestionHistory {
+	year, _ := strconv.Atoi(job.Year)
+	refDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	return &model.IngestionHistory{
+		ReferenceDate:  refDate,
+		TriggerType:    job.Trigger,
+		ScopeType:      store.ScopeTypeManagingUnit,
+		SourceFile:     fmt.Sprintf("%s_Receitas.zip", job.Year),
+		ProcessedCodes: pq.Int64Array(job.Codes),
+	}
+}
+
+func (p *RevenuePipeline) ShouldSkip(err error, job model.RevenueJob) bool {
+	return errors.Is(err, service.ErrEmptyDataset)
+}
+
+func (p *RevenuePipeline) StatusKey(job model.RevenueJob) string {
+	return job.Year
+}
+
+func (p *RevenuePipeline) HistoryKey(h model.IngestionHistory) string {
+	return h.ReferenceDate.Format("2006")
+}
+
+func (p *RevenuePipeline) HistoryRange(startDate, endDate time.Time) (time.Time, time.Time) {
+	start := time.Date(startDate.Year(), time.January, 1, 0, 0, 0, 0, startDate.Location())
+	end := time.Date(endDate.Year(), time.January, 1, 0, 0, 0, 0, endDate.Location())
+	return start, end
+}
+
+func (p *RevenuePipeline) Dataset() string {
+	return store.DatasetRevenue
+}
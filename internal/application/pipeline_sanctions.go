@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+	"github.com/lib/pq"
+)
+
+// SanctionsPipeline implements Pipeline[model.SanctionsJob].
+// It ingests the daily CEIS and CNEP snapshots used to screen favored entities.
+type SanctionsPipeline struct {
+	client    service.TransparencyPortalClient
+	loader    service.Loader
+	appLogger *logger.Logger
+}
+
+func NewSanctionsPipeline(
+	client service.TransparencyPortalClient,
+	loader service.Loader,
+	appLogger *logger.Logger,
+) *SanctionsPipeline {
+	return &SanctionsPipeline{
+		client:    client,
+		loader:    loader,
+		appLogger: appLogger,
+	}
+}
+
+func (p *SanctionsPipeline) Execute(ctx context.Context, job model.SanctionsJob) error {
+	date := job.Date.Format("20060102")
+	files := make(map[service.DataType]string)
+
+	for _, list := range []service.DataType{service.SancoesCEIS, service.SancoesCNEP} {
+		// 1. Download
+		download, err := p.client.FetchSanctions(ctx, list, date)
+		if err != nil {
+			return err
+		}
+		recordSource(ctx, download)
+
+		// 2. Unzip
+		outputDir := "tmp/data/sanctions_" + date
+		extraction := filesystem.UnzipFile(download.OutputPath, outputDir, p.appLogger)
+		if !extraction.Success {
+			return fmt.Errorf("extraction failed for %s %s: %w", list, date, service.ErrCorruptArchive)
+		}
+
+		suffix := service.SancoesCEISDataType
+		if list == service.SancoesCNEP {
+			suffix = service.SancoesCNEPDataType
+		}
+		files[list] = filepath.Join(extraction.OutputDir, date+suffix)
+	}
+
+	// 3. Extract
+	cfg := service.SanctionsExtractionConfig{
+		Extraction: service.OutputSanctionsExtractionFiles{
+			Date:  job.Date.Format(time.DateOnly),
+			Files: files,
+		},
+	}
+	if err := checkSchema(ctx, p.client, cfg.Extraction.Files); err != nil {
+		return err
+	}
+
+	payload, err := p.client.ExtractSanctions(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	recordRejections(ctx, payload.Rejections)
+
+	// 4. Load
+	return p.loader.LoadSanctions(ctx, payload)
+}
+
+func (p *SanctionsPipeline) BuildHistoryRecord(job model.SanctionsJob) *model.IngestionHistory {
+	return &model.IngestionHistory{
+		ReferenceDate:  job.Date,
+		TriggerType:    job.Trigger,
+		ScopeType:      store.ScopeTypeManagingUnit,
+		SourceFile:     fmt.Sprintf("sancoes_%s.zip", job.Date.Format("20060102")),
+		ProcessedCodes: pq.Int64Array(job.Codes),
+	}
+}
+
+func (p *SanctionsPipeline) ShouldSkip(err error, job model.SanctionsJob) bool {
+	return errors.Is(err, service.ErrEmptyDataset)
+}
+
+func (p *SanctionsPipeline) StatusKey(job model.SanctionsJob) string {
+	return job.Date.Format(time.DateOnly)
+}
+
+func (p *SanctionsPipeline) HistoryKey(h model.IngestionHistory) string {
+	return h.ReferenceDate.Format(time.DateOnly)
+}
+
+func (p *SanctionsPipeline) HistoryRange(startDate, endDate time.Time) (time.Time, time.Time) {
+	return startDate, endDate
+}
+
+func (p *SanctionsPipeline) Dataset() string {
+	return store.DatasetSanctions
+}
@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/store"
+	"github.com/lib/pq"
+)
+
+// TravelPipeline implements Pipeline[model.TravelJob].
+// It handles the yearly official travel (viagens a serviço) download.
+type TravelPipeline struct {
+	client    service.TransparencyPortalClient
+	loader    service.Loader
+	appLogger *logger.Logger
+}
+
+func NewTravelPipeline(
+	client service.TransparencyPortalClient,
+	loader service.Loader,
+	appLogger *logger.Logger,
+) *TravelPipeline {
+	return &TravelPipeline{
+		client:    client,
+		loader:    loader,
+		appLogger: appLogger,
+	}
+}
+
+func (p *TravelPipeline) Execute(ctx context.Context, job model.TravelJob) error {
+	// 1. Download
+	download, err := p.client.FetchTravel(ctx, job.Year)
+	if err != nil {
+		return err
+	}
+	recordSource(ctx, download)
+
+	// 2. Unzip
+	outputDir := "tmp/data/travel_" + job.Year
+	extraction := filesystem.UnzipFile(download.OutputPath, outputDir, p.appLogger)
+	if !extraction.Success {
+		return fmt.Errorf("extraction failed for %s: %w", job.Year, service.ErrCorruptArchive)
+	}
+
+	// 3. Build extraction config
+	codeStrings := make([]string, len(job.Codes))
+	for i, c := range job.Codes {
+		codeStrings[i] = fmt.Sprintf("%d", c)
+	}
+
+	cfg := service.TravelExtractionConfig{
+		Codes: codeStrings,
+		Extraction: service.OutputTravelExtractionFiles{
+			Year: job.Year,
+			Files: map[service.DataType]string{
+				service.Viagem:          filepath.Join(extraction.OutputDir, job.Year+service.ViagemDataType),
+				service.ViagemPagamento: filepath.Join(extraction.OutputDir, job.Year+service.ViagemPagamentoDataType),
+				service.ViagemPassagem:  filepath.Join(extraction.OutputDir, job.Year+service.ViagemPassagemDataType),
+			},
+		},
+	}
+
+	if err := checkSchema(ctx, p.client, cfg.Extraction.Files); err != nil {
+		return err
+	}
+
+	// 4. Extract
+	payload, err := p.client.ExtractTravel(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	recordRejections(ctx, payload.Rejections)
+
+	// 5. Load
+	return p.loader.LoadTravel(ctx, payload)
+}
+
+func (p *TravelPipeline) BuildHistoryRecord(job model.TravelJob) *model.IngestionHistory {
+	year, _ := strconv.Atoi(job.Year)
+	refDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	return &model.IngestionHistory{
+		ReferenceDate:  refDate,
+		TriggerType:    job.Trigger,
+		ScopeType:      store.ScopeTypeManagingUnit,
+		SourceFile:     fmt.Sprintf("%s_Viagens.zip", job.Year),
+		ProcessedCodes: pq.Int64Array(job.Codes),
+	}
+}
+
+func (p *TravelPipeline) ShouldSkip(err error, job model.TravelJob) bool {
+	return errors.Is(err, service.ErrEmptyDataset)
+}
+
+func (p *TravelPipeline) StatusKey(job model.TravelJob) string {
+	return job.Year
+}
+
+func (p *TravelPipeline) HistoryKey(h model.IngestionHistory) string {
+	return h.ReferenceDate.Format("2006")
+}
+
+func (p *TravelPipeline) HistoryRange(startDate, endDate time.Time) (time.Time, time.Time) {
+	start := time.Date(startDate.Year(), time.January, 1, 0, 0, 0, 0, startDate.Location())
+	end := time.Date(endDate.Year(), time.January, 1, 0, 0, 0, 0, endDate.Location())
+	return start, end
+}
+
+func (p *TravelPipeline) Dataset() string {
+	return store.DatasetTravel
+}
@@ -0,0 +1,310 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/repository"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+)
+
+// jobQueue holds an orchestrator's jobs until a worker takes them. The
+// orchestrator pushes new jobs, pops them in its workers and hands each one
+// back through retry or finish.
+type jobQueue[J any] interface {
+	// push adds a new job. It returns false when the queue is closed or ctx
+	// is cancelled.
+	push(ctx context.Context, envelope jobEnvelope[J]) bool
+	// pop waits for the next job. It returns false once the queue is closed
+	// and has nothing left for this process, or ctx is cancelled.
+	pop(ctx context.Context) (jobEnvelope[J], bool)
+	// retry queues a failed job for another attempt. It returns false when
+	// the job could not be queued, and the caller finishes it instead.
+	retry(ctx context.Context, envelope jobEnvelope[J], err error) bool
+	// finish settles a job for good; a non-nil err is the failure that ended
+	// it. Jobs ended by errInterrupted or errLeaseBusy did not run, and are
+	// left for the next run when the queue outlives the process.
+	finish(ctx context.Context, envelope jobEnvelope[J], err error)
+	// pending reports whether a job for key is queued or running.
+	pending(key string) bool
+	close()
+}
+
+// memoryQueue is the default queue: a buffered channel that lives and dies
+// with the process.
+type memoryQueue[J any] struct {
+	key func(J) string
+
+	mu     sync.RWMutex
+	closed bool
+	// keys holds the keys pushed and not finished yet, so a long-running
+	// caller does not add a key again while its job waits or runs.
+	keys map[string]bool
+	jobs chan jobEnvelope[J]
+}
+
+func newMemoryQueue[J any](key func(J) string) *memoryQueue[J] {
+	return &memoryQueue[J]{
+		key:  key,
+		keys: make(map[string]bool),
+		jobs: make(chan jobEnvelope[J], 100),
+	}
+}
+
+func (q *memoryQueue[J]) push(ctx context.Context, envelope jobEnvelope[J]) bool {
+	q.mu.Lock()
+	closed := q.closed
+	if !closed {
+		q.keys[q.key(envelope.job)] = true
+	}
+	q.mu.Unlock()
+	if closed {
+		return false
+	}
+	select {
+	case q.jobs <- envelope:
+		return true
+	case <-ctx.Done():
+		q.finish(ctx, envelope, ctx.Err())
+		return false
+	}
+}
+
+// pop stops at cancellation without draining the channel: the jobs left in it
+// are lost with the process, and the next run finds them pending again.
+func (q *memoryQueue[J]) pop(ctx context.Context) (jobEnvelope[J], bool) {
+	select {
+	case envelope, ok := <-q.jobs:
+		return envelope, ok
+	case <-ctx.Done():
+		return jobEnvelope[J]{}, false
+	}
+}
+
+func (q *memoryQueue[J]) retry(ctx context.Context, envelope jobEnvelope[J], err error) bool {
+	q.mu.RLock()
+	closed := q.closed
+	q.mu.RUnlock()
+	if closed {
+		return false
+	}
+	q.jobs <- envelope
+	return true
+}
+
+func (q *memoryQueue[J]) finish(ctx context.Context, envelope jobEnvelope[J], err error) {
+	q.mu.Lock()
+	delete(q.keys, q.key(envelope.job))
+	q.mu.Unlock()
+}
+
+func (q *memoryQueue[J]) pending(key string) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.keys[key]
+}
+
+func (q *memoryQueue[J]) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	close(q.jobs)
+}
+
+// DurableQueueOptions tunes a Postgres-backed queue. Zero values take the defaults.
+type DurableQueueOptions struct {
+	// Visibility is how long a claimed job stays hidden from other workers
+	// without a heartbeat. Workers extend it every third of the period while
+	// the job runs, so only a crashed worker's jobs become visible again.
+	// Ingestion leases taken for the queue's jobs last as long.
+	Visibility time.Duration
+	// PollInterval is how often an idle worker checks for new jobs.
+	PollInterval time.Duration
+	// RetryDelay is how long a failed job waits before it is visible again.
+	RetryDelay time.Duration
+}
+
+// durableQueue keeps jobs in the pipeline_jobs table, encoded as JSON, so they
+// survive a crash and can be shared by orchestrators of the same dataset in
+// several processes. Attempts are counted by the table, and jobs that run out
+// of them end as DEAD.
+type durableQueue[J any] struct {
+	repo        repository.PipelineJobInterface
+	appLogger   *logger.Logger
+	dataset     string
+	key         func(J) string
+	worker      string
+	maxAttempts int
+	opts        DurableQueueOptions
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newDurableQueue[J any](repo repository.PipelineJobInterface, appLogger *logger.Logger, dataset string, key func(J) string, maxAttempts int, opts DurableQueueOptions) *durableQueue[J] {
+	if opts.Visibility <= 0 {
+		opts.Visibility = 10 * time.Minute
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = 30 * time.Second
+	}
+	return &durableQueue[J]{
+		repo:        repo,
+		appLogger:   appLogger,
+		dataset:     dataset,
+		key:         key,
+		worker:      runID(),
+		maxAttempts: maxAttempts,
+		opts:        opts,
+	}
+}
+
+func (q *durableQueue[J]) push(ctx context.Context, envelope jobEnvelope[J]) bool {
+	const component = "DurableQueue"
+	q.mu.RLock()
+	closed := q.closed
+	q.mu.RUnlock()
+	if closed || ctx.Err() != nil {
+		return false
+	}
+
+	payload, err := json.Marshal(envelope.job)
+	if err != nil {
+		q.appLogger.Error(component, "Failed to encode job: dataset=%s err=%v", q.dataset, err)
+		return false
+	}
+	row := &model.PipelineJob{
+		Dataset: q.dataset,
+		JobKey:  q.key(envelope.job),
+		Payload: payload,
+	}
+	if envelope.ingestionJobID != 0 {
+		row.IngestionJobID = &envelope.ingestionJobID
+	}
+	if err := q.repo.EnqueuePipelineJob(ctx, row); err != nil {
+		q.appLogger.Error(component, "Failed to enqueue job: key=%s err=%v", row.JobKey, err)
+		return false
+	}
+	if row.ID == 0 {
+		q.appLogger.Debug(component, "Job already queued: key=%s", row.JobKey)
+	}
+	return true
+}
+
+// pop claims the next visible job, polling while there is none. A closed queue
+// returns as soon as nothing is visible; jobs waiting on a retry delay or
+// running elsewhere stay in the table for the next run.
+func (q *durableQueue[J]) pop(ctx context.Context) (jobEnvelope[J], bool) {
+	const component = "DurableQueue"
+	for ctx.Err() == nil {
+		row, err := q.repo.ClaimPipelineJob(ctx, q.dataset, q.worker, q.opts.Visibility, q.maxAttempts)
+		if err != nil {
+			q.appLogger.Error(component, "Failed to claim job: dataset=%s err=%v", q.dataset, err)
+		}
+		if row != nil {
+			var job J
+			if err := json.Unmarshal(row.Payload, &job); err != nil {
+				q.appLogger.Error(component, "Failed to decode job, dead-lettering: id=%d err=%v", row.ID, err)
+				q.settled("dead-letter", row.ID, q.repo.DeadLetterPipelineJob(ctx, row.ID, q.worker, fmt.Sprintf("failed to decode job: %v", err)))
+				continue
+			}
+			envelope := jobEnvelope[J]{job: job, attempt: row.Attempts - 1, since: row.CreatedAt, id: row.ID}
+			if row.IngestionJobID != nil {
+				envelope.ingestionJobID = *row.IngestionJobID
+			}
+			envelope.release = q.heartbeat(ctx, row.ID)
+			return envelope, true
+		}
+
+		q.mu.RLock()
+		closed := q.closed
+		q.mu.RUnlock()
+		if closed {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(q.opts.PollInterval):
+		}
+	}
+	return jobEnvelope[J]{}, false
+}
+
+// heartbeat extends the job's visibility until the returned func is called.
+func (q *durableQueue[J]) heartbeat(ctx context.Context, id int64) context.CancelFunc {
+	const component = "DurableQueue"
+	return RenewEvery(ctx, q.opts.Visibility/3, func(ctx context.Context) {
+		if err := q.repo.ExtendPipelineJob(ctx, id, q.worker, q.opts.Visibility); err != nil && ctx.Err() == nil {
+			q.appLogger.Warn(component, "Failed to extend job visibility: id=%d err=%v", id, err)
+		}
+	})
+}
+
+// retry makes the job visible again after the retry delay. Retries survive
+// Close: a later run, or another process, picks them up.
+func (q *durableQueue[J]) retry(ctx context.Context, envelope jobEnvelope[J], err error) bool {
+	const component = "DurableQueue"
+	envelope.release()
+	payload, encodeErr := json.Marshal(envelope.job)
+	if encodeErr != nil {
+		q.appLogger.Error(component, "Failed to encode job: id=%d err=%v", envelope.id, encodeErr)
+		return false
+	}
+	retryErr := q.repo.RetryPipelineJob(ctx, envelope.id, q.worker, payload, q.opts.RetryDelay, err.Error())
+	q.settled("requeue", envelope.id, retryErr)
+	// A job claimed again by another worker is in its hands, not to be finished here.
+	return retryErr == nil || errors.Is(retryErr, repository.ErrJobLost)
+}
+
+// finish marks the job DONE, or DEAD when err is set. An interrupted job is
+// released back to the queue instead, for the next run to resume, and a job
+// whose period was leased elsewhere is released after the retry delay, for
+// whoever claims it then to catch up with that run.
+func (q *durableQueue[J]) finish(ctx context.Context, envelope jobEnvelope[J], err error) {
+	envelope.release()
+	if errors.Is(err, errInterrupted) || errors.Is(err, errLeaseBusy) {
+		var delay time.Duration
+		if errors.Is(err, errLeaseBusy) {
+			delay = q.opts.RetryDelay
+		}
+		q.settled("release", envelope.id, q.repo.ReleasePipelineJob(ctx, envelope.id, q.worker, delay, err.Error()))
+		return
+	}
+	if err == nil {
+		q.settled("complete", envelope.id, q.repo.CompletePipelineJob(ctx, envelope.id, q.worker))
+		return
+	}
+	q.settled("dead-letter", envelope.id, q.repo.DeadLetterPipelineJob(ctx, envelope.id, q.worker, err.Error()))
+}
+
+// settled logs the outcome of settling a job. A job whose visibility expired
+// and that another worker claimed again is left to that worker.
+func (q *durableQueue[J]) settled(action string, id int64, err error) {
+	const component = "DurableQueue"
+	switch {
+	case errors.Is(err, repository.ErrJobLost):
+		q.appLogger.Warn(component, "Job was claimed again by another worker, leaving it: id=%d action=%s", id, action)
+	case err != nil:
+		q.appLogger.Error(component, "Failed to %s job: id=%d err=%v", action, id, err)
+	}
+}
+
+// pending is always false: the table's unique index on live keys already
+// drops a job whose key is queued or running, in any process.
+func (q *durableQueue[J]) pending(key string) bool {
+	return false
+}
+
+func (q *durableQueue[J]) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"sync"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+// jobRecorder collects what a job learns about its inputs: the fingerprints of
+// the files it downloaded, the schema drift found in them, the rows it had to
+// reject and the outcome of each unit code. The orchestrator attaches one to
+// the job context and stores what it gathered on the ingestion record, so
+// pipelines stay free of history concerns.
+type jobRecorder struct {
+	mu         sync.Mutex
+	sources    model.SourceFingerprints
+	drift      model.SchemaDrifts
+	rejections []model.RowRejection
+	outcomes   model.CodeOutcomes
+}
+
+type jobRecorderKey struct{}
+
+type ingestionJobKey struct{}
+
+// WithIngestionJob returns a context under which orchestrators link the
+// ingestion records they create to the queued ingestion job id.
+func WithIngestionJob(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, ingestionJobKey{}, id)
+}
+
+func ingestionJobFrom(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(ingestionJobKey{}).(int64)
+	return id, ok
+}
+
+func withJobRecorder(ctx context.Context) (context.Context, *jobRecorder) {
+	rec := &jobRecorder{}
+	return context.WithValue(ctx, jobRecorderKey{}, rec), rec
+}
+
+func recorderFrom(ctx context.Context) *jobRecorder {
+	rec, _ := ctx.Value(jobRecorderKey{}).(*jobRecorder)
+	return rec
+}
+
+// recordSource notes a completed download on the recorder attached to ctx, if any.
+func recordSource(ctx context.Context, download service.DownloadResult) {
+	rec := recorderFrom(ctx)
+	if rec == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.sources = append(rec.sources, model.SourceFingerprint{
+		Key:          download.Key,
+		Name:         download.Name,
+		URL:          download.URL,
+		Path:         download.OutputPath,
+		ETag:         download.ETag,
+		LastModified: download.LastModified,
+		Size:         download.Size,
+		SHA256:       download.SHA256,
+	})
+}
+
+// checkSchema runs the client's header check on files and records the drift
+// found. It returns an error wrapping service.ErrSchemaDrift when the drift
+// policy rejects the files.
+func checkSchema(ctx context.Context, client service.TransparencyPortalClient, files map[service.DataType]string) error {
+	drift, err := client.CheckSchema(ctx, files)
+	if rec := recorderFrom(ctx); rec != nil && len(drift) > 0 {
+		rec.mu.Lock()
+		rec.drift = append(rec.drift, drift...)
+		rec.mu.Unlock()
+	}
+	return err
+}
+
+// recordRejections notes the rows an extraction left out.
+func recordRejections(ctx context.Context, rejections []model.RowRejection) {
+	rec := recorderFrom(ctx)
+	if rec == nil || len(rejections) == 0 {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.rejections = append(rec.rejections, rejections...)
+}
+
+// recordCodeOutcomes notes what the job did for each of its codes.
+func recordCodeOutcomes(ctx context.Context, outcomes model.CodeOutcomes) {
+	rec := recorderFrom(ctx)
+	if rec == nil || len(outcomes) == 0 {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.outcomes = append(rec.outcomes, outcomes...)
+}
+
+func (r *jobRecorder) fingerprints() model.SourceFingerprints {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sources
+}
+
+func (r *jobRecorder) schemaDrift() model.SchemaDrifts {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.drift
+}
+
+func (r *jobRecorder) rejectedRows() []model.RowRejection {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rejections
+}
+
+func (r *jobRecorder) codeOutcomes() model.CodeOutcomes {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.outcomes
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// Agreement is a voluntary transfer instrument (convênio, contrato de repasse,
+// termo de fomento...) granted by a managing unit.
+type Agreement struct {
+	AgreementNumber   string                  `db:"agreement_number"`
+	ProcessNumber     string                  `db:"process_number"`
+	InstrumentType    string                  `db:"instrument_type"`
+	Situation         string                  `db:"situation"`
+	Object            string                  `db:"object"`
+	SuperiorOrganCode int32                   `db:"superior_organ_code"`
+	SuperiorOrganName string                  `db:"superior_organ_name"`
+	GrantorCode       int32                   `db:"grantor_code"`
+	GrantorName       string                  `db:"grantor_name"`
+	GranteeCode       string                  `db:"grantee_code"`
+	GranteeName       string                  `db:"grantee_name"`
+	GranteeType       string                  `db:"grantee_type"`
+	FederativeUnit    string                  `db:"federative_unit"`
+	MunicipalityCode  string                  `db:"municipality_code"`
+	MunicipalityName  string                  `db:"municipality_name"`
+	AgreementValue    float64                 `db:"agreement_value"`
+	ReleasedValue     float64                 `db:"released_value"`
+	CounterpartValue  float64                 `db:"counterpart_value"`
+	PublicationDate   time.Time               `db:"publication_date"`
+	StartDate         time.Time               `db:"start_date"`
+	EndDate           time.Time               `db:"end_date"`
+	InsertedAt        time.Time               `db:"inserted_at"`
+	UpdatedAt         time.Time               `db:"updated_at"`
+	Disbursements     []AgreementDisbursement `db:"-" json:"disbursements"`
+}
+
+// AgreementDisbursement is a release of funds for an agreement. The portal only
+// publishes the latest release of each agreement, so the history is built up
+// from the successive monthly snapshots.
+type AgreementDisbursement struct {
+	AgreementNumber string    `db:"agreement_number"`
+	ReleaseDate     time.Time `db:"release_date"`
+	Value           float64   `db:"value"`
+	// ReleasedTotal is the agreement's released value once this release was
+	// made. It tells apart releases of the same value on the same day.
+	ReleasedTotal  float64   `db:"released_total"`
+	ReferenceMonth string    `db:"reference_month"`
+	InsertedAt     time.Time `db:"inserted_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
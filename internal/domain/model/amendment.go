@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// AmendmentAuthor is the dimension of parliamentary amendment authors
+// (parlamentares, bancadas and comissões), shared by amendments and execution data.
+type AmendmentAuthor struct {
+	AuthorCode int32     `db:"author_code"`
+	AuthorName string    `db:"author_name"`
+	InsertedAt time.Time `db:"inserted_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+type Amendment struct {
+	AmendmentCode              string    `db:"amendment_code"`
+	Year                       int16     `db:"year"`
+	Type                       string    `db:"type"`
+	AuthorCode                 int32     `db:"author_code"`
+	AuthorName                 string    `db:"-"`
+	AmendmentNumber            string    `db:"amendment_number"`
+	Locality                   string    `db:"locality"`
+	MunicipalityCode           string    `db:"municipality_code"`
+	Municipality               string    `db:"municipality"`
+	FederativeUnit             string    `db:"federative_unit"`
+	FunctionCode               int16     `db:"function_code"`
+	FunctionName               string    `db:"function_name"`
+	SubfunctionCode            int16     `db:"subfunction_code"`
+	SubfunctionName            string    `db:"subfunction_name"`
+	ProgramCode                int32     `db:"program_code"`
+	ProgramName                string    `db:"program_name"`
+	ActionCode                 string    `db:"action_code"`
+	ActionName                 string    `db:"action_name"`
+	CommittedValueBRL          float64   `db:"committed_value_brl"`
+	LiquidatedValueBRL         float64   `db:"liquidated_value_brl"`
+	PaidValueBRL               float64   `db:"paid_value_brl"`
+	RegisteredPayablesValueBRL float64   `db:"registered_payables_value_brl"`
+	CancelledPayablesValueBRL  float64   `db:"canceled_payables_value_brl"`
+	PaidPayablesValueBRL       float64   `db:"paid_payables_value_brl"`
+	InsertedAt                 time.Time `db:"inserted_at"`
+	UpdatedAt                  time.Time `db:"updated_at"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// Bidding is a public procurement process (licitação) run by a managing unit.
+// A bidding is identified by its number, unit and purchase modality.
+type Bidding struct {
+	BiddingNumber      string               `db:"bidding_number"`
+	ManagementUnitCode int32                `db:"management_unit_code"`
+	ManagementUnitName string               `db:"management_unit_name"`
+	ModalityCode       int16                `db:"modality_code"`
+	Modality           string               `db:"modality"`
+	Process            string               `db:"process"`
+	Object             string               `db:"object"`
+	Situation          string               `db:"situation"`
+	SuperiorOrganCode  int32                `db:"superior_organ_code"`
+	SuperiorOrganName  string               `db:"superior_organ_name"`
+	OrganCode          int32                `db:"organ_code"`
+	OrganName          string               `db:"organ_name"`
+	FederativeUnit     string               `db:"federative_unit"`
+	Municipality       string               `db:"municipality"`
+	ResultDate         time.Time            `db:"result_date"`
+	OpeningDate        time.Time            `db:"opening_date"`
+	Value              float64              `db:"value"`
+	ReferenceMonth     string               `db:"reference_month"`
+	InsertedAt         time.Time            `db:"inserted_at"`
+	UpdatedAt          time.Time            `db:"updated_at"`
+	Items              []BiddingItem        `db:"-" json:"items"`
+	Participants       []BiddingParticipant `db:"-" json:"participants"`
+}
+
+type BiddingItem struct {
+	BiddingNumber      string    `db:"bidding_number"`
+	ManagementUnitCode int32     `db:"management_unit_code"`
+	ModalityCode       int16     `db:"modality_code"`
+	ItemCode           string    `db:"item_code"`
+	Description        string    `db:"description"`
+	Quantity           float64   `db:"quantity"`
+	Value              float64   `db:"value"`
+	WinnerCode         string    `db:"winner_code"`
+	WinnerName         string    `db:"winner_name"`
+	InsertedAt         time.Time `db:"inserted_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
+
+type BiddingParticipant struct {
+	BiddingNumber      string    `db:"bidding_number"`
+	ManagementUnitCode int32     `db:"management_unit_code"`
+	ModalityCode       int16     `db:"modality_code"`
+	ItemCode           string    `db:"item_code"`
+	ParticipantCode    string    `db:"participant_code"`
+	ParticipantName    string    `db:"participant_name"`
+	Winner             bool      `db:"winner"`
+	InsertedAt         time.Time `db:"inserted_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
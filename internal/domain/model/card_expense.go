@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// CardExpense is a transaction made with a government payment card (CPGF).
+// The portal publishes no transaction id, so a unit's month is always reloaded as a whole.
+type CardExpense struct {
+	SuperiorOrganCode  int32  `db:"superior_organ_code"`
+	SuperiorOrganName  string `db:"superior_organ_name"`
+	OrganCode          int32  `db:"organ_code"`
+	OrganName          string `db:"organ_name"`
+	ManagementUnitCode int32  `db:"management_unit_code"`
+	ManagementUnitName string `db:"management_unit_name"`
+	// ManagementCode is not in the CPGF file; the loader resolves it.
+	ManagementCode  int32     `db:"management_code"`
+	ReferenceMonth  string    `db:"reference_month"`
+	CardholderCPF   string    `db:"cardholder_cpf"`
+	CardholderName  string    `db:"cardholder_name"`
+	FavoredCode     string    `db:"favored_code"`
+	FavoredName     string    `db:"favored_name"`
+	TransactionType string    `db:"transaction_type"`
+	TransactionDate time.Time `db:"transaction_date"`
+	Value           float64   `db:"value"`
+	InsertedAt      time.Time `db:"inserted_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
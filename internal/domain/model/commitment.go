@@ -28,6 +28,7 @@ type Commitment struct {
 	BudgetPlan                    string           `db:"budget_plan"`
 	BudgetPlanCode                int32            `db:"budget_plan_code"`
 	Observation                   string           `db:"observation"`
+	BiddingModality               string           `db:"bidding_modality"`
 	CommitmentOriginalValue       float64          `db:"commitment_original_value"`
 	CommitmentValueConvertedToBrl float64          `db:"commitment_value_converted_to_brl"`
 	ConversionValueUsed           float64          `db:"conversion_value_used"`
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+type Contract struct {
+	ContractNumber     string         `db:"contract_number"`
+	Object             string         `db:"object"`
+	LegalBasis         string         `db:"legal_basis"`
+	PurchaseModality   string         `db:"purchase_modality"`
+	Situation          string         `db:"situation"`
+	SuperiorOrganCode  int32          `db:"superior_organ_code"`
+	SuperiorOrganName  string         `db:"superior_organ_name"`
+	OrganCode          int32          `db:"organ_code"`
+	OrganName          string         `db:"organ_name"`
+	ManagementUnitCode int32          `db:"management_unit_code"`
+	ManagementUnitName string         `db:"management_unit_name"`
+	Process            string         `db:"process"`
+	SignatureDate      time.Time      `db:"signature_date"`
+	PublicationDate    time.Time      `db:"publication_date"`
+	StartDate          time.Time      `db:"start_date"`
+	EndDate            time.Time      `db:"end_date"`
+	ContractorCode     string         `db:"contractor_code"`
+	ContractorName     string         `db:"contractor_name"`
+	InitialValue       float64        `db:"initial_value"`
+	FinalValue         float64        `db:"final_value"`
+	BiddingNumber      string         `db:"bidding_number"`
+	ReferenceMonth     string         `db:"reference_month"`
+	InsertedAt         time.Time      `db:"inserted_at"`
+	UpdatedAt          time.Time      `db:"updated_at"`
+	Items              []ContractItem `db:"-" json:"items"`
+}
+
+type ContractItem struct {
+	ContractNumber        string    `db:"contract_number"`
+	ManagementUnitCode    int32     `db:"management_unit_code"`
+	ItemCode              string    `db:"item_code"`
+	Description           string    `db:"description"`
+	ComplementDescription string    `db:"complement_description"`
+	Quantity              float64   `db:"quantity"`
+	Value                 float64   `db:"value"`
+	InsertedAt            time.Time `db:"inserted_at"`
+	UpdatedAt             time.Time `db:"updated_at"`
+}
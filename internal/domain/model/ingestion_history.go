@@ -1,18 +1,152 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/lib/pq"
 )
 
 type IngestionHistory struct {
-	ID             int64         `json:"id" db:"id"`
-	ProcessedAt    time.Time     `json:"processed_at" db:"processed_at"`
-	ReferenceDate  time.Time     `json:"reference_date" db:"reference_date"`
-	SourceFile     string        `json:"source_file" db:"source_file"`
-	TriggerType    string        `json:"trigger_type" db:"trigger_type"`
-	ScopeType      string        `json:"scope_type" db:"scope_type"`
-	Status         string        `json:"status" db:"status"`
-	ProcessedCodes pq.Int64Array `json:"processed_codes" db:"processed_codes" swaggertype:"array,integer"`
+	ID             int64              `json:"id" db:"id"`
+	ProcessedAt    time.Time          `json:"processed_at" db:"processed_at"`
+	Dataset        string             `json:"dataset" db:"dataset"`
+	ReferenceDate  time.Time          `json:"reference_date" db:"reference_date"`
+	SourceFile     string             `json:"source_file" db:"source_file"`
+	TriggerType    string             `json:"trigger_type" db:"trigger_type"`
+	ScopeType      string             `json:"scope_type" db:"scope_type"`
+	Status         string             `json:"status" db:"status"`
+	ProcessedCodes pq.Int64Array      `json:"processed_codes" db:"processed_codes" swaggertype:"array,integer"`
+	Sources        SourceFingerprints `json:"sources" db:"source_fingerprints"`
+	SchemaDrift    SchemaDrifts       `json:"schema_drift" db:"schema_drift"`
+	CodeOutcomes   CodeOutcomes       `json:"code_outcomes" db:"code_outcomes"`
+	// LastError is why the ingestion failed, empty unless its status is FAILURE.
+	LastError string `json:"last_error,omitempty" db:"last_error"`
+	// JobID is the queued ingestion job the record ran for, if any.
+	JobID *int64 `json:"job_id,omitempty" db:"job_id"`
+}
+
+// SourceFingerprint identifies the exact upstream file a job ingested, so a
+// later recheck can tell whether the portal republished it.
+type SourceFingerprint struct {
+	Key          string `json:"key,omitempty"`
+	Name         string `json:"name,omitempty"`
+	URL          string `json:"url"`
+	Path         string `json:"path"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Size         int64  `json:"size"`
+	SHA256       string `json:"sha256"`
+}
+
+// SourceFingerprints is stored as a JSONB array in ingestion_history.
+type SourceFingerprints []SourceFingerprint
+
+func (s SourceFingerprints) Value() (driver.Value, error) {
+	return jsonArrayValue(s, len(s))
+}
+
+func (s *SourceFingerprints) Scan(src interface{}) error {
+	return scanJSON(src, s)
+}
+
+// SchemaDrift describes how a file's header differs from the columns its
+// extractor expects. Renamed pairs a missing column with the most similar new
+// one and is a suggestion, not an applied mapping.
+type SchemaDrift struct {
+	DataType string `json:"data_type"`
+	File     string `json:"file"`
+	// LayoutVersion is the closest known layout the header was compared with.
+	LayoutVersion int            `json:"layout_version,omitempty"`
+	Added         []string       `json:"added,omitempty"`
+	Removed       []string       `json:"removed,omitempty"`
+	Renamed       []ColumnRename `json:"renamed,omitempty"`
+}
+
+type ColumnRename struct {
+	Expected   string  `json:"expected"`
+	Found      string  `json:"found"`
+	Similarity float64 `json:"similarity"`
+}
+
+// SchemaDrifts is stored as a JSONB array in ingestion_history.
+type SchemaDrifts []SchemaDrift
+
+func (s SchemaDrifts) Value() (driver.Value, error) {
+	return jsonArrayValue(s, len(s))
+}
+
+func (s *SchemaDrifts) Scan(src interface{}) error {
+	return scanJSON(src, s)
+}
+
+// Outcomes of a requested unit code within an ingestion.
+const (
+	CodeLoaded = "LOADED"
+	CodeEmpty  = "EMPTY"
+	CodeFailed = "FAILED"
+)
+
+// CodeOutcome records what an ingestion did for one requested code: whether it
+// had data that was loaded, had no data, or failed to load.
+type CodeOutcome struct {
+	Code   int64  `json:"code"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// CodeOutcomes is stored as a JSONB array in ingestion_history.
+type CodeOutcomes []CodeOutcome
+
+func (c CodeOutcomes) Value() (driver.Value, error) {
+	return jsonArrayValue(c, len(c))
+}
+
+func (c *CodeOutcomes) Scan(src interface{}) error {
+	return scanJSON(src, c)
+}
+
+// Failed returns the codes that failed to load.
+func (c CodeOutcomes) Failed() []int64 {
+	var codes []int64
+	for _, o := range c {
+		if o.Status == CodeFailed {
+			codes = append(codes, o.Code)
+		}
+	}
+	return codes
+}
+
+// Done returns the codes that were loaded or had nothing to load.
+func (c CodeOutcomes) Done() []int64 {
+	var codes []int64
+	for _, o := range c {
+		if o.Status != CodeFailed {
+			codes = append(codes, o.Code)
+		}
+	}
+	return codes
+}
+
+// jsonArrayValue encodes v for a JSONB array column, storing "[]" when empty.
+func jsonArrayValue(v interface{}, length int) (driver.Value, error) {
+	if length == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(v)
+}
+
+func scanJSON(src interface{}, dst interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dst)
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+	default:
+		return fmt.Errorf("unsupported type for %T: %T", dst, src)
+	}
 }
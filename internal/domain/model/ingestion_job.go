@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// IngestionJob is an ingestion requested through the API and queued in
+// Postgres until `etl serve` runs it. Every ingestion_history record it
+// creates points back to it.
+type IngestionJob struct {
+	ID          int64         `json:"id" db:"id"`
+	Kind        string        `json:"kind" db:"kind"`
+	StartDate   time.Time     `json:"start_date" db:"start_date"`
+	EndDate     time.Time     `json:"end_date" db:"end_date"`
+	Codes       pq.Int64Array `json:"codes" db:"codes" swaggertype:"array,integer"`
+	ScopeType   string        `json:"scope_type" db:"scope_type"`
+	TriggerType string        `json:"trigger_type" db:"trigger_type"`
+	Status      string        `json:"status" db:"status"`
+	Attempts    int           `json:"attempts" db:"attempts"`
+	LastError   string        `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	StartedAt   *time.Time    `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt  *time.Time    `json:"finished_at,omitempty" db:"finished_at"`
+	// Ingestions are the history records of the job's periods so far.
+	Ingestions []IngestionHistory `json:"ingestions" db:"-"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// RowRejection is a source row left out of an ingestion because one of its
+// values could not be parsed. The rest of the file keeps loading.
+type RowRejection struct {
+	ID          int64     `json:"id" db:"id"`
+	IngestionID int64     `json:"ingestion_id" db:"ingestion_id"`
+	DataType    string    `json:"data_type" db:"data_type"`
+	SourceFile  string    `json:"source_file" db:"source_file"`
+	Line        int       `json:"line" db:"line"`
+	Column      string    `json:"column" db:"column_name"`
+	RawValue    string    `json:"raw_value" db:"raw_value"`
+	Reason      string    `json:"reason" db:"reason"`
+	RejectedAt  time.Time `json:"rejected_at" db:"rejected_at"`
+}
@@ -20,3 +20,74 @@ type ExpensesExecutionJob struct {
 	IsManagingCode bool
 	Trigger        string
 }
+
+// ContractsJob represents a month of contracts (compras) data to ingest.
+// Granularity: monthly (one job per year+month pair).
+type ContractsJob struct {
+	Year           string // "2025"
+	Month          string // "01"
+	Codes          []int64
+	IsManagingCode bool
+	Trigger        string
+}
+
+// LicitacaoJob represents a month of public procurement (licitações) data to ingest.
+// Granularity: monthly (one job per year+month pair).
+type LicitacaoJob struct {
+	Year           string // "2025"
+	Month          string // "01"
+	Codes          []int64
+	IsManagingCode bool
+	Trigger        string
+}
+
+// AgreementsJob represents a month of agreements (convênios) data to ingest.
+// Granularity: monthly (one job per year+month pair).
+type AgreementsJob struct {
+	Year           string // "2025"
+	Month          string // "01"
+	Codes          []int64
+	IsManagingCode bool
+	Trigger        string
+}
+
+// AmendmentsJob represents one snapshot of the parliamentary amendments (emendas) dataset.
+// The portal only publishes the current snapshot, so there is one job per run date.
+type AmendmentsJob struct {
+	Date    time.Time
+	Codes   []int64
+	Trigger string
+}
+
+// CardExpensesJob represents a month of government payment card (CPGF) data to ingest.
+// Granularity: monthly (one job per year+month pair).
+type CardExpensesJob struct {
+	Year    string // "2025"
+	Month   string // "01"
+	Codes   []int64
+	Trigger string
+}
+
+// SanctionsJob represents one daily snapshot of the CEIS and CNEP sanction lists.
+// The lists are not keyed by unit; Codes is only recorded in the history.
+type SanctionsJob struct {
+	Date    time.Time
+	Codes   []int64
+	Trigger string
+}
+
+// RevenueJob represents one fiscal year of revenue (receitas) data to ingest.
+// Granularity: yearly, since the portal publishes a single cumulative file per year.
+type RevenueJob struct {
+	Year    string // "2025"
+	Codes   []int64
+	Trigger string
+}
+
+// TravelJob represents one year of official travel (viagens a serviço) data to ingest.
+// Granularity: yearly, matching the portal's download.
+type TravelJob struct {
+	Year    string // "2025"
+	Codes   []int64
+	Trigger string
+}
@@ -34,6 +34,10 @@ type Payment struct {
 	InsertedAt              time.Time                   `db:"inserted_at"`
 	UpdatedAt               time.Time                   `db:"updated_at"`
 	ImpactedCommitments     []PaymentImpactedCommitment `db:"-" json:"impacted_commitments"`
+	FinalBeneficiaries      []PaymentFinalBeneficiary   `db:"-" json:"final_beneficiaries"`
+	BankTransfers           []PaymentBankTransfer       `db:"-" json:"bank_transfers"`
+	Invoices                []PaymentInvoice            `db:"-" json:"invoices"`
+	CourtOrders             []PaymentCourtOrder         `db:"-" json:"court_orders"`
 }
 
 type PaymentImpactedCommitment struct {
@@ -49,3 +53,53 @@ type PaymentImpactedCommitment struct {
 	InsertedAt                 time.Time `db:"inserted_at"`
 	UpdatedAt                  time.Time `db:"updated_at"`
 }
+
+// PaymentFinalBeneficiary is the party that ultimately receives a payment made
+// through an intermediary, such as a bank or a payroll.
+type PaymentFinalBeneficiary struct {
+	ID                   int64     `db:"id"`
+	PaymentCode          string    `db:"payment_code"`
+	FinalBeneficiaryCode string    `db:"final_beneficiary_code"`
+	FinalBeneficiaryName string    `db:"final_beneficiary_name"`
+	ReceivedValueBRL     float64   `db:"received_value_brl"`
+	InsertedAt           time.Time `db:"inserted_at"`
+	UpdatedAt            time.Time `db:"updated_at"`
+}
+
+// PaymentBankTransfer is an entry of the bank list (ListaBancos) attached to a payment.
+type PaymentBankTransfer struct {
+	ID          int64     `db:"id"`
+	PaymentCode string    `db:"payment_code"`
+	ListCode    string    `db:"list_code"`
+	BankCode    string    `db:"bank_code"`
+	BankName    string    `db:"bank_name"`
+	ValueBRL    float64   `db:"value_brl"`
+	InsertedAt  time.Time `db:"inserted_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// PaymentInvoice is an entry of the invoice list (ListaFaturas) attached to a payment.
+type PaymentInvoice struct {
+	ID            int64     `db:"id"`
+	PaymentCode   string    `db:"payment_code"`
+	ListCode      string    `db:"list_code"`
+	InvoiceNumber string    `db:"invoice_number"`
+	FavoredCode   string    `db:"favored_code"`
+	FavoredName   string    `db:"favored_name"`
+	ValueBRL      float64   `db:"value_brl"`
+	InsertedAt    time.Time `db:"inserted_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// PaymentCourtOrder is an entry of the court-ordered payment list (ListaPrecatorios) attached to a payment.
+type PaymentCourtOrder struct {
+	ID            int64     `db:"id"`
+	PaymentCode   string    `db:"payment_code"`
+	ListCode      string    `db:"list_code"`
+	ProcessNumber string    `db:"process_number"`
+	FavoredCode   string    `db:"favored_code"`
+	FavoredName   string    `db:"favored_name"`
+	ValueBRL      float64   `db:"value_brl"`
+	InsertedAt    time.Time `db:"inserted_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
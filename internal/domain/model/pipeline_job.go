@@ -0,0 +1,23 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PipelineJob is an orchestrator job kept in the durable queue. Payload is
+// the pipeline's job encoded as JSON.
+type PipelineJob struct {
+	ID             int64           `json:"id" db:"id"`
+	Dataset        string          `json:"dataset" db:"dataset"`
+	JobKey         string          `json:"job_key" db:"job_key"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	IngestionJobID *int64          `json:"ingestion_job_id,omitempty" db:"ingestion_job_id"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	VisibleAt      time.Time       `json:"visible_at" db:"visible_at"`
+	LockedBy       string          `json:"locked_by" db:"locked_by"`
+	LastError      string          `json:"last_error" db:"last_error"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Revenue is the collected revenue (receitas) of a managing unit for one month
+// and revenue classification. Rows of the yearly file sharing those keys are summed.
+type Revenue struct {
+	SuperiorOrganCode  int32     `db:"superior_organ_code"`
+	SuperiorOrganName  string    `db:"superior_organ_name"`
+	OrganCode          int32     `db:"organ_code"`
+	OrganName          string    `db:"organ_name"`
+	ManagementUnitCode int32     `db:"management_unit_code"`
+	ManagementUnitName string    `db:"management_unit_name"`
+	FiscalYear         int32     `db:"fiscal_year"`
+	ReferenceMonth     string    `db:"reference_month"`
+	EconomicCategory   string    `db:"economic_category"`
+	RevenueOrigin      string    `db:"revenue_origin"`
+	RevenueSpecies     string    `db:"revenue_species"`
+	RevenueDetail      string    `db:"revenue_detail"`
+	ForecastValue      float64   `db:"forecast_value_brl"`
+	LaunchedValue      float64   `db:"launched_value_brl"`
+	CollectedValue     float64   `db:"collected_value_brl"`
+	InsertedAt         time.Time `db:"inserted_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
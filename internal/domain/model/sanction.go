@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Sanction is an entry of the CEIS (inidôneas e suspensas) or CNEP (punidas)
+// lists. Entries are kept after they leave the list so past payments can
+// still be checked against them.
+type Sanction struct {
+	List               string    `db:"list"`
+	SanctionCode       string    `db:"sanction_code"`
+	PersonType         string    `db:"person_type"`
+	DocumentNumber     string    `db:"document_number"`
+	SanctionedName     string    `db:"sanctioned_name"`
+	ProcessNumber      string    `db:"process_number"`
+	Category           string    `db:"category"`
+	StartDate          time.Time `db:"start_date"`
+	EndDate            time.Time `db:"end_date"`
+	PublicationDate    time.Time `db:"publication_date"`
+	SanctioningOrgan   string    `db:"sanctioning_organ"`
+	SanctioningOrganUF string    `db:"sanctioning_organ_uf"`
+	LegalBasis         string    `db:"legal_basis"`
+	FineValue          float64   `db:"fine_value"`
+	LastSeenDate       time.Time `db:"last_seen_date"`
+	InsertedAt         time.Time `db:"inserted_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// Trip is an official travel process (viagem a serviço). The portal does not
+// publish the requesting unit, so ManagementUnitCode is the unit that paid it.
+type Trip struct {
+	TripID              string        `db:"trip_id"`
+	ProposalNumber      string        `db:"proposal_number"`
+	Situation           string        `db:"situation"`
+	Urgent              bool          `db:"urgent"`
+	SuperiorOrganCode   int32         `db:"superior_organ_code"`
+	SuperiorOrganName   string        `db:"superior_organ_name"`
+	RequestingOrganCode int32         `db:"requesting_organ_code"`
+	RequestingOrganName string        `db:"requesting_organ_name"`
+	ManagementUnitCode  int32         `db:"management_unit_code"`
+	ManagementUnitName  string        `db:"management_unit_name"`
+	TravelerName        string        `db:"traveler_name"`
+	TravelerRole        string        `db:"traveler_role"`
+	TravelerFunction    string        `db:"traveler_function"`
+	StartDate           time.Time     `db:"start_date"`
+	EndDate             time.Time     `db:"end_date"`
+	Destinations        string        `db:"destinations"`
+	Reason              string        `db:"reason"`
+	PerDiemValue        float64       `db:"per_diem_value_brl"`
+	TicketsValue        float64       `db:"tickets_value_brl"`
+	ReturnedValue       float64       `db:"returned_value_brl"`
+	OtherExpensesValue  float64       `db:"other_expenses_value_brl"`
+	FiscalYear          int32         `db:"fiscal_year"`
+	InsertedAt          time.Time     `db:"inserted_at"`
+	UpdatedAt           time.Time     `db:"updated_at"`
+	Payments            []TripPayment `db:"-" json:"payments"`
+	Passages            []TripPassage `db:"-" json:"passages"`
+}
+
+type TripPayment struct {
+	TripID             string    `db:"trip_id"`
+	PayingOrganCode    int32     `db:"paying_organ_code"`
+	PayingOrganName    string    `db:"paying_organ_name"`
+	ManagementUnitCode int32     `db:"management_unit_code"`
+	ManagementUnitName string    `db:"management_unit_name"`
+	PaymentType        string    `db:"payment_type"`
+	Value              float64   `db:"value"`
+	InsertedAt         time.Time `db:"inserted_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
+
+// TripPassage is an airfare or ground ticket bought for a trip. Only the
+// outbound leg is kept; the return leg mirrors it in most rows.
+type TripPassage struct {
+	TripID             string    `db:"trip_id"`
+	Transport          string    `db:"transport"`
+	OriginCountry      string    `db:"origin_country"`
+	OriginUF           string    `db:"origin_uf"`
+	OriginCity         string    `db:"origin_city"`
+	DestinationCountry string    `db:"destination_country"`
+	DestinationUF      string    `db:"destination_uf"`
+	DestinationCity    string    `db:"destination_city"`
+	Value              float64   `db:"value"`
+	ServiceFee         float64   `db:"service_fee"`
+	IssueDate          time.Time `db:"issue_date"`
+	InsertedAt         time.Time `db:"inserted_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+type AgreementInterface interface {
+	InsertAgreement(ctx context.Context, agreement *model.Agreement) error
+	InsertAgreementDisbursement(ctx context.Context, disbursement *model.AgreementDisbursement) error
+	GetAgreements(ctx context.Context, filter service.GetAgreementsFilter) ([]service.AgreementInformation, error)
+	GetAgreementDisbursements(ctx context.Context, agreementNumber string) ([]service.AgreementDisbursementInformation, error)
+}
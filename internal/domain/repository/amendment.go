@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+type AmendmentInterface interface {
+	UpsertAuthor(ctx context.Context, author *model.AmendmentAuthor) error
+	InsertAmendment(ctx context.Context, amendment *model.Amendment) error
+	GetAuthors(ctx context.Context) ([]service.AmendmentAuthorInformation, error)
+	GetAmendmentExecution(ctx context.Context, filter service.AmendmentsFilter) ([]service.AmendmentExecutionRow, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+type BiddingInterface interface {
+	InsertBidding(ctx context.Context, bidding *model.Bidding) error
+	InsertBiddingItem(ctx context.Context, item *model.BiddingItem) error
+	InsertBiddingParticipant(ctx context.Context, participant *model.BiddingParticipant) error
+}
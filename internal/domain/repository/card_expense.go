@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+type CardExpenseInterface interface {
+	InsertCardExpense(ctx context.Context, expense *model.CardExpense) error
+}
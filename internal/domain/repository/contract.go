@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+type ContractInterface interface {
+	InsertContract(ctx context.Context, contract *model.Contract) error
+	InsertContractItem(ctx context.Context, item *model.ContractItem) error
+	GetContracts(ctx context.Context, filter service.GetContractsFilter) ([]service.ContractInformation, error)
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+// ErrJobLost is returned when a worker settles a queued job it no longer
+// holds: its visibility expired and another worker claimed the job again.
+var ErrJobLost = errors.New("job was claimed again by another worker")
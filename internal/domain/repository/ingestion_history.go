@@ -10,6 +10,9 @@ import (
 type IngestionHistoryInterface interface {
 	InsertIngestionHistory(ctx context.Context, history *model.IngestionHistory) error
 	GetLatest(ctx context.Context, limit int) ([]model.IngestionHistory, error)
-	UpdateIngestionStatus(ctx context.Context, id int64, status string) error
-	GetHistoryInRange(ctx context.Context, startDate, endDate time.Time, codes []int64) ([]model.IngestionHistory, error)
+	UpdateIngestionStatus(ctx context.Context, id int64, status, lastError string) error
+	UpdateIngestionSources(ctx context.Context, id int64, sources model.SourceFingerprints) error
+	UpdateIngestionSchemaDrift(ctx context.Context, id int64, drift model.SchemaDrifts) error
+	UpdateIngestionCodeOutcomes(ctx context.Context, id int64, outcomes model.CodeOutcomes) error
+	GetHistoryInRange(ctx context.Context, dataset string, startDate, endDate time.Time, codes []int64) ([]model.IngestionHistory, error)
 }
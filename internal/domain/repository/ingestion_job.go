@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+type IngestionJobInterface interface {
+	EnqueueJob(ctx context.Context, job *model.IngestionJob) error
+	GetJob(ctx context.Context, id int64) (*model.IngestionJob, error)
+	// ClaimJob marks the oldest visible job as running until visibility
+	// passes and returns it, or nil when there is none. Running jobs whose
+	// visibility expired after maxAttempts attempts end as FAILURE instead.
+	ClaimJob(ctx context.Context, visibility time.Duration, maxAttempts int) (*model.IngestionJob, error)
+	// ExtendJob keeps a running job hidden for another visibility period.
+	ExtendJob(ctx context.Context, id int64, attempt int, visibility time.Duration) error
+	// ReleaseJob requeues a job its worker was stopped before finishing,
+	// without counting the attempt. Like FinishJob, it only applies while the
+	// job is still running the claim that made attempt.
+	ReleaseJob(ctx context.Context, id int64, attempt int, lastError string) error
+	FinishJob(ctx context.Context, id int64, attempt int, status, lastError string) error
+}
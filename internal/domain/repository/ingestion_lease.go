@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type IngestionLeaseInterface interface {
+	// AcquireLease takes the lease on a dataset key for holder until ttl
+	// passes. It returns false when another holder's lease has not expired.
+	AcquireLease(ctx context.Context, dataset, key, holder string, ttl time.Duration) (bool, error)
+	RenewLease(ctx context.Context, dataset, key, holder string, ttl time.Duration) error
+	ReleaseLease(ctx context.Context, dataset, key, holder string) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+type IngestionRejectionInterface interface {
+	InsertRejections(ctx context.Context, ingestionID int64, rejections []model.RowRejection) error
+	GetRejections(ctx context.Context, ingestionID int64, limit, offset int) ([]model.RowRejection, error)
+}
@@ -4,9 +4,15 @@ import (
 	"context"
 
 	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
 )
 
 type PaymentInterface interface {
 	InsertPayment(ctx context.Context, payment *model.Payment) error
 	InsertPaymentImpactedCommitment(ctx context.Context, pic *model.PaymentImpactedCommitment) error
+	InsertPaymentFinalBeneficiary(ctx context.Context, pfb *model.PaymentFinalBeneficiary) error
+	InsertPaymentBankTransfer(ctx context.Context, pbt *model.PaymentBankTransfer) error
+	InsertPaymentInvoice(ctx context.Context, pi *model.PaymentInvoice) error
+	InsertPaymentCourtOrder(ctx context.Context, pco *model.PaymentCourtOrder) error
+	GetPaymentDetail(ctx context.Context, paymentCode string) (*service.PaymentDetail, error)
 }
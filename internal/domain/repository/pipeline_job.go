@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+type PipelineJobInterface interface {
+	// EnqueuePipelineJob queues a job unless one with the same dataset and key
+	// is already queued or running.
+	EnqueuePipelineJob(ctx context.Context, job *model.PipelineJob) error
+	// ClaimPipelineJob locks the next visible job of dataset for worker until
+	// visibility passes, or returns nil when there is none. Running jobs whose
+	// visibility expired after maxAttempts attempts are dead-lettered instead.
+	ClaimPipelineJob(ctx context.Context, dataset, worker string, visibility time.Duration, maxAttempts int) (*model.PipelineJob, error)
+	ExtendPipelineJob(ctx context.Context, id int64, worker string, visibility time.Duration) error
+	// CompletePipelineJob, RetryPipelineJob, DeadLetterPipelineJob and
+	// ReleasePipelineJob settle a running job held by worker. They return
+	// ErrJobLost when another worker claimed it again in the meantime.
+	CompletePipelineJob(ctx context.Context, id int64, worker string) error
+	// RetryPipelineJob requeues a job as payload, which may narrow it to what
+	// is left to do, visible again after delay.
+	RetryPipelineJob(ctx context.Context, id int64, worker string, payload []byte, delay time.Duration, lastError string) error
+	DeadLetterPipelineJob(ctx context.Context, id int64, worker, lastError string) error
+	// ReleasePipelineJob hands back a job its worker did not finish, visible
+	// again after delay and without counting the attempt.
+	ReleasePipelineJob(ctx context.Context, id int64, worker string, delay time.Duration, lastError string) error
+}
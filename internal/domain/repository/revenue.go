@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+type RevenueInterface interface {
+	InsertRevenue(ctx context.Context, revenue *model.Revenue) error
+	GetBudgetBalance(ctx context.Context, filter service.ExpensesFilter) ([]service.BudgetBalance, error)
+}
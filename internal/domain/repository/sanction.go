@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+type SanctionInterface interface {
+	InsertSanction(ctx context.Context, sanction *model.Sanction) error
+	GetSanctionedFavored(ctx context.Context, filter service.ExpensesFilter) ([]service.SanctionedFavored, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+type TravelInterface interface {
+	InsertTrip(ctx context.Context, trip *model.Trip) error
+	InsertTripPayment(ctx context.Context, payment *model.TripPayment) error
+	InsertTripPassage(ctx context.Context, passage *model.TripPassage) error
+	GetTravelCosts(ctx context.Context, filter service.GetTravelCostsFilter) ([]service.TravelCost, error)
+}
@@ -0,0 +1,36 @@
+package service
+
+import "time"
+
+type GetAgreementsFilter struct {
+	ManagementUnitCodes []string
+	Situation           string
+	StartDate           time.Time
+	EndDate             time.Time
+}
+
+type AgreementInformation struct {
+	AgreementNumber  string    `db:"agreement_number" json:"agreement_number"`
+	GrantorCode      int32     `db:"grantor_code" json:"grantor_code"`
+	GrantorName      string    `db:"grantor_name" json:"grantor_name"`
+	GranteeCode      string    `db:"grantee_code" json:"grantee_code"`
+	GranteeName      string    `db:"grantee_name" json:"grantee_name"`
+	InstrumentType   string    `db:"instrument_type" json:"instrument_type"`
+	Situation        string    `db:"situation" json:"situation"`
+	Object           string    `db:"object" json:"object"`
+	FederativeUnit   string    `db:"federative_unit" json:"federative_unit"`
+	MunicipalityName string    `db:"municipality_name" json:"municipality_name"`
+	AgreementValue   float64   `db:"agreement_value" json:"agreement_value"`
+	ReleasedValue    float64   `db:"released_value" json:"released_value"`
+	CounterpartValue float64   `db:"counterpart_value" json:"counterpart_value"`
+	StartDate        time.Time `db:"start_date" json:"start_date"`
+	EndDate          time.Time `db:"end_date" json:"end_date"`
+}
+
+type AgreementDisbursementInformation struct {
+	AgreementNumber string    `db:"agreement_number" json:"agreement_number"`
+	ReleaseDate     time.Time `db:"release_date" json:"release_date"`
+	Value           float64   `db:"value" json:"value"`
+	ReleasedTotal   float64   `db:"released_total" json:"released_total"`
+	ReferenceMonth  string    `db:"reference_month" json:"reference_month"`
+}
@@ -0,0 +1,35 @@
+package service
+
+import "github.com/farxc/envelopa-transparencia/internal/domain/model"
+
+// Agreements data — convenios/{year}{month}
+// Monthly snapshot of every agreement with its cumulative and latest release.
+const (
+	Convenio DataType = iota + 400
+)
+
+const (
+	ConvenioDataType = "_Convenios.csv"
+)
+
+var AgreementDataTypeNames = map[DataType]string{
+	Convenio: "Convênios",
+}
+
+type OutputAgreementsExtractionFiles struct {
+	Month string
+	Year  string
+	File  string
+}
+
+type UnitAgreements struct {
+	UgCode     int32             `json:"ug_code"`
+	Agreements []model.Agreement `json:"agreements"`
+}
+
+type AgreementsPayload struct {
+	ExtractionDate  string
+	UnitsAgreements []UnitAgreements
+	// Rejections are the rows left out because a value did not parse.
+	Rejections []model.RowRejection
+}
@@ -0,0 +1,26 @@
+package service
+
+type AmendmentsFilter struct {
+	ExpensesFilter
+	AuthorCodes []int
+}
+
+type AmendmentAuthorInformation struct {
+	AuthorCode      int32  `db:"author_code" json:"author_code"`
+	AuthorName      string `db:"author_name" json:"author_name"`
+	AmendmentsCount int    `db:"amendments_count" json:"amendments_count"`
+}
+
+// AmendmentExecutionRow is the execution of amendments by one author, for one
+// action in one municipality, summed over the filtered months.
+type AmendmentExecutionRow struct {
+	AuthorCode         int32   `db:"author_code" json:"author_code"`
+	AuthorName         string  `db:"author_name" json:"author_name"`
+	ActionCode         string  `db:"action_code" json:"action_code"`
+	ActionName         string  `db:"action_name" json:"action_name"`
+	FederativeUnit     string  `db:"federative_unit" json:"federative_unit"`
+	Municipality       string  `db:"municipality" json:"municipality"`
+	CommittedValueBRL  float64 `db:"committed_value_brl" json:"committed_value_brl"`
+	LiquidatedValueBRL float64 `db:"liquidated_value_brl" json:"liquidated_value_brl"`
+	PaidValueBRL       float64 `db:"paid_value_brl" json:"paid_value_brl"`
+}
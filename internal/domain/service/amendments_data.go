@@ -0,0 +1,34 @@
+package service
+
+import "github.com/farxc/envelopa-transparencia/internal/domain/model"
+
+// Parliamentary amendments data — emendas-parlamentares/UNICO
+// Single snapshot of every amendment since 2014, not keyed by managing unit.
+const (
+	EmendasParlamentares DataType = iota + 500
+)
+
+const (
+	EmendasParlamentaresDataType = "EmendasParlamentares.csv"
+)
+
+var AmendmentDataTypeNames = map[DataType]string{
+	EmendasParlamentares: "Emendas Parlamentares",
+}
+
+type OutputAmendmentsExtractionFiles struct {
+	Date string
+	File string
+}
+
+type AuthorAmendments struct {
+	Author     model.AmendmentAuthor `json:"author"`
+	Amendments []model.Amendment     `json:"amendments"`
+}
+
+type AmendmentsPayload struct {
+	ExtractionDate   string
+	AuthorAmendments []AuthorAmendments
+	// Rejections are the rows left out because a value did not parse.
+	Rejections []model.RowRejection
+}
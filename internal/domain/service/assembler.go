@@ -18,6 +18,10 @@ func AssembleExpensesData(
 	liImpacts []model.LiquidationImpactedCommitment,
 	payments []model.Payment,
 	paImpacts []model.PaymentImpactedCommitment,
+	finalBeneficiaries []model.PaymentFinalBeneficiary,
+	bankTransfers []model.PaymentBankTransfer,
+	invoices []model.PaymentInvoice,
+	courtOrders []model.PaymentCourtOrder,
 ) map[string]*UnitsExpenses {
 	unitsMap := make(map[string]*UnitsExpenses)
 
@@ -50,13 +54,34 @@ func AssembleExpensesData(
 		commitmentMap[commitment.CommitmentCode] = commitment
 	}
 
+	// 5. Group Final Beneficiaries by PaymentCode
+	beneficiaryMap := make(map[string][]model.PaymentFinalBeneficiary)
+	for _, b := range finalBeneficiaries {
+		beneficiaryMap[b.PaymentCode] = append(beneficiaryMap[b.PaymentCode], b)
+	}
+
+	// 6. Group Payment annexes (bank, invoice and court-order lists) by PaymentCode
+	bankTransferMap := make(map[string][]model.PaymentBankTransfer)
+	for _, t := range bankTransfers {
+		bankTransferMap[t.PaymentCode] = append(bankTransferMap[t.PaymentCode], t)
+	}
+	invoiceMap := make(map[string][]model.PaymentInvoice)
+	for _, inv := range invoices {
+		invoiceMap[inv.PaymentCode] = append(invoiceMap[inv.PaymentCode], inv)
+	}
+	courtOrderMap := make(map[string][]model.PaymentCourtOrder)
+	for _, co := range courtOrders {
+		courtOrderMap[co.PaymentCode] = append(courtOrderMap[co.PaymentCode], co)
+	}
+
 	// Helper to get or create unit entry
-	getOrCreateUnit := func(ugCode int, ugName string) *UnitsExpenses {
+	getOrCreateUnit := func(ugCode int, ugName string, managementCode int) *UnitsExpenses {
 		key := fmt.Sprintf("%d", ugCode)
 		if _, exists := unitsMap[key]; !exists {
 			unitsMap[key] = &UnitsExpenses{
 				UgCode:                     key,
 				UgName:                     ugName,
+				ManagementCode:             fmt.Sprintf("%d", managementCode),
 				Commitments:                []model.Commitment{},
 				Liquidations:               []model.Liquidation{},
 				Payments:                   []model.Payment{},
@@ -69,12 +94,12 @@ func AssembleExpensesData(
 		return unitsMap[key]
 	}
 
-	// 6. Build Hierarchy
+	// 7. Build Hierarchy
 	for _, c := range commitments {
 		if its, ok := itemsMap[c.CommitmentCode]; ok {
 			c.Items = its
 		}
-		unit := getOrCreateUnit(c.ManagementUnitCode, c.ManagementUnitName)
+		unit := getOrCreateUnit(c.ManagementUnitCode, c.ManagementUnitName, c.ManagementCode)
 		unit.Commitments = append(unit.Commitments, c)
 	}
 
@@ -82,12 +107,24 @@ func AssembleExpensesData(
 		if imps, ok := liImpactMap[l.LiquidationCode]; ok {
 			l.ImpactedCommitments = imps
 		}
-		unit := getOrCreateUnit(l.ManagementUnitCode, l.ManagementUnitName)
+		unit := getOrCreateUnit(l.ManagementUnitCode, l.ManagementUnitName, l.ManagementCode)
 		unit.Liquidations = append(unit.Liquidations, l)
 	}
 
 	for _, p := range payments {
-		unit := getOrCreateUnit(p.ManagementUnitCode, p.ManagementUnitName)
+		if bs, ok := beneficiaryMap[p.PaymentCode]; ok {
+			p.FinalBeneficiaries = bs
+		}
+		if ts, ok := bankTransferMap[p.PaymentCode]; ok {
+			p.BankTransfers = ts
+		}
+		if invs, ok := invoiceMap[p.PaymentCode]; ok {
+			p.Invoices = invs
+		}
+		if cos, ok := courtOrderMap[p.PaymentCode]; ok {
+			p.CourtOrders = cos
+		}
+		unit := getOrCreateUnit(p.ManagementUnitCode, p.ManagementUnitName, p.ManagementCode)
 		unit.Payments = append(unit.Payments, p)
 	}
 
@@ -96,7 +133,7 @@ func AssembleExpensesData(
 		if !ok {
 			continue
 		}
-		unit := getOrCreateUnit(commitment.ManagementUnitCode, commitment.ManagementUnitName)
+		unit := getOrCreateUnit(commitment.ManagementUnitCode, commitment.ManagementUnitName, commitment.ManagementCode)
 		unit.PaymentImpactedCommitments = append(unit.PaymentImpactedCommitments, imp)
 	}
 
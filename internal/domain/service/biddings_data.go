@@ -0,0 +1,41 @@
+package service
+
+import "github.com/farxc/envelopa-transparencia/internal/domain/model"
+
+// Public procurement data — licitacoes/{year}{month}
+// Monthly download with the biddings, their items (with the winner) and participants.
+const (
+	Licitacao DataType = iota + 300
+	LicitacaoItem
+	LicitacaoParticipante
+)
+
+const (
+	LicitacaoDataType             = "_Licitação.csv"
+	LicitacaoItemDataType         = "_ItemLicitação.csv"
+	LicitacaoParticipanteDataType = "_ParticipantesLicitação.csv"
+)
+
+var BiddingDataTypeNames = map[DataType]string{
+	Licitacao:             "Licitação",
+	LicitacaoItem:         "Item Licitação",
+	LicitacaoParticipante: "Participantes Licitação",
+}
+
+type OutputBiddingsExtractionFiles struct {
+	Month string
+	Year  string
+	Files map[DataType]string
+}
+
+type UnitBiddings struct {
+	UgCode   int32           `json:"ug_code"`
+	Biddings []model.Bidding `json:"biddings"`
+}
+
+type BiddingsPayload struct {
+	ExtractionDate string
+	UnitsBiddings  []UnitBiddings
+	// Rejections are the rows left out because a value did not parse.
+	Rejections []model.RowRejection
+}
@@ -0,0 +1,36 @@
+package service
+
+import "github.com/farxc/envelopa-transparencia/internal/domain/model"
+
+// Government payment card data — cpgf/{year}{month}
+// Monthly statement transactions of every cardholder, keyed by managing unit.
+const (
+	CartaoPagamento DataType = iota + 600
+)
+
+const (
+	CartaoPagamentoDataType = "_CPGF.csv"
+)
+
+var CardExpenseDataTypeNames = map[DataType]string{
+	CartaoPagamento: "Cartão de Pagamento",
+}
+
+type OutputCardExpensesExtractionFiles struct {
+	Month string
+	Year  string
+	File  string
+}
+
+type UnitCardExpenses struct {
+	UgCode       int32               `json:"ug_code"`
+	CardExpenses []model.CardExpense `json:"card_expenses"`
+}
+
+type CardExpensesPayload struct {
+	ExtractionDate    string
+	ReferenceMonth    string
+	UnitsCardExpenses []UnitCardExpenses
+	// Rejections are the rows left out because a value did not parse.
+	Rejections []model.RowRejection
+}
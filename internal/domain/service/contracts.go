@@ -0,0 +1,31 @@
+package service
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type GetContractsFilter struct {
+	ManagementUnitCodes []string
+	ContractNumbers     []string
+	StartDate           time.Time
+	EndDate             time.Time
+}
+
+type ContractInformation struct {
+	ContractNumber     string         `db:"contract_number" json:"contract_number"`
+	ManagementUnitCode int32          `db:"management_unit_code" json:"management_unit_code"`
+	ManagementUnitName string         `db:"management_unit_name" json:"management_unit_name"`
+	Object             string         `db:"object" json:"object"`
+	Situation          string         `db:"situation" json:"situation"`
+	Process            string         `db:"process" json:"process"`
+	SignatureDate      time.Time      `db:"signature_date" json:"signature_date"`
+	StartDate          time.Time      `db:"start_date" json:"start_date"`
+	EndDate            time.Time      `db:"end_date" json:"end_date"`
+	ContractorCode     string         `db:"contractor_code" json:"contractor_code"`
+	ContractorName     string         `db:"contractor_name" json:"contractor_name"`
+	InitialValue       float64        `db:"initial_value" json:"initial_value"`
+	FinalValue         float64        `db:"final_value" json:"final_value"`
+	CommitmentCodes    pq.StringArray `db:"commitment_codes" json:"commitment_codes" swaggertype:"array,string"`
+}
@@ -0,0 +1,38 @@
+package service
+
+import "github.com/farxc/envelopa-transparencia/internal/domain/model"
+
+// Contracts data — compras/{year}{month}
+// Monthly download with one file per contract and one per contracted item.
+const (
+	ComprasContrato DataType = iota + 200
+	ComprasItemContrato
+)
+
+const (
+	ComprasContratoDataType     = "_Compras.csv"
+	ComprasItemContratoDataType = "_ItemCompra.csv"
+)
+
+var ContractDataTypeNames = map[DataType]string{
+	ComprasContrato:     "Compras Contrato",
+	ComprasItemContrato: "Compras Item Contrato",
+}
+
+type OutputContractsExtractionFiles struct {
+	Month string
+	Year  string
+	Files map[DataType]string
+}
+
+type UnitContracts struct {
+	UgCode    int32            `json:"ug_code"`
+	Contracts []model.Contract `json:"contracts"`
+}
+
+type ContractsPayload struct {
+	ExtractionDate string
+	UnitsContracts []UnitContracts
+	// Rejections are the rows left out because a value did not parse.
+	Rejections []model.RowRejection
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Failure kinds shared by the portal client and the pipelines. Callers branch
+// on them with errors.Is instead of inspecting messages.
+var (
+	// ErrNotPublished means the portal has no file for the period yet (HTTP 404).
+	ErrNotPublished = errors.New("dataset not published yet")
+	// ErrThrottled means the portal asked us to slow down (HTTP 429 or 503).
+	ErrThrottled = errors.New("portal throttled the request")
+	// ErrNetwork covers transport failures, timeouts and other 5xx responses.
+	ErrNetwork = errors.New("network failure")
+	// ErrCorruptArchive means the downloaded file is not a readable zip.
+	ErrCorruptArchive = errors.New("corrupt archive")
+	// ErrEmptyDataset means the file was published but holds no usable rows.
+	ErrEmptyDataset = errors.New("empty dataset")
+	// ErrSchemaDrift means a file's columns no longer match what the extractor
+	// expects and the drift policy does not allow loading it.
+	ErrSchemaDrift = errors.New("schema drift")
+)
+
+// DownloadError describes a failed download. Kind is one of the Err* values
+// above, or nil when the failure fits none of them.
+type DownloadError struct {
+	Kind       error
+	URL        string
+	StatusCode int
+	// RetryAfter is the delay requested by the server, if any.
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *DownloadError) Error() string {
+	msg := "download failed: " + e.URL
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" status=%d", e.StatusCode)
+	}
+	if e.Kind != nil {
+		msg += ": " + e.Kind.Error()
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *DownloadError) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// IsTransient reports whether err is worth retrying as is: throttling, network
+// failures and corrupt archives, which are downloaded again.
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrThrottled) || errors.Is(err, ErrNetwork) || errors.Is(err, ErrCorruptArchive)
+}
+
+// UnitLoadError reports the units a loader could not commit. Loaders that
+// commit one transaction per unit keep going after a failed unit, so every
+// unit not listed here was loaded.
+type UnitLoadError struct {
+	// Units maps the code of each failed unit to its error.
+	Units map[string]error
+}
+
+func (e *UnitLoadError) Error() string {
+	codes := make([]string, 0, len(e.Units))
+	for code := range e.Units {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	failures := make([]string, len(codes))
+	for i, code := range codes {
+		failures[i] = fmt.Sprintf("%s: %v", code, e.Units[code])
+	}
+	return fmt.Sprintf("failed to load %d unit(s): %s", len(codes), strings.Join(failures, "; "))
+}
+
+func (e *UnitLoadError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Units))
+	for _, err := range e.Units {
+		errs = append(errs, err)
+	}
+	return errs
+}
@@ -34,11 +34,19 @@ type GlobalSummary struct {
 	ExecutionPercentage   float64 `json:"execution_percentage" db:"execution_percentage"`
 }
 
+// TopFavored ranks favored entities by what they received through regular
+// payments and government payment card (CPGF) transactions combined.
 type TopFavored struct {
-	FavoredCode    string  `db:"favored_code" json:"favored_code"`
-	FavoredName    string  `db:"favored_name" json:"favored_name"`
-	TotalPaidValue float64 `db:"total_paid_value" json:"total_paid_value"`
-	PaymentsCount  int     `db:"payments_count" json:"payments_count"`
+	FavoredCode           string  `db:"favored_code" json:"favored_code"`
+	FavoredName           string  `db:"favored_name" json:"favored_name"`
+	TotalPaidValue        float64 `db:"total_paid_value" json:"total_paid_value"`
+	PaymentsCount         int     `db:"payments_count" json:"payments_count"`
+	PaymentsPaidValue     float64 `db:"payments_paid_value" json:"payments_paid_value"`
+	CardPaidValue         float64 `db:"card_paid_value" json:"card_paid_value"`
+	CardTransactionsCount int     `db:"card_transactions_count" json:"card_transactions_count"`
+	// Sanctioned is set when any payment or card transaction happened while the
+	// favored entity was on the CEIS or CNEP list.
+	Sanctioned bool `db:"sanctioned" json:"sanctioned"`
 }
 
 type ExpensesByCategory struct {
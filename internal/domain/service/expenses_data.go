@@ -1,8 +1,9 @@
 package service
 
 import (
+	"fmt"
+
 	"github.com/farxc/envelopa-transparencia/internal/domain/model"
-	"github.com/go-gota/gota/dataframe"
 )
 
 type DataType int
@@ -33,6 +34,7 @@ const (
 	DespesasPagamentoListaBancosDataType         = "_Despesas_Pagamento_ListaBancos.csv"
 	DespesasPagamentoListaFaturasDataType        = "_Despesas_Pagamento_ListaFaturas.csv"
 	DespesasPagamentoListaPrecatoriosDataType    = "_Despesas_Pagamento_ListaPrecatorios.csv"
+	DespesasPagamentoFavorecidosFinaisDataType   = "_Despesas_Pagamento_FavorecidosFinais.csv"
 )
 
 var DataTypeNames = map[DataType]string{
@@ -46,16 +48,17 @@ var DataTypeNames = map[DataType]string{
 	DespesasPagamentoListaBancos:         "Despesas Pagamento Lista Bancos",
 	DespesasPagamentoListaFaturas:        "Despesas Pagamento Lista Faturas",
 	DespesasPagamentoListaPrecatorios:    "Despesas Pagamento Lista Precatórios",
+	DespesasPagamentoFavoricidosFinais:   "Despesas Pagamento Favorecidos Finais",
 }
 
-type MatchingDataframe struct {
-	Dataframe dataframe.DataFrame
-	Type      DataType
-}
-
-type CommitmentItems struct {
-	CommitmentCode string
-	ItemsDf        dataframe.DataFrame
+// String returns the human readable name of the data type, whichever dataset it belongs to.
+func (d DataType) String() string {
+	for _, names := range []map[DataType]string{DataTypeNames, ExecutionDataTypeNames, ContractDataTypeNames, BiddingDataTypeNames, AgreementDataTypeNames, AmendmentDataTypeNames, CardExpenseDataTypeNames, SanctionDataTypeNames, RevenueDataTypeNames, TravelDataTypeNames} {
+		if name, ok := names[d]; ok {
+			return name
+		}
+	}
+	return fmt.Sprintf("DataType(%d)", int(d))
 }
 
 type OutputExpensesExtractionFiles struct {
@@ -66,6 +69,7 @@ type OutputExpensesExtractionFiles struct {
 type UnitsExpenses struct {
 	UgCode                     string                            `json:"ug_code"`
 	UgName                     string                            `json:"ug_name"`
+	ManagementCode             string                            `json:"management_code"`
 	Commitments                []model.Commitment                `json:"commitments"`
 	Liquidations               []model.Liquidation               `json:"liquidations"`
 	Payments                   []model.Payment                   `json:"payments"`
@@ -75,4 +79,6 @@ type UnitsExpenses struct {
 type ExpensesPayload struct {
 	ExtractionDate string          `json:"extraction_date"`
 	UnitsExpenses  []UnitsExpenses `json:"units"`
+	// Rejections are the rows left out because a value did not parse.
+	Rejections []model.RowRejection `json:"-"`
 }
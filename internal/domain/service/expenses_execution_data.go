@@ -32,4 +32,6 @@ type UnitExpenseExecution struct {
 type ExpensesExecutionPayload struct {
 	ExtractionDate string
 	UnitsExpenses  []UnitExpenseExecution
+	// Rejections are the rows left out because a value did not parse.
+	Rejections []model.RowRejection
 }
@@ -1,13 +1,51 @@
 package service
 
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+// DownloadResult points at a complete, validated archive and fingerprints it.
+// Failures are reported as a *DownloadError instead.
 type DownloadResult struct {
-	Success    bool
-	OutputPath string
+	// Key is the archive's path on the portal and Name its path in a mirror
+	// or the local zip directory.
+	Key          string
+	Name         string
+	URL          string
+	OutputPath   string
+	ETag         string
+	LastModified string
+	Size         int64
+	SHA256       string
 }
 
 type TransparencyPortalClient interface {
-	FetchExpensesData(date string) DownloadResult
-	ExtractExpenses(cfg ExpensesExtractionConfig) (*ExpensesPayload, error)
-	FetchExpensesExecution(month, year string) DownloadResult
-	ExtractExpensesExecution(cfg ExpensesExecutionExtractionConfig) (*ExpensesExecutionPayload, error)
+	FetchExpensesData(ctx context.Context, date string) (DownloadResult, error)
+	ExtractExpenses(ctx context.Context, cfg ExpensesExtractionConfig) (*ExpensesPayload, error)
+	FetchExpensesExecution(ctx context.Context, month, year string) (DownloadResult, error)
+	ExtractExpensesExecution(ctx context.Context, cfg ExpensesExecutionExtractionConfig) (*ExpensesExecutionPayload, error)
+	FetchContracts(ctx context.Context, month, year string) (DownloadResult, error)
+	ExtractContracts(ctx context.Context, cfg ContractsExtractionConfig) (*ContractsPayload, error)
+	FetchBiddings(ctx context.Context, month, year string) (DownloadResult, error)
+	ExtractBiddings(ctx context.Context, cfg BiddingsExtractionConfig) (*BiddingsPayload, error)
+	FetchAgreements(ctx context.Context, month, year string) (DownloadResult, error)
+	ExtractAgreements(ctx context.Context, cfg AgreementsExtractionConfig) (*AgreementsPayload, error)
+	FetchAmendments(ctx context.Context, date string) (DownloadResult, error)
+	ExtractAmendments(ctx context.Context, cfg AmendmentsExtractionConfig) (*AmendmentsPayload, error)
+	FetchCardExpenses(ctx context.Context, month, year string) (DownloadResult, error)
+	ExtractCardExpenses(ctx context.Context, cfg CardExpensesExtractionConfig) (*CardExpensesPayload, error)
+	FetchSanctions(ctx context.Context, list DataType, date string) (DownloadResult, error)
+	ExtractSanctions(ctx context.Context, cfg SanctionsExtractionConfig) (*SanctionsPayload, error)
+	FetchRevenue(ctx context.Context, year string) (DownloadResult, error)
+	ExtractRevenue(ctx context.Context, cfg RevenueExtractionConfig) (*RevenuePayload, error)
+	FetchTravel(ctx context.Context, year string) (DownloadResult, error)
+	ExtractTravel(ctx context.Context, cfg TravelExtractionConfig) (*TravelPayload, error)
+	// CheckSchema compares the header of each extracted file with the columns
+	// its extractor expects. Under the fail policy a removed or renamed column
+	// also yields an error wrapping ErrSchemaDrift.
+	CheckSchema(ctx context.Context, files map[DataType]string) ([]model.SchemaDrift, error)
+	// RecheckSource reports whether the portal republished a previously ingested file.
+	RecheckSource(ctx context.Context, fp model.SourceFingerprint) (bool, error)
 }
@@ -9,3 +9,19 @@ type ExtractionConfig[T any] struct {
 type ExpensesExtractionConfig = ExtractionConfig[OutputExpensesExtractionFiles]
 
 type ExpensesExecutionExtractionConfig = ExtractionConfig[OutputExpensesExecutionExtractionFiles]
+
+type ContractsExtractionConfig = ExtractionConfig[OutputContractsExtractionFiles]
+
+type BiddingsExtractionConfig = ExtractionConfig[OutputBiddingsExtractionFiles]
+
+type AgreementsExtractionConfig = ExtractionConfig[OutputAgreementsExtractionFiles]
+
+type AmendmentsExtractionConfig = ExtractionConfig[OutputAmendmentsExtractionFiles]
+
+type CardExpensesExtractionConfig = ExtractionConfig[OutputCardExpensesExtractionFiles]
+
+type SanctionsExtractionConfig = ExtractionConfig[OutputSanctionsExtractionFiles]
+
+type RevenueExtractionConfig = ExtractionConfig[OutputRevenueExtractionFiles]
+
+type TravelExtractionConfig = ExtractionConfig[OutputTravelExtractionFiles]
@@ -7,4 +7,12 @@ import (
 type Loader interface {
 	LoadExpenses(ctx context.Context, payload *ExpensesPayload) error
 	LoadExpensesExecution(ctx context.Context, payload *ExpensesExecutionPayload) error
+	LoadContracts(ctx context.Context, payload *ContractsPayload) error
+	LoadBiddings(ctx context.Context, payload *BiddingsPayload) error
+	LoadAgreements(ctx context.Context, payload *AgreementsPayload) error
+	LoadAmendments(ctx context.Context, payload *AmendmentsPayload) error
+	LoadCardExpenses(ctx context.Context, payload *CardExpensesPayload) error
+	LoadSanctions(ctx context.Context, payload *SanctionsPayload) error
+	LoadRevenue(ctx context.Context, payload *RevenuePayload) error
+	LoadTravel(ctx context.Context, payload *TravelPayload) error
 }
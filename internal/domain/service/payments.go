@@ -0,0 +1,50 @@
+package service
+
+import "time"
+
+type PaymentFinalBeneficiaryInfo struct {
+	FinalBeneficiaryCode string  `db:"final_beneficiary_code" json:"final_beneficiary_code"`
+	FinalBeneficiaryName string  `db:"final_beneficiary_name" json:"final_beneficiary_name"`
+	ReceivedValueBRL     float64 `db:"received_value_brl" json:"received_value_brl"`
+}
+
+type PaymentBankTransferInfo struct {
+	ListCode string  `db:"list_code" json:"list_code"`
+	BankCode string  `db:"bank_code" json:"bank_code"`
+	BankName string  `db:"bank_name" json:"bank_name"`
+	ValueBRL float64 `db:"value_brl" json:"value_brl"`
+}
+
+type PaymentInvoiceInfo struct {
+	ListCode      string  `db:"list_code" json:"list_code"`
+	InvoiceNumber string  `db:"invoice_number" json:"invoice_number"`
+	FavoredCode   string  `db:"favored_code" json:"favored_code"`
+	FavoredName   string  `db:"favored_name" json:"favored_name"`
+	ValueBRL      float64 `db:"value_brl" json:"value_brl"`
+}
+
+type PaymentCourtOrderInfo struct {
+	ListCode      string  `db:"list_code" json:"list_code"`
+	ProcessNumber string  `db:"process_number" json:"process_number"`
+	FavoredCode   string  `db:"favored_code" json:"favored_code"`
+	FavoredName   string  `db:"favored_name" json:"favored_name"`
+	ValueBRL      float64 `db:"value_brl" json:"value_brl"`
+}
+
+type PaymentDetail struct {
+	PaymentCode           string                        `db:"payment_code" json:"payment_code"`
+	PaymentEmissionDate   time.Time                     `db:"payment_emission_date" json:"payment_emission_date"`
+	DocumentType          string                        `db:"document_type" json:"document_type"`
+	FavoredCode           string                        `db:"favored_code" json:"favored_code"`
+	FavoredName           string                        `db:"favored_name" json:"favored_name"`
+	ManagementUnitCode    int                           `db:"management_unit_code" json:"management_unit_code"`
+	ManagementUnitName    string                        `db:"management_unit_name" json:"management_unit_name"`
+	ManagementCode        int                           `db:"management_code" json:"management_code"`
+	Process               string                        `db:"process" json:"process"`
+	Observation           string                        `db:"observation" json:"observation"`
+	ConvertedPaymentValue float64                       `db:"converted_payment_value" json:"converted_payment_value"`
+	FinalBeneficiaries    []PaymentFinalBeneficiaryInfo `json:"final_beneficiaries"`
+	BankTransfers         []PaymentBankTransferInfo     `json:"bank_transfers"`
+	Invoices              []PaymentInvoiceInfo          `json:"invoices"`
+	CourtOrders           []PaymentCourtOrderInfo       `json:"court_orders"`
+}
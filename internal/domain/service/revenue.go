@@ -0,0 +1,11 @@
+package service
+
+// BudgetBalance puts the revenue collected by a management's units next to
+// what they committed and paid in the same month.
+type BudgetBalance struct {
+	YearAndMonth      string  `db:"year_and_month" json:"year_and_month"`
+	CollectedValueBRL float64 `db:"collected_value_brl" json:"collected_value_brl"`
+	CommittedValueBRL float64 `db:"committed_value_brl" json:"committed_value_brl"`
+	PaidValueBRL      float64 `db:"paid_value_brl" json:"paid_value_brl"`
+	BalanceBRL        float64 `db:"balance_brl" json:"balance_brl"`
+}
@@ -0,0 +1,35 @@
+package service
+
+import "github.com/farxc/envelopa-transparencia/internal/domain/model"
+
+// Revenue data — receitas/{year}
+// Cumulative yearly file of forecast, launched and collected revenue per managing unit.
+const (
+	Receitas DataType = iota + 800
+)
+
+const (
+	ReceitasDataType = "_Receitas.csv"
+)
+
+var RevenueDataTypeNames = map[DataType]string{
+	Receitas: "Receitas",
+}
+
+type OutputRevenueExtractionFiles struct {
+	Year string
+	File string
+}
+
+type UnitRevenues struct {
+	UgCode   int32           `json:"ug_code"`
+	Revenues []model.Revenue `json:"revenues"`
+}
+
+type RevenuePayload struct {
+	ExtractionDate string
+	FiscalYear     int32
+	UnitsRevenues  []UnitRevenues
+	// Rejections are the rows left out because a value did not parse.
+	Rejections []model.RowRejection
+}
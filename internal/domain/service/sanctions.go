@@ -0,0 +1,20 @@
+package service
+
+import "time"
+
+// SanctionedFavored is a commitment or payment whose favored entity was under
+// a CEIS/CNEP sanction on the document date.
+type SanctionedFavored struct {
+	Source           string     `db:"source" json:"source"`
+	DocumentCode     string     `db:"document_code" json:"document_code"`
+	DocumentDate     time.Time  `db:"document_date" json:"document_date"`
+	Value            float64    `db:"value" json:"value"`
+	FavoredCode      string     `db:"favored_code" json:"favored_code"`
+	FavoredName      string     `db:"favored_name" json:"favored_name"`
+	List             string     `db:"list" json:"list"`
+	SanctionCode     string     `db:"sanction_code" json:"sanction_code"`
+	Category         string     `db:"category" json:"category"`
+	SanctioningOrgan string     `db:"sanctioning_organ" json:"sanctioning_organ"`
+	StartDate        time.Time  `db:"start_date" json:"start_date"`
+	EndDate          *time.Time `db:"end_date" json:"end_date"`
+}
@@ -0,0 +1,32 @@
+package service
+
+import "github.com/farxc/envelopa-transparencia/internal/domain/model"
+
+// Sanction lists — ceis/{date} and cnep/{date}
+// Daily snapshots sharing the same layout; CNEP adds the fine value.
+const (
+	SancoesCEIS DataType = iota + 700
+	SancoesCNEP
+)
+
+const (
+	SancoesCEISDataType = "_CEIS.csv"
+	SancoesCNEPDataType = "_CNEP.csv"
+)
+
+var SanctionDataTypeNames = map[DataType]string{
+	SancoesCEIS: "CEIS",
+	SancoesCNEP: "CNEP",
+}
+
+type OutputSanctionsExtractionFiles struct {
+	Date  string
+	Files map[DataType]string
+}
+
+type SanctionsPayload struct {
+	ExtractionDate string
+	Sanctions      []model.Sanction
+	// Rejections are the rows left out because a value did not parse.
+	Rejections []model.RowRejection
+}
@@ -0,0 +1,29 @@
+package service
+
+import "time"
+
+type GetTravelCostsFilter struct {
+	ManagementUnitCodes []string
+	StartDate           time.Time
+	EndDate             time.Time
+	// GroupBy is one of TravelGroupByRole, TravelGroupByDestination or TravelGroupByUnit.
+	GroupBy string
+}
+
+const (
+	TravelGroupByRole        = "role"
+	TravelGroupByDestination = "destination"
+	TravelGroupByUnit        = "unit"
+)
+
+// TravelCost aggregates trip costs for one traveler role, destination or paying unit.
+// TotalValueBRL is per diem plus tickets plus other expenses, minus returned amounts.
+type TravelCost struct {
+	GroupKey              string  `db:"group_key" json:"group_key"`
+	TripsCount            int     `db:"trips_count" json:"trips_count"`
+	PerDiemValueBRL       float64 `db:"per_diem_value_brl" json:"per_diem_value_brl"`
+	TicketsValueBRL       float64 `db:"tickets_value_brl" json:"tickets_value_brl"`
+	OtherExpensesValueBRL float64 `db:"other_expenses_value_brl" json:"other_expenses_value_brl"`
+	ReturnedValueBRL      float64 `db:"returned_value_brl" json:"returned_value_brl"`
+	TotalValueBRL         float64 `db:"total_value_brl" json:"total_value_brl"`
+}
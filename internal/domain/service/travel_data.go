@@ -0,0 +1,41 @@
+package service
+
+import "github.com/farxc/envelopa-transparencia/internal/domain/model"
+
+// Official travel data — viagens/{year}
+// Yearly download with trips, the payments of each trip and the tickets bought.
+// Only payments carry the paying unit, so they drive the unit filter.
+const (
+	Viagem DataType = iota + 900
+	ViagemPagamento
+	ViagemPassagem
+)
+
+const (
+	ViagemDataType          = "_Viagem.csv"
+	ViagemPagamentoDataType = "_Pagamento.csv"
+	ViagemPassagemDataType  = "_Passagem.csv"
+)
+
+var TravelDataTypeNames = map[DataType]string{
+	Viagem:          "Viagem",
+	ViagemPagamento: "Pagamento Viagem",
+	ViagemPassagem:  "Passagem",
+}
+
+type OutputTravelExtractionFiles struct {
+	Year  string
+	Files map[DataType]string
+}
+
+type UnitTrips struct {
+	UgCode int32        `json:"ug_code"`
+	Trips  []model.Trip `json:"trips"`
+}
+
+type TravelPayload struct {
+	ExtractionDate string
+	UnitsTrips     []UnitTrips
+	// Rejections are the rows left out because a value did not parse.
+	Rejections []model.RowRejection
+}
@@ -0,0 +1,55 @@
+package portal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+func (c *transparencyPortalClient) FetchAgreements(ctx context.Context, month, year string) (service.DownloadResult, error) {
+	return c.fetch(ctx, Archive{Key: "convenios/" + year + month, Name: "agreements/" + year + month + "_Convenios.zip"})
+}
+
+func (c *transparencyPortalClient) ExtractAgreements(ctx context.Context, cfg service.AgreementsExtractionConfig) (*service.AgreementsPayload, error) {
+	const component = "DataExtractor"
+	rejects := &Rejections{}
+	ref := cfg.Extraction.Year + cfg.Extraction.Month
+	referenceMonth := cfg.Extraction.Year + "/" + cfg.Extraction.Month
+
+	if cfg.IsManagingCode {
+		return nil, fmt.Errorf("agreements can only be filtered by management unit code")
+	}
+
+	unitIndex := make(map[int32]int)
+	var units []service.UnitAgreements
+
+	matched, err := FindRows(ctx, cfg.Extraction.File, service.Convenio, cfg.Codes, string(MatchByGrantorCode), c.debug, rejects, func(row Record) error {
+		agreement, err := DfRowToAgreement(row)
+		if err != nil {
+			return fmt.Errorf("failed to map agreement row: %w", err)
+		}
+		for i := range agreement.Disbursements {
+			agreement.Disbursements[i].ReferenceMonth = referenceMonth
+		}
+
+		i, ok := unitIndex[agreement.GrantorCode]
+		if !ok {
+			i = len(units)
+			unitIndex[agreement.GrantorCode] = i
+			units = append(units, service.UnitAgreements{UgCode: agreement.GrantorCode})
+		}
+		units[i].Agreements = append(units[i].Agreements, agreement)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Info(component, "Filtered agreement rows: ref=%s rows=%d units=%d", ref, matched, len(units))
+
+	return &service.AgreementsPayload{
+		ExtractionDate:  cfg.Extraction.Month + "/" + cfg.Extraction.Year,
+		UnitsAgreements: units,
+		Rejections:      rejects.List(),
+	}, nil
+}
@@ -0,0 +1,53 @@
+package portal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+// FetchAmendments downloads the current emendas snapshot. The portal publishes
+// a single file, so date only names the local copy.
+func (c *transparencyPortalClient) FetchAmendments(ctx context.Context, date string) (service.DownloadResult, error) {
+	return c.fetch(ctx, Archive{Key: "emendas-parlamentares/UNICO", Name: "amendments/emendas_" + date + ".zip"})
+}
+
+// ExtractAmendments reads every amendment: the file has no managing unit
+// column, so cfg.Codes does not apply. Amendments are grouped by author.
+func (c *transparencyPortalClient) ExtractAmendments(ctx context.Context, cfg service.AmendmentsExtractionConfig) (*service.AmendmentsPayload, error) {
+	const component = "DataExtractor"
+	rejects := &Rejections{}
+
+	authorIndex := make(map[int32]int)
+	var authors []service.AuthorAmendments
+
+	matched, err := ScanRows(ctx, cfg.Extraction.File, service.EmendasParlamentares, c.debug, rejects, func(row Record) error {
+		amendment, err := DfRowToAmendment(row)
+		if err != nil {
+			return fmt.Errorf("failed to map amendment row: %w", err)
+		}
+
+		i, ok := authorIndex[amendment.AuthorCode]
+		if !ok {
+			i = len(authors)
+			authorIndex[amendment.AuthorCode] = i
+			authors = append(authors, service.AuthorAmendments{
+				Author: model.AmendmentAuthor{AuthorCode: amendment.AuthorCode, AuthorName: amendment.AuthorName},
+			})
+		}
+		authors[i].Amendments = append(authors[i].Amendments, amendment)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Info(component, "Read amendment rows: date=%s rows=%d authors=%d", cfg.Extraction.Date, matched, len(authors))
+
+	return &service.AmendmentsPayload{
+		ExtractionDate:   cfg.Extraction.Date,
+		AuthorAmendments: authors,
+		Rejections:       rejects.List(),
+	}, nil
+}
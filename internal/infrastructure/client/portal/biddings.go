@@ -0,0 +1,126 @@
+package portal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+func (c *transparencyPortalClient) FetchBiddings(ctx context.Context, month, year string) (service.DownloadResult, error) {
+	return c.fetch(ctx, Archive{Key: "licitacoes/" + year + month, Name: "biddings/" + year + month + "_Licitacoes.zip"})
+}
+
+func (c *transparencyPortalClient) ExtractBiddings(ctx context.Context, cfg service.BiddingsExtractionConfig) (*service.BiddingsPayload, error) {
+	const component = "DataExtractor"
+	rejects := &Rejections{}
+	ref := cfg.Extraction.Year + cfg.Extraction.Month
+
+	if cfg.IsManagingCode {
+		return nil, fmt.Errorf("biddings can only be filtered by management unit code")
+	}
+
+	c.logger.Info(component, "Starting biddings extraction: ref=%s codesCount=%d", ref, len(cfg.Codes))
+
+	// The three files are keyed by unit, so they can be scanned in a single pass.
+	var (
+		mu           sync.Mutex
+		biddings     []model.Bidding
+		items        []model.BiddingItem
+		participants []model.BiddingParticipant
+	)
+	column := string(MatchByUGCode)
+	err := scanFiles(ctx, cfg.Extraction.Files, ref, []rowScan{
+		{dfType: service.Licitacao, codes: cfg.Codes, column: column, handle: func(row Record) error {
+			bidding, err := DfRowToBidding(row)
+			if err != nil {
+				return fmt.Errorf("failed to map bidding row: %w", err)
+			}
+			bidding.ReferenceMonth = cfg.Extraction.Year + "/" + cfg.Extraction.Month
+			mu.Lock()
+			biddings = append(biddings, bidding)
+			mu.Unlock()
+			return nil
+		}},
+		{dfType: service.LicitacaoItem, codes: cfg.Codes, column: column, handle: func(row Record) error {
+			item, err := DfRowToBiddingItem(row)
+			if err != nil {
+				return fmt.Errorf("failed to map bidding item row: %w", err)
+			}
+			mu.Lock()
+			items = append(items, item)
+			mu.Unlock()
+			return nil
+		}},
+		{dfType: service.LicitacaoParticipante, codes: cfg.Codes, column: column, handle: func(row Record) error {
+			participant, err := DfRowToBiddingParticipant(row)
+			if err != nil {
+				return fmt.Errorf("failed to map bidding participant row: %w", err)
+			}
+			mu.Lock()
+			participants = append(participants, participant)
+			mu.Unlock()
+			return nil
+		}},
+	}, c.debug, rejects, c.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(biddings) == 0 {
+		c.logger.Warn(component, "No biddings found for the provided codes: ref=%s", ref)
+		return &service.BiddingsPayload{ExtractionDate: cfg.Extraction.Month + "/" + cfg.Extraction.Year, Rejections: rejects.List()}, nil
+	}
+
+	byKey := make(map[string]int, len(biddings))
+	for i, b := range biddings {
+		byKey[biddingKey(b.BiddingNumber, b.ManagementUnitCode, b.ModalityCode)] = i
+	}
+
+	orphans := 0
+	for _, item := range items {
+		i, ok := byKey[biddingKey(item.BiddingNumber, item.ManagementUnitCode, item.ModalityCode)]
+		if !ok {
+			orphans++
+			continue
+		}
+		biddings[i].Items = append(biddings[i].Items, item)
+	}
+	for _, p := range participants {
+		i, ok := byKey[biddingKey(p.BiddingNumber, p.ManagementUnitCode, p.ModalityCode)]
+		if !ok {
+			orphans++
+			continue
+		}
+		biddings[i].Participants = append(biddings[i].Participants, p)
+	}
+	if orphans > 0 {
+		c.logger.Warn(component, "Bidding children without a matching bidding were dropped: ref=%s count=%d", ref, orphans)
+	}
+
+	unitIndex := make(map[int32]int)
+	var units []service.UnitBiddings
+	for _, b := range biddings {
+		i, ok := unitIndex[b.ManagementUnitCode]
+		if !ok {
+			i = len(units)
+			unitIndex[b.ManagementUnitCode] = i
+			units = append(units, service.UnitBiddings{UgCode: b.ManagementUnitCode})
+		}
+		units[i].Biddings = append(units[i].Biddings, b)
+	}
+
+	c.logger.Info(component, "Biddings extraction completed: ref=%s biddings=%d items=%d participants=%d", ref, len(biddings), len(items), len(participants))
+
+	return &service.BiddingsPayload{
+		ExtractionDate: cfg.Extraction.Month + "/" + cfg.Extraction.Year,
+		UnitsBiddings:  units,
+		Rejections:     rejects.List(),
+	}, nil
+}
+
+func biddingKey(number string, ugCode int32, modalityCode int16) string {
+	return fmt.Sprintf("%d/%d/%s", ugCode, modalityCode, number)
+}
@@ -0,0 +1,50 @@
+package portal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+func (c *transparencyPortalClient) FetchCardExpenses(ctx context.Context, month, year string) (service.DownloadResult, error) {
+	return c.fetch(ctx, Archive{Key: "cpgf/" + year + month, Name: "card_expenses/" + year + month + "_CPGF.zip"})
+}
+
+func (c *transparencyPortalClient) ExtractCardExpenses(ctx context.Context, cfg service.CardExpensesExtractionConfig) (*service.CardExpensesPayload, error) {
+	const component = "DataExtractor"
+	rejects := &Rejections{}
+	ref := cfg.Extraction.Year + cfg.Extraction.Month
+	referenceMonth := cfg.Extraction.Year + "/" + cfg.Extraction.Month
+
+	unitIndex := make(map[int32]int)
+	var units []service.UnitCardExpenses
+
+	matched, err := FindRows(ctx, cfg.Extraction.File, service.CartaoPagamento, cfg.Codes, string(MatchByCardUnitCode), c.debug, rejects, func(row Record) error {
+		expense, err := DfRowToCardExpense(row)
+		if err != nil {
+			return fmt.Errorf("failed to map card expense row: %w", err)
+		}
+		expense.ReferenceMonth = referenceMonth
+
+		i, ok := unitIndex[expense.ManagementUnitCode]
+		if !ok {
+			i = len(units)
+			unitIndex[expense.ManagementUnitCode] = i
+			units = append(units, service.UnitCardExpenses{UgCode: expense.ManagementUnitCode})
+		}
+		units[i].CardExpenses = append(units[i].CardExpenses, expense)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Info(component, "Filtered card expense rows: ref=%s rows=%d units=%d", ref, matched, len(units))
+
+	return &service.CardExpensesPayload{
+		ExtractionDate:    cfg.Extraction.Month + "/" + cfg.Extraction.Year,
+		ReferenceMonth:    referenceMonth,
+		UnitsCardExpenses: units,
+		Rejections:        rejects.List(),
+	}, nil
+}
@@ -0,0 +1,89 @@
+package portal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+func (c *transparencyPortalClient) ExtractContracts(ctx context.Context, cfg service.ContractsExtractionConfig) (*service.ContractsPayload, error) {
+	const component = "DataExtractor"
+	rejects := &Rejections{}
+	ref := cfg.Extraction.Year + cfg.Extraction.Month
+
+	if cfg.IsManagingCode {
+		return nil, fmt.Errorf("contracts can only be filtered by management unit code")
+	}
+
+	c.logger.Info(component, "Starting contracts extraction: ref=%s codesCount=%d", ref, len(cfg.Codes))
+
+	var contracts []model.Contract
+	err := scanFiles(ctx, cfg.Extraction.Files, ref, []rowScan{
+		{dfType: service.ComprasContrato, codes: cfg.Codes, column: string(MatchByUGCode), handle: func(row Record) error {
+			contract, err := DfRowToContract(row)
+			if err != nil {
+				return fmt.Errorf("failed to map contract row: %w", err)
+			}
+			contract.ReferenceMonth = cfg.Extraction.Year + "/" + cfg.Extraction.Month
+			contracts = append(contracts, contract)
+			return nil
+		}},
+	}, c.debug, rejects, c.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contracts) == 0 {
+		c.logger.Warn(component, "No contracts found for the provided codes: ref=%s", ref)
+		return &service.ContractsPayload{ExtractionDate: cfg.Extraction.Month + "/" + cfg.Extraction.Year, Rejections: rejects.List()}, nil
+	}
+
+	// Items only carry the contract number and the UG, so both are needed to
+	// attach them to the right contract.
+	byKey := make(map[string]int, len(contracts))
+	for i, contract := range contracts {
+		byKey[contractKey(contract.ContractNumber, contract.ManagementUnitCode)] = i
+	}
+
+	err = scanFiles(ctx, cfg.Extraction.Files, ref, []rowScan{
+		{dfType: service.ComprasItemContrato, codes: cfg.Codes, column: string(MatchByUGCode), handle: func(row Record) error {
+			item, err := DfRowToContractItem(row)
+			if err != nil {
+				return fmt.Errorf("failed to map contract item row: %w", err)
+			}
+			if i, ok := byKey[contractKey(item.ContractNumber, item.ManagementUnitCode)]; ok {
+				contracts[i].Items = append(contracts[i].Items, item)
+			}
+			return nil
+		}},
+	}, c.debug, rejects, c.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	unitIndex := make(map[int32]int)
+	var units []service.UnitContracts
+	for _, contract := range contracts {
+		i, ok := unitIndex[contract.ManagementUnitCode]
+		if !ok {
+			i = len(units)
+			unitIndex[contract.ManagementUnitCode] = i
+			units = append(units, service.UnitContracts{UgCode: contract.ManagementUnitCode})
+		}
+		units[i].Contracts = append(units[i].Contracts, contract)
+	}
+
+	c.logger.Info(component, "Contracts extraction completed: ref=%s contracts=%d units=%d", ref, len(contracts), len(units))
+
+	return &service.ContractsPayload{
+		ExtractionDate: cfg.Extraction.Month + "/" + cfg.Extraction.Year,
+		UnitsContracts: units,
+		Rejections:     rejects.List(),
+	}, nil
+}
+
+func contractKey(number string, ugCode int32) string {
+	return fmt.Sprintf("%d/%s", ugCode, number)
+}
@@ -0,0 +1,136 @@
+package portal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+)
+
+// dirSource reads archives from a local directory laid out like the zip
+// directory (root/<Archive.Name>), e.g. a copy of tmp/zips from an earlier run.
+type dirSource struct {
+	root string
+}
+
+// archiveMeta is kept next to a mirrored or downloaded archive as <name>.json
+// so the upstream validators survive the copy, or a later run reusing it.
+type archiveMeta struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func NewDirSource(root string) Source {
+	return &dirSource{root: root}
+}
+
+func (d *dirSource) Fetch(ctx context.Context, a Archive, outputPath string) (service.DownloadResult, error) {
+	src := filepath.Join(d.root, a.Name)
+	if _, err := os.Stat(src); errors.Is(err, os.ErrNotExist) {
+		return service.DownloadResult{}, &service.DownloadError{Kind: service.ErrNotPublished, URL: src, Err: err}
+	}
+	if err := filesystem.ValidateZip(src); err != nil {
+		return service.DownloadResult{}, &service.DownloadError{Kind: service.ErrCorruptArchive, URL: src, Err: err}
+	}
+	if err := copyFile(src, outputPath); err != nil {
+		return service.DownloadResult{}, &service.DownloadError{URL: src, Err: err}
+	}
+	size, sum, err := filesystem.FileDigest(outputPath)
+	if err != nil {
+		return service.DownloadResult{}, &service.DownloadError{URL: src, Err: err}
+	}
+
+	result := service.DownloadResult{URL: src, OutputPath: outputPath, Size: size, SHA256: sum}
+	if meta, err := readArchiveMeta(src); err == nil {
+		result.ETag = meta.ETag
+		result.LastModified = meta.LastModified
+	}
+	return result, nil
+}
+
+// Recheck compares the archive's current digest with the recorded one.
+func (d *dirSource) Recheck(ctx context.Context, fp model.SourceFingerprint) (bool, error) {
+	src := filepath.Join(d.root, fp.Name)
+	f, err := os.Open(src)
+	if err != nil {
+		kind := error(nil)
+		if errors.Is(err, os.ErrNotExist) {
+			kind = service.ErrNotPublished
+		}
+		return false, &service.DownloadError{Kind: kind, URL: src, Err: err}
+	}
+	defer f.Close()
+	return digestDiffers(fp, f)
+}
+
+func (d *dirSource) store(ctx context.Context, a Archive, path string, origin service.DownloadResult) error {
+	dst := filepath.Join(d.root, a.Name)
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	if err := copyFile(path, dst); err != nil {
+		return err
+	}
+	return writeArchiveMeta(dst, origin)
+}
+
+func (d *dirSource) evict(ctx context.Context, a Archive) error {
+	dst := filepath.Join(d.root, a.Name)
+	if err := os.Remove(dst + ".json"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Remove(dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func writeArchiveMeta(path string, origin service.DownloadResult) error {
+	meta, err := json.Marshal(archiveMeta{URL: origin.URL, ETag: origin.ETag, LastModified: origin.LastModified})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path+".json", meta, 0o644)
+}
+
+func readArchiveMeta(path string) (archiveMeta, error) {
+	var meta archiveMeta
+	data, err := os.ReadFile(path + ".json")
+	if err != nil {
+		return meta, err
+	}
+	return meta, json.Unmarshal(data, &meta)
+}
+
+// copyFile copies src to dst through a temporary file, so dst is never partial.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	partPath := dst + ".part"
+	out, err := os.Create(partPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", partPath, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(partPath)
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(partPath)
+		return err
+	}
+	return os.Rename(partPath, dst)
+}
@@ -0,0 +1,441 @@
+package portal
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+)
+
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3"
+
+// downloadPolicy controls retries and timeouts of the portal downloader.
+type downloadPolicy struct {
+	maxAttempts    int
+	baseBackoff    time.Duration
+	maxBackoff     time.Duration
+	maxRetryAfter  time.Duration
+	connectTimeout time.Duration
+	headerTimeout  time.Duration
+	// readTimeout aborts a transfer that receives no bytes for this long.
+	readTimeout time.Duration
+}
+
+var defaultDownloadPolicy = downloadPolicy{
+	maxAttempts:    5,
+	baseBackoff:    2 * time.Second,
+	maxBackoff:     2 * time.Minute,
+	maxRetryAfter:  10 * time.Minute,
+	connectTimeout: 15 * time.Second,
+	headerTimeout:  60 * time.Second,
+	readTimeout:    60 * time.Second,
+}
+
+// httpSource reads archives over HTTP: from the portal itself or, with
+// signing, from an S3-compatible mirror.
+type httpSource struct {
+	logger *logger.Logger
+	client *http.Client
+	policy downloadPolicy
+	// sleep waits between download attempts, returning early with ctx's error
+	// when ctx is done; tests replace it to run instantly.
+	sleep func(ctx context.Context, d time.Duration) error
+	// urlFor maps an archive to its location on this source.
+	urlFor func(a Archive) string
+	// validators reads the ETag and Last-Modified that identify the upstream
+	// version of a response.
+	validators func(h http.Header) (etag, lastModified string)
+	// sign, when set, authenticates each request.
+	sign func(req *http.Request)
+}
+
+const PortalTransparenciaURL = "https://portaldatransparencia.gov.br/download-de-dados/"
+
+// NewPortalSource reads archives from the live portal at baseURL
+// (PortalTransparenciaURL when empty).
+func NewPortalSource(logger *logger.Logger, baseURL string) Source {
+	if baseURL == "" {
+		baseURL = PortalTransparenciaURL
+	}
+	return newHTTPSource(logger, func(a Archive) string { return baseURL + a.Key })
+}
+
+func newHTTPSource(logger *logger.Logger, urlFor func(a Archive) string) *httpSource {
+	return &httpSource{
+		logger:     logger,
+		client:     newHTTPClient(defaultDownloadPolicy),
+		policy:     defaultDownloadPolicy,
+		sleep:      sleepContext,
+		urlFor:     urlFor,
+		validators: headerValidators,
+	}
+}
+
+func (s *httpSource) Fetch(ctx context.Context, a Archive, outputPath string) (service.DownloadResult, error) {
+	return s.download(ctx, s.urlFor(a), outputPath, a.Key)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// newRequest builds a request carrying the user agent and, if needed, a signature.
+func (s *httpSource) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if s.sign != nil {
+		s.sign(req)
+	}
+	return req, nil
+}
+
+func headerValidators(h http.Header) (string, string) {
+	return h.Get("ETag"), h.Get("Last-Modified")
+}
+
+func newHTTPClient(policy downloadPolicy) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   policy.connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = policy.connectTimeout
+	transport.ResponseHeaderTimeout = policy.headerTimeout
+
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			req.Header.Set("User-Agent", userAgent)
+			return nil
+		},
+	}
+}
+
+/*
+download saves the archive at url to outputPath. ref identifies the requested
+period (date or year+month) in the logs.
+
+Bytes are written to outputPath+".part" and resumed with a Range request when a
+previous attempt or run was interrupted. The headers of the response that
+started the part file are kept next to it, and the Range request is made
+conditional on them with If-Range, so a file republished in between is
+downloaded again from the start instead of being spliced. Transient failures (see
+service.IsTransient) are retried with exponential backoff and jitter, honouring
+Retry-After. The file is only renamed to outputPath once it opens as a valid
+zip, so a path that exists is always a complete download. Cancelling ctx
+aborts the transfer without retrying and keeps the part file for the next run.
+
+Failures are returned as a *service.DownloadError classified by kind.
+*/
+func (s *httpSource) download(ctx context.Context, url, outputPath, ref string) (service.DownloadResult, error) {
+	const component = "Downloader"
+	partPath := outputPath + ".part"
+
+	s.logger.Debug(component, "Starting download for ref=%s url=%s", ref, url)
+
+	// header keeps the validators of the response that completed the file.
+	var header http.Header
+	for attempt := 1; ; attempt++ {
+		h, err := s.fetchToFile(ctx, url, partPath)
+		if h != nil {
+			header = h
+		}
+		if err == nil {
+			if zipErr := filesystem.ValidateZip(partPath); zipErr != nil {
+				// A corrupt archive cannot be resumed; start over on the next attempt.
+				discardPart(partPath)
+				err = &service.DownloadError{Kind: service.ErrCorruptArchive, URL: url, Err: zipErr}
+			}
+		}
+
+		if err == nil {
+			if err := os.Rename(partPath, outputPath); err != nil {
+				s.logger.Error(component, "Failed to move download into place: ref=%s path=%s error=%v", ref, outputPath, err)
+				return service.DownloadResult{}, &service.DownloadError{URL: url, Err: err}
+			}
+			os.Remove(partHeaderPath(partPath))
+			size, sum, err := filesystem.FileDigest(outputPath)
+			if err != nil {
+				return service.DownloadResult{}, &service.DownloadError{URL: url, Err: err}
+			}
+			s.logger.Info(component, "Download completed: ref=%s path=%s size=%d bytes attempts=%d", ref, outputPath, size, attempt)
+			etag, lastModified := s.validators(header)
+			return service.DownloadResult{
+				URL:          url,
+				OutputPath:   outputPath,
+				ETag:         etag,
+				LastModified: lastModified,
+				Size:         size,
+				SHA256:       sum,
+			}, nil
+		}
+
+		if ctx.Err() != nil {
+			s.logger.Warn(component, "Download interrupted: ref=%s error=%v", ref, err)
+			return service.DownloadResult{}, err
+		}
+		if !service.IsTransient(err) {
+			s.logger.Warn(component, "Download failed: ref=%s error=%v", ref, err)
+			return service.DownloadResult{}, err
+		}
+		if attempt >= s.policy.maxAttempts {
+			s.logger.Error(component, "Download failed after retries: ref=%s attempts=%d error=%v", ref, attempt, err)
+			return service.DownloadResult{}, err
+		}
+
+		var retryAfter time.Duration
+		var downloadErr *service.DownloadError
+		if errors.As(err, &downloadErr) {
+			retryAfter = downloadErr.RetryAfter
+		}
+		wait := s.backoff(attempt, retryAfter)
+		s.logger.Warn(component, "Download attempt failed, retrying: ref=%s attempt=%d wait=%s error=%v", ref, attempt, wait, err)
+		if err := s.sleep(ctx, wait); err != nil {
+			return service.DownloadResult{}, &service.DownloadError{URL: url, Err: err}
+		}
+	}
+}
+
+// fetchToFile performs one attempt, appending to partPath when the server
+// honours the Range request for the same version of the file and rewriting it
+// otherwise. It returns the headers of the response the part file was started
+// with, once the body has been written.
+func (s *httpSource) fetchToFile(ctx context.Context, url, partPath string) (http.Header, error) {
+	var offset int64
+	var validator string
+	started := loadPartHeader(partPath)
+	if info, err := os.Stat(partPath); err == nil && started != nil {
+		// A part file whose version is unknown cannot be resumed safely.
+		if validator = resumeValidator(started); validator != "" {
+			offset = info.Size()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := s.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, &service.DownloadError{URL: url, Err: fmt.Errorf("failed to create HTTP request: %w", err)}
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", validator)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, &service.DownloadError{Kind: service.ErrNetwork, URL: url, Err: err}
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if start, _, ok := parseContentRange(resp.Header.Get("Content-Range")); !ok || start != offset {
+			discardPart(partPath)
+			return nil, &service.DownloadError{Kind: service.ErrNetwork, URL: url, Err: fmt.Errorf("range %q does not resume at byte %d, restarting", resp.Header.Get("Content-Range"), offset)}
+		}
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		// A full body: the first attempt, or the file changed since the part
+		// was started.
+		flags |= os.O_TRUNC
+		started = resp.Header
+		if err := savePartHeader(partPath, started); err != nil {
+			return nil, &service.DownloadError{URL: url, Err: err}
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		if _, total, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && total != offset {
+			discardPart(partPath)
+			return nil, &service.DownloadError{Kind: service.ErrNetwork, URL: url, Err: fmt.Errorf("part file holds %d bytes of a %d-byte file, restarting", offset, total)}
+		}
+		// The part file already holds the whole archive; let validation decide.
+		return started, nil
+	default:
+		return nil, &service.DownloadError{
+			Kind:       statusKind(resp.StatusCode),
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil {
+		return nil, &service.DownloadError{URL: url, Err: fmt.Errorf("failed to open output file %s: %w", partPath, err)}
+	}
+	defer out.Close()
+
+	body := &idleTimeoutReader{r: resp.Body, timeout: s.policy.readTimeout, timer: time.AfterFunc(s.policy.readTimeout, cancel)}
+	defer body.timer.Stop()
+
+	written, err := io.Copy(out, body)
+	if err != nil {
+		// Keep what was written so the next attempt can resume from it.
+		return nil, &service.DownloadError{Kind: service.ErrNetwork, URL: url, Err: fmt.Errorf("transfer interrupted: %w", err)}
+	}
+	if resp.ContentLength >= 0 && written != resp.ContentLength {
+		return nil, &service.DownloadError{Kind: service.ErrNetwork, URL: url, Err: fmt.Errorf("short body: got %d of %d bytes", written, resp.ContentLength)}
+	}
+	return started, nil
+}
+
+// partHeaderPath is where the headers a part file was started with are kept.
+func partHeaderPath(partPath string) string {
+	return partPath + ".header"
+}
+
+func savePartHeader(partPath string, h http.Header) error {
+	f, err := os.Create(partHeaderPath(partPath))
+	if err != nil {
+		return fmt.Errorf("failed to save part headers: %w", err)
+	}
+	defer f.Close()
+	if err := h.Write(f); err != nil {
+		return fmt.Errorf("failed to save part headers: %w", err)
+	}
+	return nil
+}
+
+// loadPartHeader returns the saved headers of partPath, or nil when there are none.
+func loadPartHeader(partPath string) http.Header {
+	data, err := os.ReadFile(partHeaderPath(partPath))
+	if err != nil {
+		return nil
+	}
+	h, err := textproto.NewReader(bufio.NewReader(strings.NewReader(string(data) + "\r\n"))).ReadMIMEHeader()
+	if err != nil {
+		return nil
+	}
+	return http.Header(h)
+}
+
+// discardPart removes a part file that cannot be resumed, with its headers.
+func discardPart(partPath string) {
+	os.Remove(partPath)
+	os.Remove(partHeaderPath(partPath))
+}
+
+// resumeValidator returns the If-Range value for a part started with h: its
+// ETag when strong (If-Range does not accept weak ones), else Last-Modified.
+func resumeValidator(h http.Header) string {
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return h.Get("Last-Modified")
+}
+
+// parseContentRange reads "bytes start-end/total" and "bytes */total". Start is
+// -1 and total is -1 when absent or unknown.
+func parseContentRange(value string) (start, total int64, ok bool) {
+	spec, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	span, size, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+	start, total = -1, -1
+	if span != "*" {
+		first, _, found := strings.Cut(span, "-")
+		if !found {
+			return 0, 0, false
+		}
+		n, err := strconv.ParseInt(first, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		start = n
+	}
+	if size != "*" {
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		total = n
+	}
+	return start, total, true
+}
+
+// statusKind classifies an unexpected HTTP status. Statuses that fit no kind,
+// such as 403, are returned unclassified and are not retried.
+func statusKind(status int) error {
+	switch {
+	case status == http.StatusNotFound:
+		return service.ErrNotPublished
+	case status == http.StatusTooManyRequests, status == http.StatusServiceUnavailable:
+		return service.ErrThrottled
+	case status >= http.StatusInternalServerError:
+		return service.ErrNetwork
+	default:
+		return nil
+	}
+}
+
+// backoff returns the delay before the next attempt: the server's Retry-After
+// when given, otherwise exponential backoff with jitter over its upper half.
+func (s *httpSource) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, s.policy.maxRetryAfter)
+	}
+	ceiling := s.policy.baseBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > s.policy.maxBackoff {
+		ceiling = s.policy.maxBackoff
+	}
+	half := ceiling / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// idleTimeoutReader cancels the request when no bytes arrive for timeout.
+type idleTimeoutReader struct {
+	r       io.Reader
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+func (r *idleTimeoutReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
@@ -0,0 +1,434 @@
+package portal
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+)
+
+func zipFixture(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("20250101_Despesas.csv")
+	if err != nil {
+		t.Fatalf("failed to create zip entry: %v", err)
+	}
+	f.Write([]byte(strings.Repeat("Código Unidade Gestora;Valor\n158454;10,00\n", 200)))
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newTestSource(server *httptest.Server) *httpSource {
+	policy := defaultDownloadPolicy
+	policy.maxAttempts = 3
+	policy.readTimeout = 2 * time.Second
+	return &httpSource{
+		logger:     &logger.Logger{MinLevel: logger.LevelError},
+		client:     server.Client(),
+		policy:     policy,
+		sleep:      func(context.Context, time.Duration) error { return nil },
+		urlFor:     func(a Archive) string { return server.URL + "/" + a.Key },
+		validators: headerValidators,
+	}
+}
+
+func TestDownloadResumesWithRange(t *testing.T) {
+	archive := zipFixture(t)
+	half := len(archive) / 2
+	const etag = `"v1"`
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		if requests.Add(1) == 1 {
+			// Announce the full size but cut the body halfway.
+			w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+			w.WriteHeader(http.StatusOK)
+			w.Write(archive[:half])
+			return
+		}
+		if got, want := r.Header.Get("Range"), fmt.Sprintf("bytes=%d-", half); got != want {
+			t.Errorf("Range header = %q, want %q", got, want)
+		}
+		if got := r.Header.Get("If-Range"); got != etag {
+			t.Errorf("If-Range header = %q, want %q", got, etag)
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", half, len(archive)-1, len(archive)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(archive[half:])
+	}))
+	defer server.Close()
+
+	outputPath := filepath.Join(t.TempDir(), "despesas.zip")
+	result, err := newTestSource(server).download(context.Background(), server.URL+"/despesas/20250101", outputPath, "20250101")
+	if err != nil {
+		t.Fatalf("expected download to succeed: %v", err)
+	}
+	got, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !bytes.Equal(got, archive) {
+		t.Fatalf("resumed archive differs from source: got %d bytes, want %d", len(got), len(archive))
+	}
+	if result.ETag != etag {
+		t.Fatalf("ETag = %q, want %q", result.ETag, etag)
+	}
+	for _, leftover := range []string{outputPath + ".part", outputPath + ".part.header"} {
+		if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be cleaned up, stat error=%v", leftover, err)
+		}
+	}
+}
+
+func TestDownloadRestartsWhenResumeIsUnsafe(t *testing.T) {
+	archive := zipFixture(t)
+	half := len(archive) / 2
+
+	// cut answers the first request with half of the archive.
+	cut := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(archive[:half])
+	}
+	full := func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Range"); got != "" {
+			t.Errorf("Range header = %q, want a request from the start", got)
+		}
+		w.Write(archive)
+	}
+
+	tests := []struct {
+		name         string
+		etag         string
+		responses    []http.HandlerFunc
+		wantRequests int32
+	}{
+		{
+			name:         "file republished since the part was started",
+			etag:         `"v1"`,
+			responses:    []http.HandlerFunc{cut, func(w http.ResponseWriter, r *http.Request) { w.Write(archive) }},
+			wantRequests: 2,
+		},
+		{
+			name: "range does not resume at the local offset",
+			etag: `"v1"`,
+			responses: []http.HandlerFunc{cut, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(archive)-1, len(archive)))
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write(archive)
+			}, full},
+			wantRequests: 3,
+		},
+		{
+			name:         "no validator to make the range conditional",
+			responses:    []http.HandlerFunc{cut, full},
+			wantRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.etag != "" {
+					w.Header().Set("ETag", tt.etag)
+				}
+				i := int(requests.Add(1)) - 1
+				tt.responses[min(i, len(tt.responses)-1)](w, r)
+			}))
+			defer server.Close()
+
+			outputPath := filepath.Join(t.TempDir(), "despesas.zip")
+			if _, err := newTestSource(server).download(context.Background(), server.URL+"/despesas/20250101", outputPath, "20250101"); err != nil {
+				t.Fatalf("expected download to succeed: %v", err)
+			}
+			got, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			if !bytes.Equal(got, archive) {
+				t.Fatalf("archive differs from source: got %d bytes, want %d", len(got), len(archive))
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestDownloadKeepsValidatorsOfCompletePart(t *testing.T) {
+	archive := zipFixture(t)
+	const etag = `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(archive)))
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+	}))
+	defer server.Close()
+
+	outputPath := filepath.Join(t.TempDir(), "despesas.zip")
+	partPath := outputPath + ".part"
+	if err := os.WriteFile(partPath, archive, 0o644); err != nil {
+		t.Fatalf("failed to write part file: %v", err)
+	}
+	if err := savePartHeader(partPath, http.Header{"Etag": {etag}}); err != nil {
+		t.Fatalf("failed to save part headers: %v", err)
+	}
+
+	result, err := newTestSource(server).download(context.Background(), server.URL+"/despesas/20250101", outputPath, "20250101")
+	if err != nil {
+		t.Fatalf("expected download to succeed: %v", err)
+	}
+	if result.ETag != etag || result.Size != int64(len(archive)) {
+		t.Fatalf("unexpected fingerprint: %+v", result)
+	}
+}
+
+func TestDownloadErrorKinds(t *testing.T) {
+	archive := zipFixture(t)
+
+	tests := []struct {
+		name         string
+		responses    []func(w http.ResponseWriter)
+		wantKind     error
+		wantRequests int32
+	}{
+		{
+			name: "retries 429 and 503",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "1")
+					w.WriteHeader(http.StatusTooManyRequests)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+				func(w http.ResponseWriter) { w.Write(archive) },
+			},
+			wantRequests: 3,
+		},
+		{
+			name: "404 is not published and not retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+			},
+			wantKind:     service.ErrNotPublished,
+			wantRequests: 1,
+		},
+		{
+			name: "corrupt archive is never moved into place",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.Write([]byte("<html>maintenance</html>")) },
+			},
+			wantKind:     service.ErrCorruptArchive,
+			wantRequests: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := int(requests.Add(1)) - 1
+				tt.responses[min(i, len(tt.responses)-1)](w)
+			}))
+			defer server.Close()
+
+			outputPath := filepath.Join(t.TempDir(), "despesas.zip")
+			_, err := newTestSource(server).download(context.Background(), server.URL+"/despesas/20250101", outputPath, "20250101")
+
+			if tt.wantKind == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantKind != nil && !errors.Is(err, tt.wantKind) {
+				t.Fatalf("error = %v, want kind %v", err, tt.wantKind)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if _, err := os.Stat(outputPath); (tt.wantKind == nil) != (err == nil) {
+				t.Fatalf("output presence does not match success: stat error=%v", err)
+			}
+		})
+	}
+}
+
+func TestDownloadStopsWhenCancelled(t *testing.T) {
+	var requests atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	outputPath := filepath.Join(t.TempDir(), "despesas.zip")
+	_, err := newTestSource(server).download(ctx, server.URL+"/despesas/20250101", outputPath, "20250101")
+	if err == nil {
+		t.Fatal("expected cancelled download to fail")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1: a cancelled download must not be retried", got)
+	}
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no output for a cancelled download, stat error=%v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := parseRetryAfter("30", now); got != 30*time.Second {
+		t.Fatalf("seconds: got %s", got)
+	}
+	if got := parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now); got != time.Minute {
+		t.Fatalf("http date: got %s", got)
+	}
+	if got := parseRetryAfter("soon", now); got != 0 {
+		t.Fatalf("invalid: got %s", got)
+	}
+}
+
+func TestRecheckSource(t *testing.T) {
+	archive := zipFixture(t)
+	const etag = `"v1"`
+	current := etag
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", current)
+		if r.Header.Get("If-None-Match") == current {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	client := &transparencyPortalClient{
+		logger: &logger.Logger{MinLevel: logger.LevelError},
+		source: newTestSource(server),
+		zipDir: t.TempDir(),
+	}
+	download, err := client.FetchExpensesData(context.Background(), "20250101")
+	if err != nil {
+		t.Fatalf("expected download to succeed: %v", err)
+	}
+	if download.ETag != etag || download.Size != int64(len(archive)) || len(download.SHA256) != 64 {
+		t.Fatalf("unexpected fingerprint: %+v", download)
+	}
+	outputPath := download.OutputPath
+
+	fp := model.SourceFingerprint{Key: download.Key, Name: download.Name, Path: download.OutputPath, ETag: download.ETag, Size: download.Size, SHA256: download.SHA256}
+	if changed, err := client.RecheckSource(context.Background(), fp); err != nil || changed {
+		t.Fatalf("unchanged source: changed=%v err=%v", changed, err)
+	}
+
+	current = `"v2"`
+	if changed, err := client.RecheckSource(context.Background(), fp); err != nil || !changed {
+		t.Fatalf("republished source: changed=%v err=%v", changed, err)
+	}
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Fatalf("expected stale archive to be removed, stat error=%v", err)
+	}
+
+	fp.ETag = ""
+	if changed, err := client.RecheckSource(context.Background(), fp); err != nil || changed {
+		t.Fatalf("hash fallback on identical body: changed=%v err=%v", changed, err)
+	}
+}
+
+func TestFetchExpensesDataReusesArchiveValidators(t *testing.T) {
+	archive := zipFixture(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 00:00:00 GMT")
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	client := &transparencyPortalClient{
+		logger: &logger.Logger{MinLevel: logger.LevelError},
+		source: newTestSource(server),
+		zipDir: t.TempDir(),
+	}
+	first, err := client.FetchExpensesData(context.Background(), "20250101")
+	if err != nil {
+		t.Fatalf("expected download to succeed: %v", err)
+	}
+	reused, err := client.FetchExpensesData(context.Background(), "20250101")
+	if err != nil {
+		t.Fatalf("expected reuse to succeed: %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1: a complete archive must be reused", got)
+	}
+	if reused != first {
+		t.Fatalf("reused archive lost its fingerprint: first=%+v reused=%+v", first, reused)
+	}
+
+	if err := os.Remove(first.OutputPath + ".json"); err != nil {
+		t.Fatalf("failed to remove validators: %v", err)
+	}
+	if _, err := client.FetchExpensesData(context.Background(), "20250101"); err != nil {
+		t.Fatalf("expected download to succeed: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("requests = %d, want 2: an archive without validators must be downloaded again", got)
+	}
+}
+
+func TestMirrorSourceStoresOriginDownloads(t *testing.T) {
+	archive := zipFixture(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	mirrorDir := t.TempDir()
+	mirror, err := NewMirrorSource(&logger.Logger{MinLevel: logger.LevelError}, NewDirSource(mirrorDir), newTestSource(server))
+	if err != nil {
+		t.Fatalf("failed to build mirror: %v", err)
+	}
+	a := Archive{Key: "despesas/20250101", Name: "expenses/despesas_20250101.zip"}
+
+	first, err := mirror.Fetch(context.Background(), a, filepath.Join(t.TempDir(), "first.zip"))
+	if err != nil {
+		t.Fatalf("expected upstream fetch to succeed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(mirrorDir, a.Name)); err != nil {
+		t.Fatalf("expected archive to be stored in the mirror: %v", err)
+	}
+
+	second, err := mirror.Fetch(context.Background(), a, filepath.Join(t.TempDir(), "second.zip"))
+	if err != nil {
+		t.Fatalf("expected mirror fetch to succeed: %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("upstream requests = %d, want 1", got)
+	}
+	if second.SHA256 != first.SHA256 || second.ETag != first.ETag {
+		t.Fatalf("mirror fingerprint differs from upstream: first=%+v second=%+v", first, second)
+	}
+}
@@ -0,0 +1,443 @@
+package portal
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"github.com/farxc/envelopa-transparencia/internal/utils"
+)
+
+//go:embed layouts/*.json
+var embeddedLayouts embed.FS
+
+// layoutFiles names the layout definition of each supported data type, both
+// in the embedded layouts directory and in a directory given to LoadLayouts.
+var layoutFiles = map[service.DataType]string{
+	service.DespesasEmpenho:                      "despesas_empenho.json",
+	service.DespesasItemEmpenho:                  "despesas_item_empenho.json",
+	service.DespesasItemEmpenhoHistorico:         "despesas_item_empenho_historico.json",
+	service.DespesasLiquidacao:                   "despesas_liquidacao.json",
+	service.DespesasLiquidacaoEmpenhosImpactados: "despesas_liquidacao_empenhos_impactados.json",
+	service.DespesasPagamento:                    "despesas_pagamento.json",
+	service.DespesasPagamentoEmpenhosImpactados:  "despesas_pagamento_empenhos_impactados.json",
+	service.DespesasPagamentoFavoricidosFinais:   "despesas_pagamento_favorecidos_finais.json",
+	service.DespesasPagamentoListaBancos:         "despesas_pagamento_lista_bancos.json",
+	service.DespesasPagamentoListaFaturas:        "despesas_pagamento_lista_faturas.json",
+	service.DespesasPagamentoListaPrecatorios:    "despesas_pagamento_lista_precatorios.json",
+	service.DespesasExecucao:                     "despesas_execucao.json",
+	service.ComprasContrato:                      "compras_contrato.json",
+	service.ComprasItemContrato:                  "compras_item_contrato.json",
+	service.Licitacao:                            "licitacao.json",
+	service.LicitacaoItem:                        "licitacao_item.json",
+	service.LicitacaoParticipante:                "licitacao_participante.json",
+	service.Convenio:                             "convenio.json",
+	service.EmendasParlamentares:                 "emendas_parlamentares.json",
+	service.CartaoPagamento:                      "cartao_pagamento.json",
+	service.SancoesCEIS:                          "sancoes_ceis.json",
+	service.SancoesCNEP:                          "sancoes_cnep.json",
+	service.Receitas:                             "receitas.json",
+	service.Viagem:                               "viagem.json",
+	service.ViagemPagamento:                      "viagem_pagamento.json",
+	service.ViagemPassagem:                       "viagem_passagem.json",
+}
+
+// FieldType names the parser applied to a raw CSV value before it is stored
+// in the target field.
+type FieldType string
+
+const (
+	// FieldString stores the value as is.
+	FieldString FieldType = "string"
+	// FieldDigits keeps only the digits, for masked CPF/CNPJ values.
+	FieldDigits FieldType = "digits"
+	// FieldInt parses an integer into any integer field.
+	FieldInt FieldType = "int"
+	// FieldDecimal parses a Brazilian decimal ("2.252,71").
+	FieldDecimal FieldType = "decimal"
+	// FieldDate parses a dd/mm/yyyy date.
+	FieldDate FieldType = "date"
+	// FieldBool is true for "Sim".
+	FieldBool FieldType = "bool"
+)
+
+// ColumnMapping is one column of a layout. Columns without a field are part
+// of the header signature but are not loaded.
+type ColumnMapping struct {
+	Column string    `json:"column"`
+	Field  string    `json:"field,omitempty"`
+	Type   FieldType `json:"type,omitempty"`
+}
+
+/*
+Layout is one version of the header of a portal file and how its columns map
+to model fields. When the portal renames or adds columns, a new version is
+added next to the old one, so archives published before and after the change
+both load.
+*/
+type Layout struct {
+	DataType service.DataType `json:"-"`
+	Version  int              `json:"version"`
+	Columns  []ColumnMapping  `json:"columns"`
+
+	// bindings caches the resolved fields per target type.
+	bindings sync.Map
+}
+
+type layoutFile struct {
+	Versions []*Layout `json:"versions"`
+}
+
+var (
+	layoutsMu sync.RWMutex
+	// layouts holds the versions of every data type, newest first.
+	layouts = mustLoadEmbeddedLayouts()
+)
+
+func mustLoadEmbeddedLayouts() map[service.DataType][]*Layout {
+	loaded := make(map[service.DataType][]*Layout, len(layoutFiles))
+	for dfType, name := range layoutFiles {
+		data, err := embeddedLayouts.ReadFile("layouts/" + name)
+		if err != nil {
+			panic(fmt.Sprintf("missing layout for %s: %v", dfType, err))
+		}
+		versions, err := parseLayouts(dfType, data)
+		if err != nil {
+			panic(fmt.Sprintf("invalid layout %s: %v", name, err))
+		}
+		loaded[dfType] = versions
+	}
+	return loaded
+}
+
+/*
+LoadLayouts reads layout definitions from dir, named like the embedded ones
+(e.g. despesas_empenho.json), and adds their versions to the built-in ones; a
+version number that already exists is replaced. It returns the number of
+versions loaded. Files that are absent from dir are left alone.
+*/
+func LoadLayouts(dir string) (int, error) {
+	loaded := 0
+	for dfType, name := range layoutFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return loaded, err
+		}
+		versions, err := parseLayouts(dfType, data)
+		if err != nil {
+			return loaded, fmt.Errorf("invalid layout %s: %w", name, err)
+		}
+		registerLayouts(dfType, versions...)
+		loaded += len(versions)
+	}
+	return loaded, nil
+}
+
+// registerLayouts adds versions to the registry, replacing equal version numbers.
+func registerLayouts(dfType service.DataType, versions ...*Layout) {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+
+	byVersion := make(map[int]*Layout)
+	for _, l := range layouts[dfType] {
+		byVersion[l.Version] = l
+	}
+	for _, l := range versions {
+		byVersion[l.Version] = l
+	}
+	merged := make([]*Layout, 0, len(byVersion))
+	for _, l := range byVersion {
+		merged = append(merged, l)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Version > merged[j].Version })
+	layouts[dfType] = merged
+}
+
+func parseLayouts(dfType service.DataType, data []byte) ([]*Layout, error) {
+	var file layoutFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if len(file.Versions) == 0 {
+		return nil, fmt.Errorf("no versions defined")
+	}
+
+	seen := make(map[int]bool, len(file.Versions))
+	for _, l := range file.Versions {
+		if l.Version <= 0 || seen[l.Version] {
+			return nil, fmt.Errorf("invalid or repeated version %d", l.Version)
+		}
+		seen[l.Version] = true
+		l.DataType = dfType
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("version %d: %w", l.Version, err)
+		}
+	}
+	sort.Slice(file.Versions, func(i, j int) bool { return file.Versions[i].Version > file.Versions[j].Version })
+	return file.Versions, nil
+}
+
+func (l *Layout) validate() error {
+	if len(l.Columns) == 0 {
+		return fmt.Errorf("no columns defined")
+	}
+	columns := make(map[string]bool, len(l.Columns))
+	fields := make(map[string]bool, len(l.Columns))
+	for _, c := range l.Columns {
+		if c.Column == "" || columns[c.Column] {
+			return fmt.Errorf("empty or repeated column %q", c.Column)
+		}
+		columns[c.Column] = true
+		if c.Field == "" {
+			if c.Type != "" {
+				return fmt.Errorf("column %q has a type but no field", c.Column)
+			}
+			continue
+		}
+		if fields[c.Field] {
+			return fmt.Errorf("field %s is mapped twice", c.Field)
+		}
+		fields[c.Field] = true
+		switch c.Type {
+		case FieldString, FieldDigits, FieldInt, FieldDecimal, FieldDate, FieldBool:
+		default:
+			return fmt.Errorf("column %q has unknown type %q", c.Column, c.Type)
+		}
+	}
+	return nil
+}
+
+func layoutsFor(dfType service.DataType) []*Layout {
+	layoutsMu.RLock()
+	defer layoutsMu.RUnlock()
+	return layouts[dfType]
+}
+
+/*
+selectLayout picks the version of dfType that matches header: the one missing
+the fewest columns, then the one matching the most, then the newest. A version
+that dropped columns of an older one is a subset of it, so an old file, which
+has them all, still decodes with the old version. Extra columns in the header
+are ignored.
+*/
+func selectLayout(dfType service.DataType, header []string) (*Layout, error) {
+	versions := layoutsFor(dfType)
+	if len(versions) == 0 {
+		return nil, validateDataTypeForTransformation(dfType)
+	}
+
+	inHeader := make(map[string]bool, len(header))
+	for _, col := range header {
+		inHeader[col] = true
+	}
+	// versions is sorted newest first, so only a better score replaces best.
+	var best *Layout
+	bestMissing, bestMatched := -1, 0
+	for _, l := range versions {
+		missing, matched := 0, 0
+		for _, c := range l.Columns {
+			if inHeader[c.Column] {
+				matched++
+			} else {
+				missing++
+			}
+		}
+		if bestMissing < 0 || missing < bestMissing || (missing == bestMissing && matched > bestMatched) {
+			best, bestMissing, bestMatched = l, missing, matched
+		}
+	}
+	return best, nil
+}
+
+// ColumnNames returns the header of the layout in file order.
+func (l *Layout) ColumnNames() []string {
+	names := make([]string, len(l.Columns))
+	for i, c := range l.Columns {
+		names[i] = c.Column
+	}
+	return names
+}
+
+/*
+resolveColumn translates a column name used by the extractors, such as a
+MatchColumn, to its name in l. The field mapped from column in any version of
+the data type identifies it; columns that are not mapped are returned as is.
+*/
+func resolveColumn(l *Layout, column string) string {
+	if column == "" {
+		return column
+	}
+	field := ""
+	for _, version := range layoutsFor(l.DataType) {
+		for _, c := range version.Columns {
+			if c.Column == column && c.Field != "" {
+				field = c.Field
+			}
+		}
+	}
+	if field == "" {
+		return column
+	}
+	for _, c := range l.Columns {
+		if c.Field == field {
+			return c.Column
+		}
+	}
+	return column
+}
+
+// fieldBinding is a layout column resolved to a field of the target type.
+type fieldBinding struct {
+	column string
+	kind   FieldType
+	index  []int
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (l *Layout) bind(t reflect.Type) ([]fieldBinding, error) {
+	if cached, ok := l.bindings.Load(t); ok {
+		return cached.([]fieldBinding), nil
+	}
+
+	var bindings []fieldBinding
+	for _, c := range l.Columns {
+		if c.Field == "" {
+			continue
+		}
+		field, ok := t.FieldByName(c.Field)
+		if !ok {
+			return nil, fmt.Errorf("layout %s v%d: %s has no field %s", l.DataType, l.Version, t, c.Field)
+		}
+		if !fieldAccepts(field.Type, c.Type) {
+			return nil, fmt.Errorf("layout %s v%d: field %s.%s cannot hold %s values", l.DataType, l.Version, t, c.Field, c.Type)
+		}
+		bindings = append(bindings, fieldBinding{column: c.Column, kind: c.Type, index: field.Index})
+	}
+	l.bindings.Store(t, bindings)
+	return bindings, nil
+}
+
+func fieldAccepts(t reflect.Type, kind FieldType) bool {
+	switch kind {
+	case FieldString, FieldDigits:
+		return t.Kind() == reflect.String
+	case FieldInt:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return true
+		}
+	case FieldDecimal:
+		return t.Kind() == reflect.Float64 || t.Kind() == reflect.Float32
+	case FieldDate:
+		return t == timeType
+	case FieldBool:
+		return t.Kind() == reflect.Bool
+	}
+	return false
+}
+
+// FieldError is a value that does not parse as its column's type. Empty
+// values are never an error; they load as the zero value.
+type FieldError struct {
+	Line   int
+	Column string
+	Value  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("line=%d column=%q value=%q: %s", e.Line, e.Column, e.Value, e.Reason)
+}
+
+/*
+decode fills the struct dst points to from the mapped columns of row. A value
+that does not parse fails the row with a *FieldError, so the caller can reject
+it instead of loading a silent zero.
+*/
+func (l *Layout) decode(row filesystem.Row, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode target must be a pointer to a struct, got %T", dst)
+	}
+	v = v.Elem()
+	bindings, err := l.bind(v.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, b := range bindings {
+		raw := row.Get(b.column)
+		field := v.FieldByIndex(b.index)
+		invalid := func(reason string) error {
+			return &FieldError{Line: row.Line, Column: b.column, Value: raw, Reason: reason}
+		}
+		switch b.kind {
+		case FieldString:
+			field.SetString(raw)
+		case FieldDigits:
+			field.SetString(utils.OnlyDigits(raw))
+		case FieldInt:
+			trimmed := strings.TrimSpace(raw)
+			if trimmed == "" {
+				field.SetInt(0)
+				continue
+			}
+			n, err := strconv.ParseInt(trimmed, 10, 64)
+			if err != nil || field.OverflowInt(n) {
+				return invalid("invalid integer")
+			}
+			field.SetInt(n)
+		case FieldDecimal:
+			value, err := utils.ParseFloat(raw)
+			if err != nil {
+				return invalid("invalid decimal")
+			}
+			field.SetFloat(value)
+		case FieldDate:
+			date := utils.ParseDate(strings.TrimSpace(raw))
+			if date.IsZero() && strings.TrimSpace(raw) != "" {
+				return invalid("invalid date")
+			}
+			field.Set(reflect.ValueOf(date))
+		case FieldBool:
+			field.SetBool(utils.ParseBool(raw))
+		}
+	}
+	return nil
+}
+
+// Record is a CSV row together with the layout version its file was read with.
+type Record struct {
+	filesystem.Row
+	Layout *Layout
+}
+
+// Decode fills the struct dst points to from the mapped columns of the record.
+func (r Record) Decode(dst any) error {
+	return r.Layout.decode(r.Row, dst)
+}
+
+// decodeRecord decodes row on top of value, which carries the defaults of
+// unmapped fields such as empty child slices.
+func decodeRecord[T any](row Record, value T) (T, error) {
+	if err := row.Decode(&value); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
@@ -0,0 +1,326 @@
+package portal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// writeCSVFile writes a Windows-1252 CSV with header and rows, like the portal's.
+func writeCSVFile(t *testing.T, header []string, rows ...[]string) string {
+	t.Helper()
+	lines := []string{strings.Join(header, ";")}
+	for _, row := range rows {
+		lines = append(lines, strings.Join(row, ";"))
+	}
+	encoded, err := charmap.Windows1252.NewEncoder().String(strings.Join(lines, "\r\n") + "\r\n")
+	if err != nil {
+		t.Fatalf("failed to encode csv: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "file.csv")
+	if err := os.WriteFile(path, []byte(encoded), 0o644); err != nil {
+		t.Fatalf("failed to write csv: %v", err)
+	}
+	return path
+}
+
+// writeCSV writes a CSV like writeCSVFile and opens it.
+func writeCSV(t *testing.T, header []string, rows ...[]string) *filesystem.CSVReader {
+	t.Helper()
+	reader, err := filesystem.OpenCSV(writeCSVFile(t, header, rows...))
+	if err != nil {
+		t.Fatalf("failed to open csv: %v", err)
+	}
+	t.Cleanup(func() { reader.Close() })
+	return reader
+}
+
+func TestLayoutsBindToMappers(t *testing.T) {
+	mappers := map[service.DataType]func(Record) error{
+		service.DespesasEmpenho:                      func(r Record) error { _, err := DfRowToCommitment(r); return err },
+		service.DespesasItemEmpenho:                  func(r Record) error { _, err := DfRowToCommitmentItem(r); return err },
+		service.DespesasItemEmpenhoHistorico:         func(r Record) error { _, err := DfRowToCommitmentItemHistory(r); return err },
+		service.DespesasLiquidacao:                   func(r Record) error { _, err := DfRowToLiquidation(r); return err },
+		service.DespesasLiquidacaoEmpenhosImpactados: func(r Record) error { _, err := DfRowToLiquidationImpactedCommitment(r); return err },
+		service.DespesasPagamento:                    func(r Record) error { _, err := DfRowToPayment(r); return err },
+		service.DespesasPagamentoEmpenhosImpactados:  func(r Record) error { _, err := DfRowToPaymentImpactedCommitment(r); return err },
+		service.DespesasPagamentoFavoricidosFinais:   func(r Record) error { _, err := DfRowToPaymentFinalBeneficiary(r); return err },
+		service.DespesasPagamentoListaBancos:         func(r Record) error { _, err := DfRowToPaymentBankTransfer(r); return err },
+		service.DespesasPagamentoListaFaturas:        func(r Record) error { _, err := DfRowToPaymentInvoice(r); return err },
+		service.DespesasPagamentoListaPrecatorios:    func(r Record) error { _, err := DfRowToPaymentCourtOrder(r); return err },
+		service.DespesasExecucao:                     func(r Record) error { _, err := DfRowToExpenseExecution(r); return err },
+		service.ComprasContrato:                      func(r Record) error { _, err := DfRowToContract(r); return err },
+		service.ComprasItemContrato:                  func(r Record) error { _, err := DfRowToContractItem(r); return err },
+		service.Licitacao:                            func(r Record) error { _, err := DfRowToBidding(r); return err },
+		service.LicitacaoItem:                        func(r Record) error { _, err := DfRowToBiddingItem(r); return err },
+		service.LicitacaoParticipante:                func(r Record) error { _, err := DfRowToBiddingParticipant(r); return err },
+		service.Convenio:                             func(r Record) error { _, err := DfRowToAgreement(r); return err },
+		service.EmendasParlamentares:                 func(r Record) error { _, err := DfRowToAmendment(r); return err },
+		service.CartaoPagamento:                      func(r Record) error { _, err := DfRowToCardExpense(r); return err },
+		service.SancoesCEIS:                          func(r Record) error { _, err := DfRowToSanction(r); return err },
+		service.SancoesCNEP:                          func(r Record) error { _, err := DfRowToSanction(r); return err },
+		service.Receitas:                             func(r Record) error { _, err := DfRowToRevenue(r); return err },
+		service.Viagem:                               func(r Record) error { _, err := DfRowToTrip(r); return err },
+		service.ViagemPagamento:                      func(r Record) error { _, err := DfRowToTripPayment(r); return err },
+		service.ViagemPassagem:                       func(r Record) error { _, err := DfRowToTripPassage(r); return err },
+	}
+
+	for dfType := range layoutFiles {
+		mapper, ok := mappers[dfType]
+		if !ok {
+			t.Errorf("%s has a layout but no mapper", dfType)
+			continue
+		}
+		for _, layout := range layoutsFor(dfType) {
+			if err := mapper(Record{Layout: layout}); err != nil {
+				t.Errorf("%s v%d: %v", dfType, layout.Version, err)
+			}
+		}
+	}
+}
+
+func TestLayoutVersionSelectedByHeader(t *testing.T) {
+	v1 := layoutsFor(service.ComprasItemContrato)[0]
+	// Hypothetical later layouts: one renames the unit and value columns, the
+	// other only drops the descriptions, so its columns are a subset of v1.
+	data := []byte(`{"versions": [{"version": 2, "columns": [
+		{"column": "Número Contrato", "field": "ContractNumber", "type": "string"},
+		{"column": "Código Unidade Gestora", "field": "ManagementUnitCode", "type": "int"},
+		{"column": "Código Item Compra", "field": "ItemCode", "type": "string"},
+		{"column": "Quantidade Item", "field": "Quantity", "type": "decimal"},
+		{"column": "Valor Unitário Item", "field": "Value", "type": "decimal"}
+	]}, {"version": 3, "columns": [
+		{"column": "Número Contrato", "field": "ContractNumber", "type": "string"},
+		{"column": "Código UG", "field": "ManagementUnitCode", "type": "int"},
+		{"column": "Código Item Compra", "field": "ItemCode", "type": "string"},
+		{"column": "Quantidade Item", "field": "Quantity", "type": "decimal"},
+		{"column": "Valor Item", "field": "Value", "type": "decimal"}
+	]}]}`)
+	later, err := parseLayouts(service.ComprasItemContrato, data)
+	if err != nil {
+		t.Fatalf("failed to parse layout: %v", err)
+	}
+	registerLayouts(service.ComprasItemContrato, later...)
+	// parseLayouts returns the newest version first.
+	v3, v2 := later[0], later[1]
+	t.Cleanup(func() {
+		layoutsMu.Lock()
+		layouts[service.ComprasItemContrato] = []*Layout{v1}
+		layoutsMu.Unlock()
+	})
+
+	tests := []struct {
+		name        string
+		header      []string
+		row         []string
+		version     int
+		description string
+	}{
+		{
+			name:        "old layout",
+			header:      v1.ColumnNames(),
+			row:         []string{"0001/2025", "158454", "ITEM-1", "Cadeira", "", "2", "1.250,50"},
+			version:     1,
+			description: "Cadeira",
+		},
+		{
+			name:    "new layout",
+			header:  v2.ColumnNames(),
+			row:     []string{"0001/2025", "158454", "ITEM-1", "2", "1.250,50"},
+			version: 2,
+		},
+		{
+			name:    "new layout dropping columns",
+			header:  v3.ColumnNames(),
+			row:     []string{"0001/2025", "158454", "ITEM-1", "2", "1.250,50"},
+			version: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := writeCSV(t, tt.header, tt.row)
+			layout, err := selectLayout(service.ComprasItemContrato, reader.Header())
+			if err != nil {
+				t.Fatalf("selectLayout: %v", err)
+			}
+			if layout.Version != tt.version {
+				t.Fatalf("version = %d, want %d", layout.Version, tt.version)
+			}
+			if got := resolveColumn(layout, string(MatchByUGCode)); !reader.HasColumn(got) {
+				t.Fatalf("match column resolved to %q, which is not in the header", got)
+			}
+
+			row, err := reader.Next()
+			if err != nil {
+				t.Fatalf("failed to read row: %v", err)
+			}
+			item, err := DfRowToContractItem(Record{Row: row, Layout: layout})
+			if err != nil {
+				t.Fatalf("DfRowToContractItem: %v", err)
+			}
+			if item.ManagementUnitCode != 158454 || item.Quantity != 2 || item.Value != 1250.50 || item.Description != tt.description {
+				t.Errorf("item = %+v", item)
+			}
+		})
+	}
+}
+
+func TestDecodeParsers(t *testing.T) {
+	layout := &Layout{DataType: service.Receitas, Version: 1, Columns: []ColumnMapping{
+		{Column: "Nome", Field: "Name", Type: FieldString},
+		{Column: "CPF", Field: "Document", Type: FieldDigits},
+		{Column: "Código", Field: "Code", Type: FieldInt},
+		{Column: "Valor", Field: "Value", Type: FieldDecimal},
+		{Column: "Data", Field: "Date", Type: FieldDate},
+		{Column: "Urgente", Field: "Urgent", Type: FieldBool},
+		{Column: "Ignorada"},
+	}}
+	if err := layout.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	var decoded struct {
+		Name     string
+		Document string
+		Code     int16
+		Value    float64
+		Date     time.Time
+		Urgent   bool
+	}
+	reader := writeCSV(t, layout.ColumnNames(), []string{"Fulano", "***.456.789-**", " 42 ", "2.252,71", "15/03/2025", "Sim", "x"})
+	row, err := reader.Next()
+	if err != nil {
+		t.Fatalf("failed to read row: %v", err)
+	}
+	if err := (Record{Row: row, Layout: layout}).Decode(&decoded); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if decoded.Name != "Fulano" || decoded.Document != "456789" || decoded.Code != 42 || decoded.Value != 2252.71 || !decoded.Urgent {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	if want := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC); !decoded.Date.Equal(want) {
+		t.Errorf("date = %v, want %v", decoded.Date, want)
+	}
+}
+
+func TestFindRowsRejectsUnparsableValues(t *testing.T) {
+	path := writeCSVFile(t, Columns(service.ComprasItemContrato),
+		[]string{"0001/2025", "158454", "ITEM-1", "Cadeira", "", "2", "1.250,50"},
+		[]string{"0002/2025", "158454", "ITEM-2", "Mesa", "", "dois", "300,00"},
+		[]string{"0003/2025", "158454", "ITEM-3", "Armário", "", "1", "800,00"},
+		[]string{"0004/2025", "999999", "ITEM-4", "Outra unidade", "", "x", "y"},
+	)
+
+	rejects := &Rejections{}
+	var items []string
+	matched, err := FindRows(context.Background(), path, service.ComprasItemContrato, []string{"158454"}, string(MatchByUGCode), false, rejects, func(row Record) error {
+		item, err := DfRowToContractItem(row)
+		if err != nil {
+			return fmt.Errorf("failed to map contract item row: %w", err)
+		}
+		items = append(items, item.ItemCode)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("FindRows: %v", err)
+	}
+	if matched != 3 || !reflect.DeepEqual(items, []string{"ITEM-1", "ITEM-3"}) {
+		t.Fatalf("matched=%d items=%v, want 3 matched and ITEM-1, ITEM-3 loaded", matched, items)
+	}
+
+	want := []model.RowRejection{{
+		DataType:   service.ComprasItemContrato.String(),
+		SourceFile: filepath.Base(path),
+		Line:       3,
+		Column:     "Quantidade Item",
+		RawValue:   "dois",
+		Reason:     "invalid decimal",
+	}}
+	if got := rejects.List(); !reflect.DeepEqual(got, want) {
+		t.Errorf("rejections = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeRejectsInvalidIntegersAndDates(t *testing.T) {
+	layout := &Layout{DataType: service.Receitas, Version: 1, Columns: []ColumnMapping{
+		{Column: "Código", Field: "Code", Type: FieldInt},
+		{Column: "Data", Field: "Date", Type: FieldDate},
+	}}
+	reader := writeCSV(t, layout.ColumnNames(), []string{"", ""}, []string{"12a", ""}, []string{"1", "31/02/2025"}, []string{"70000", ""})
+
+	var decoded struct {
+		Code int16
+		Date time.Time
+	}
+	wantColumns := []string{"", "Código", "Data", "Código"}
+	for i, want := range wantColumns {
+		row, err := reader.Next()
+		if err != nil {
+			t.Fatalf("failed to read row %d: %v", i, err)
+		}
+		err = (Record{Row: row, Layout: layout}).Decode(&decoded)
+		var fieldErr *FieldError
+		switch {
+		case want == "" && err != nil:
+			t.Errorf("row %d: unexpected error %v", i, err)
+		case want != "" && (!errors.As(err, &fieldErr) || fieldErr.Column != want):
+			t.Errorf("row %d: error = %v, want a field error on %q", i, err, want)
+		}
+	}
+}
+
+func TestAgreementDisbursementsKeepReleasedTotal(t *testing.T) {
+	header := Columns(service.Convenio)
+	row := func(released string) []string {
+		values := map[string]string{
+			"NÚMERO CONVÊNIO":        "900001",
+			"CÓDIGO CONCEDENTE":      "158454",
+			"VALOR LIBERADO":         released,
+			"DATA ÚLTIMA LIBERAÇÃO":  "10/02/2025",
+			"VALOR ÚLTIMA LIBERAÇÃO": "1.000,00",
+		}
+		out := make([]string, len(header))
+		for i, col := range header {
+			out[i] = values[col]
+		}
+		return out
+	}
+	// Two snapshots whose last releases share a date and a value: the second
+	// release only shows in the total released so far.
+	reader := writeCSV(t, header, row("1.000,00"), row("2.000,00"))
+	layout, err := selectLayout(service.Convenio, reader.Header())
+	if err != nil {
+		t.Fatalf("selectLayout: %v", err)
+	}
+
+	var totals []float64
+	for range 2 {
+		r, err := reader.Next()
+		if err != nil {
+			t.Fatalf("failed to read row: %v", err)
+		}
+		agreement, err := DfRowToAgreement(Record{Row: r, Layout: layout})
+		if err != nil {
+			t.Fatalf("DfRowToAgreement: %v", err)
+		}
+		if len(agreement.Disbursements) != 1 || agreement.Disbursements[0].Value != 1000 {
+			t.Fatalf("disbursements = %+v, want one release of 1000", agreement.Disbursements)
+		}
+		totals = append(totals, agreement.Disbursements[0].ReleasedTotal)
+	}
+	if !reflect.DeepEqual(totals, []float64{1000, 2000}) {
+		t.Fatalf("released totals = %v, want [1000 2000]", totals)
+	}
+}
@@ -1,326 +1,158 @@
 package portal
 
 import (
-	"fmt"
+	"time"
 
 	"github.com/farxc/envelopa-transparencia/internal/domain/model"
-	"github.com/farxc/envelopa-transparencia/internal/utils"
-	"github.com/go-gota/gota/dataframe"
 )
 
-func parseFloatField(df dataframe.DataFrame, rowIdx int, column string) (float64, error) {
-	value, err := utils.ParseFloat(utils.GetStr(column, rowIdx, &df))
-	if err != nil {
-		return 0, fmt.Errorf("row=%d column=%q: %w", rowIdx, column, err)
-	}
-	return value, nil
+// The column to field mappings live in the layouts directory; the mappers only
+// set the defaults of unmapped fields and derive values from mapped ones.
+
+func DfRowToCommitment(row Record) (model.Commitment, error) {
+	return decodeRecord(row, model.Commitment{Items: []model.CommitmentItem{}})
 }
 
-func DfRowToCommitment(df dataframe.DataFrame, rowIdx int) (model.Commitment, error) {
-	originalValue, err := parseFloatField(df, rowIdx, "Valor Original do Empenho")
-	if err != nil {
-		return model.Commitment{}, err
-	}
-	convertedValue, err := parseFloatField(df, rowIdx, "Valor do Empenho Convertido pra R$")
-	if err != nil {
-		return model.Commitment{}, err
-	}
-	conversionValue, err := parseFloatField(df, rowIdx, "Valor Utilizado na Conversão")
-	if err != nil {
-		return model.Commitment{}, err
-	}
+func DfRowToLiquidation(row Record) (model.Liquidation, error) {
+	return decodeRecord(row, model.Liquidation{ImpactedCommitments: []model.LiquidationImpactedCommitment{}})
+}
 
-	return model.Commitment{
-		ID:                            utils.ParseInt64(utils.GetStr("Id Empenho", rowIdx, &df)),
-		CommitmentCode:                utils.GetStr("Código Empenho", rowIdx, &df),
-		ResumedCommitmentCode:         utils.GetStr("Código Empenho Resumido", rowIdx, &df),
-		EmissionDate:                  utils.ParseDate(utils.GetStr("Data Emissão", rowIdx, &df)),
-		Type:                          utils.GetStr("Tipo Empenho", rowIdx, &df),
-		Process:                       utils.GetStr("Processo", rowIdx, &df),
-		DocumentCodeType:              utils.GetStr("Código Tipo Documento", rowIdx, &df),
-		DocumentType:                  utils.GetStr("Tipo Documento", rowIdx, &df),
-		ManagementUnitName:            utils.GetStr("Unidade Gestora", rowIdx, &df),
-		ManagementUnitCode:            utils.GetInt("Código Unidade Gestora", rowIdx, &df),
-		ManagementCode:                utils.GetInt("Código Gestão", rowIdx, &df),
-		ManagementName:                utils.GetStr("Gestão", rowIdx, &df),
-		FavoredCode:                   utils.GetStr("Código Favorecido", rowIdx, &df),
-		FavoredName:                   utils.GetStr("Favorecido", rowIdx, &df),
-		ExpenseCategoryCode:           utils.GetInt16("Código Categoria de Despesa", rowIdx, &df),
-		ExpenseCategory:               utils.GetStr("Categoria de Despesa", rowIdx, &df),
-		ExpenseGroupCode:              utils.GetInt16("Código Grupo de Despesa", rowIdx, &df),
-		ExpenseGroup:                  utils.GetStr("Grupo de Despesa", rowIdx, &df),
-		ApplicationModalityCode:       utils.GetInt16("Código Modalidade de Aplicação", rowIdx, &df),
-		ApplicationModality:           utils.GetStr("Modalidade de Aplicação", rowIdx, &df),
-		ExpenseElementCode:            utils.GetInt16("Código Elemento de Despesa", rowIdx, &df),
-		ExpenseElement:                utils.GetStr("Elemento de Despesa", rowIdx, &df),
-		BudgetPlan:                    utils.GetStr("Plano Orçamentário", rowIdx, &df),
-		BudgetPlanCode:                utils.GetInt32("Código Plano Orçamentário", rowIdx, &df),
-		Observation:                   utils.GetStr("Observação", rowIdx, &df),
-		CommitmentOriginalValue:       originalValue,
-		CommitmentValueConvertedToBrl: convertedValue,
-		ConversionValueUsed:           conversionValue,
-		Items:                         []model.CommitmentItem{},
-	}, nil
+func DfRowToPayment(row Record) (model.Payment, error) {
+	return decodeRecord(row, model.Payment{
+		ImpactedCommitments: []model.PaymentImpactedCommitment{},
+		FinalBeneficiaries:  []model.PaymentFinalBeneficiary{},
+		BankTransfers:       []model.PaymentBankTransfer{},
+		Invoices:            []model.PaymentInvoice{},
+		CourtOrders:         []model.PaymentCourtOrder{},
+	})
 }
 
-func DfRowToLiquidation(df dataframe.DataFrame, rowIdx int) model.Liquidation {
-	return model.Liquidation{
-		LiquidationCode:         utils.GetStr("Código Liquidação", rowIdx, &df),
-		LiquidationCodeResumed:  utils.GetStr("Código Liquidação Resumido", rowIdx, &df),
-		LiquidationEmissionDate: utils.ParseDate(utils.GetStr("Data Emissão", rowIdx, &df)),
-		DocumentCodeType:        utils.GetStr("Código Tipo Documento", rowIdx, &df),
-		DocumentType:            utils.GetStr("Tipo Documento", rowIdx, &df),
-		ManagementUnitName:      utils.GetStr("Unidade Gestora", rowIdx, &df),
-		ManagementUnitCode:      utils.GetInt("Código Unidade Gestora", rowIdx, &df),
-		ManagementCode:          utils.GetInt("Código Gestão", rowIdx, &df),
-		ManagementName:          utils.GetStr("Gestão", rowIdx, &df),
-		FavoredCode:             utils.GetStr("Código Favorecido", rowIdx, &df),
-		FavoredName:             utils.GetStr("Favorecido", rowIdx, &df),
-		ExpenseCategoryCode:     utils.GetInt16("Código Categoria de Despesa", rowIdx, &df),
-		ExpenseCategory:         utils.GetStr("Categoria de Despesa", rowIdx, &df),
-		ExpenseGroupCode:        utils.GetInt16("Código Grupo de Despesa", rowIdx, &df),
-		ExpenseGroup:            utils.GetStr("Grupo de Despesa", rowIdx, &df),
-		ApplicationModalityCode: utils.GetInt16("Código Modalidade de Aplicação", rowIdx, &df),
-		ApplicationModality:     utils.GetStr("Modalidade de Aplicação", rowIdx, &df),
-		ExpenseElementCode:      utils.GetInt16("Código Elemento de Despesa", rowIdx, &df),
-		ExpenseElement:          utils.GetStr("Elemento de Despesa", rowIdx, &df),
-		BudgetPlan:              utils.GetStr("Plano Orçamentário", rowIdx, &df),
-		BudgetPlanCode:          utils.GetInt32("Código Plano Orçamentário", rowIdx, &df),
-		Observation:             utils.GetStr("Observação", rowIdx, &df),
-		ImpactedCommitments:     []model.LiquidationImpactedCommitment{},
-	}
+func DfRowToPaymentFinalBeneficiary(row Record) (model.PaymentFinalBeneficiary, error) {
+	return decodeRecord(row, model.PaymentFinalBeneficiary{})
 }
 
-func DfRowToPayment(df dataframe.DataFrame, rowIdx int) (model.Payment, error) {
-	originalValue, err := parseFloatField(df, rowIdx, "Valor Original do Pagamento")
-	if err != nil {
-		return model.Payment{}, err
-	}
-	convertedValue, err := parseFloatField(df, rowIdx, "Valor do Pagamento Convertido pra R$")
-	if err != nil {
-		return model.Payment{}, err
-	}
-	conversionValue, err := parseFloatField(df, rowIdx, "Valor Utilizado na Conversão")
-	if err != nil {
-		return model.Payment{}, err
-	}
+func DfRowToPaymentBankTransfer(row Record) (model.PaymentBankTransfer, error) {
+	return decodeRecord(row, model.PaymentBankTransfer{})
+}
 
-	return model.Payment{
-		PaymentCode:             utils.GetStr("Código Pagamento", rowIdx, &df),
-		PaymentCodeResumed:      utils.GetStr("Código Pagamento Resumido", rowIdx, &df),
-		PaymentEmissionDate:     utils.ParseDate(utils.GetStr("Data Emissão", rowIdx, &df)),
-		DocumentCodeType:        utils.GetStr("Código Tipo Documento", rowIdx, &df),
-		DocumentType:            utils.GetStr("Tipo Documento", rowIdx, &df),
-		FavoredCode:             utils.GetStr("Código Favorecido", rowIdx, &df),
-		FavoredName:             utils.GetStr("Favorecido", rowIdx, &df),
-		ManagementUnitName:      utils.GetStr("Unidade Gestora", rowIdx, &df),
-		ManagementUnitCode:      utils.GetInt("Código Unidade Gestora", rowIdx, &df),
-		ManagementCode:          utils.GetInt("Código Gestão", rowIdx, &df),
-		ManagementName:          utils.GetStr("Gestão", rowIdx, &df),
-		ExpenseCategoryCode:     utils.GetInt16("Código Categoria de Despesa", rowIdx, &df),
-		ExpenseCategory:         utils.GetStr("Categoria de Despesa", rowIdx, &df),
-		ExpenseGroupCode:        utils.GetInt16("Código Grupo de Despesa", rowIdx, &df),
-		ExpenseGroup:            utils.GetStr("Grupo de Despesa", rowIdx, &df),
-		ApplicationModalityCode: utils.GetInt16("Código Modalidade de Aplicação", rowIdx, &df),
-		ApplicationModality:     utils.GetStr("Modalidade de Aplicação", rowIdx, &df),
-		ExpenseElementCode:      utils.GetInt16("Código Elemento de Despesa", rowIdx, &df),
-		ExpenseElement:          utils.GetStr("Elemento de Despesa", rowIdx, &df),
-		BudgetPlan:              utils.GetStr("Plano Orçamentário", rowIdx, &df),
-		BudgetPlanCode:          utils.GetInt32("Código Plano Orçamentário", rowIdx, &df),
-		Observation:             utils.GetStr("Observação", rowIdx, &df),
-		ExtraBudgetary:          utils.ParseBool(utils.GetStr("Extraorçamentário", rowIdx, &df)),
-		Process:                 utils.GetStr("Processo", rowIdx, &df),
-		OriginalPaymentValue:    originalValue,
-		ConvertedPaymentValue:   convertedValue,
-		ConversionUsedValue:     conversionValue,
-		ImpactedCommitments:     []model.PaymentImpactedCommitment{},
-	}, nil
+func DfRowToPaymentInvoice(row Record) (model.PaymentInvoice, error) {
+	return decodeRecord(row, model.PaymentInvoice{})
 }
 
-func DfRowToCommitmentItem(df dataframe.DataFrame, rowIdx int) (model.CommitmentItem, error) {
-	quantity, err := parseFloatField(df, rowIdx, "Quantidade")
-	if err != nil {
-		return model.CommitmentItem{}, err
-	}
-	unitPrice, err := parseFloatField(df, rowIdx, "Valor Unitário")
-	if err != nil {
-		return model.CommitmentItem{}, err
-	}
-	currentValue, err := parseFloatField(df, rowIdx, "Valor Atual")
-	if err != nil {
-		return model.CommitmentItem{}, err
-	}
-	totalPrice, err := parseFloatField(df, rowIdx, "Valor Total")
-	if err != nil {
-		return model.CommitmentItem{}, err
-	}
+func DfRowToPaymentCourtOrder(row Record) (model.PaymentCourtOrder, error) {
+	return decodeRecord(row, model.PaymentCourtOrder{})
+}
 
-	return model.CommitmentItem{
-		CommitmentID:            utils.ParseInt64(utils.GetStr("Id Empenho", rowIdx, &df)),
-		CommitmentCode:          utils.GetStr("Código Empenho", rowIdx, &df),
-		ExpenseCategoryCode:     utils.GetInt16("Código Categoria de Despesa", rowIdx, &df),
-		ExpenseCategory:         utils.GetStr("Categoria de Despesa", rowIdx, &df),
-		ExpenseGroupCode:        utils.GetInt16("Código Grupo de Despesa", rowIdx, &df),
-		ExpenseGroup:            utils.GetStr("Grupo de Despesa", rowIdx, &df),
-		ApplicationModalityCode: utils.GetInt16("Código Modalidade de Aplicação", rowIdx, &df),
-		ApplicationModality:     utils.GetStr("Modalidade de Aplicação", rowIdx, &df),
-		ExpenseElementCode:      utils.GetInt16("Código Elemento de Despesa", rowIdx, &df),
-		ExpenseElement:          utils.GetStr("Elemento de Despesa", rowIdx, &df),
-		SubExpenseElement:       utils.GetStr("SubElemento de Despesa", rowIdx, &df),
-		SubExpenseElementCode:   utils.GetInt16("Código SubElemento de Despesa", rowIdx, &df),
-		Description:             utils.GetStr("Descrição", rowIdx, &df),
-		Sequential:              utils.ParseInt16(utils.GetInt("Sequencial", rowIdx, &df)),
-		Quantity:                quantity,
-		UnitPrice:               unitPrice,
-		CurrentValue:            currentValue,
-		TotalPrice:              totalPrice,
-		History:                 []model.CommitmentItemsHistory{},
-	}, nil
+func DfRowToCommitmentItem(row Record) (model.CommitmentItem, error) {
+	return decodeRecord(row, model.CommitmentItem{History: []model.CommitmentItemsHistory{}})
 }
 
-func DfRowToCommitmentItemHistory(df dataframe.DataFrame, rowIdx int) (model.CommitmentItemsHistory, error) {
-	itemQuantity, err := parseFloatField(df, rowIdx, "Quantidade Item")
-	if err != nil {
-		return model.CommitmentItemsHistory{}, err
-	}
-	itemUnitPrice, err := parseFloatField(df, rowIdx, "Valor Unitário Item")
-	if err != nil {
-		return model.CommitmentItemsHistory{}, err
-	}
-	itemTotalPrice, err := parseFloatField(df, rowIdx, "Valor Total Item")
-	if err != nil {
-		return model.CommitmentItemsHistory{}, err
-	}
+func DfRowToCommitmentItemHistory(row Record) (model.CommitmentItemsHistory, error) {
+	return decodeRecord(row, model.CommitmentItemsHistory{})
+}
 
-	return model.CommitmentItemsHistory{
-		CommitmentID:   utils.ParseInt64(utils.GetStr("Id Empenho", rowIdx, &df)),
-		CommitmentCode: utils.GetStr("Código Empenho", rowIdx, &df),
-		OperationType:  utils.GetStr("Tipo Operação", rowIdx, &df),
-		OperationDate:  utils.ParseDate(utils.GetStr("Data Operação", rowIdx, &df)),
-		Sequential:     utils.ParseInt16(utils.GetInt("Sequencial", rowIdx, &df)),
-		ItemQuantity:   itemQuantity,
-		ItemUnitPrice:  itemUnitPrice,
-		ItemTotalPrice: itemTotalPrice,
-	}, nil
+func DfRowToPaymentImpactedCommitment(row Record) (model.PaymentImpactedCommitment, error) {
+	return decodeRecord(row, model.PaymentImpactedCommitment{})
 }
 
-func DfRowToPaymentImpactedCommitment(df dataframe.DataFrame, rowIdx int) (model.PaymentImpactedCommitment, error) {
-	paidValue, err := parseFloatField(df, rowIdx, "Valor Pago (R$)")
-	if err != nil {
-		return model.PaymentImpactedCommitment{}, err
-	}
-	registeredPayablesValue, err := parseFloatField(df, rowIdx, "Valor Restos a Pagar Inscritos (R$)")
-	if err != nil {
-		return model.PaymentImpactedCommitment{}, err
-	}
-	canceledPayablesValue, err := parseFloatField(df, rowIdx, "Valor Restos a Pagar Cancelado (R$)")
-	if err != nil {
-		return model.PaymentImpactedCommitment{}, err
-	}
-	outstandingValuePaid, err := parseFloatField(df, rowIdx, "Valor Restos a Pagar Pagos (R$)")
-	if err != nil {
-		return model.PaymentImpactedCommitment{}, err
-	}
+func DfRowToLiquidationImpactedCommitment(row Record) (model.LiquidationImpactedCommitment, error) {
+	return decodeRecord(row, model.LiquidationImpactedCommitment{})
+}
 
-	return model.PaymentImpactedCommitment{
-		PaymentCode:                utils.GetStr("Código Pagamento", rowIdx, &df),
-		CommitmentCode:             utils.GetStr("Código Empenho", rowIdx, &df),
-		ExpenseNatureCodeComplete:  utils.GetInt64("Código Natureza Despesa Completa", rowIdx, &df),
-		Subitem:                    utils.GetStr("Subitem", rowIdx, &df),
-		PaidValueBRL:               paidValue,
-		RegisteredPayablesValueBRL: registeredPayablesValue,
-		CanceledPayablesValueBRL:   canceledPayablesValue,
-		OutstandingValuePaidBRL:    outstandingValuePaid,
-	}, nil
+func DfRowToExpenseExecution(row Record) (model.ExpenseExecution, error) {
+	return decodeRecord(row, model.ExpenseExecution{})
 }
 
-func DfRowToLiquidationImpactedCommitment(df dataframe.DataFrame, rowIdx int) (model.LiquidationImpactedCommitment, error) {
-	liquidatedValue, err := parseFloatField(df, rowIdx, "Valor Liquidado (R$)")
-	if err != nil {
-		return model.LiquidationImpactedCommitment{}, err
-	}
-	registeredPayablesValue, err := parseFloatField(df, rowIdx, "Valor Restos a Pagar Inscritos (R$)")
-	if err != nil {
-		return model.LiquidationImpactedCommitment{}, err
-	}
-	canceledPayablesValue, err := parseFloatField(df, rowIdx, "Valor Restos a Pagar Cancelado (R$)")
-	if err != nil {
-		return model.LiquidationImpactedCommitment{}, err
-	}
-	outstandingValueLiquidated, err := parseFloatField(df, rowIdx, "Valor Restos a Pagar Pagos (R$)")
-	if err != nil {
-		return model.LiquidationImpactedCommitment{}, err
-	}
+func DfRowToContract(row Record) (model.Contract, error) {
+	return decodeRecord(row, model.Contract{Items: []model.ContractItem{}})
+}
 
-	return model.LiquidationImpactedCommitment{
-		LiquidationCode:               utils.GetStr("Código Liquidação", rowIdx, &df),
-		CommitmentCode:                utils.GetStr("Código Empenho", rowIdx, &df),
-		ExpenseNatureCodeComplete:     utils.GetInt64("Código Natureza Despesa Completa", rowIdx, &df),
-		Subitem:                       utils.GetStr("Subitem", rowIdx, &df),
-		LiquidatedValueBRL:            liquidatedValue,
-		RegisteredPayablesValueBRL:    registeredPayablesValue,
-		CanceledPayablesValueBRL:      canceledPayablesValue,
-		OutstandingValueLiquidatedBRL: outstandingValueLiquidated,
-	}, nil
+func DfRowToContractItem(row Record) (model.ContractItem, error) {
+	return decodeRecord(row, model.ContractItem{})
 }
 
-func DfRowToExpenseExecution(df dataframe.DataFrame, rowIdx int) (model.ExpenseExecution, error) {
-	committedValue, err := parseFloatField(df, rowIdx, "Valor Empenhado (R$)")
-	if err != nil {
-		return model.ExpenseExecution{}, err
-	}
-	liquidatedValue, err := parseFloatField(df, rowIdx, "Valor Liquidado (R$)")
-	if err != nil {
-		return model.ExpenseExecution{}, err
-	}
-	paidValue, err := parseFloatField(df, rowIdx, "Valor Pago (R$)")
+func DfRowToBidding(row Record) (model.Bidding, error) {
+	return decodeRecord(row, model.Bidding{
+		Items:        []model.BiddingItem{},
+		Participants: []model.BiddingParticipant{},
+	})
+}
+
+func DfRowToBiddingItem(row Record) (model.BiddingItem, error) {
+	return decodeRecord(row, model.BiddingItem{})
+}
+
+func DfRowToBiddingParticipant(row Record) (model.BiddingParticipant, error) {
+	return decodeRecord(row, model.BiddingParticipant{})
+}
+
+// agreementRecord adds the last release columns, which become a disbursement.
+type agreementRecord struct {
+	model.Agreement
+	LastReleaseDate  time.Time
+	LastReleaseValue float64
+}
+
+func DfRowToAgreement(row Record) (model.Agreement, error) {
+	decoded, err := decodeRecord(row, agreementRecord{
+		Agreement: model.Agreement{Disbursements: []model.AgreementDisbursement{}},
+	})
 	if err != nil {
-		return model.ExpenseExecution{}, err
+		return model.Agreement{}, err
 	}
-	registeredPayables, err := parseFloatField(df, rowIdx, "Valor Restos a Pagar Inscritos (R$)")
-	if err != nil {
-		return model.ExpenseExecution{}, err
+
+	agreement := decoded.Agreement
+	if !decoded.LastReleaseDate.IsZero() && decoded.LastReleaseValue != 0 {
+		agreement.Disbursements = append(agreement.Disbursements, model.AgreementDisbursement{
+			AgreementNumber: agreement.AgreementNumber,
+			ReleaseDate:     decoded.LastReleaseDate,
+			Value:           decoded.LastReleaseValue,
+			ReleasedTotal:   agreement.ReleasedValue,
+		})
 	}
-	cancelledPayables, err := parseFloatField(df, rowIdx, "Valor Restos a Pagar Cancelado (R$)")
+	return agreement, nil
+}
+
+func DfRowToAmendment(row Record) (model.Amendment, error) {
+	return decodeRecord(row, model.Amendment{})
+}
+
+func DfRowToCardExpense(row Record) (model.CardExpense, error) {
+	return decodeRecord(row, model.CardExpense{})
+}
+
+// DfRowToSanction maps a CEIS or CNEP row. The fine value only exists in CNEP.
+func DfRowToSanction(row Record) (model.Sanction, error) {
+	return decodeRecord(row, model.Sanction{})
+}
+
+// revenueRecord adds the launch date, which sets the reference month.
+type revenueRecord struct {
+	model.Revenue
+	LaunchDate time.Time
+}
+
+func DfRowToRevenue(row Record) (model.Revenue, error) {
+	decoded, err := decodeRecord(row, revenueRecord{})
 	if err != nil {
-		return model.ExpenseExecution{}, err
+		return model.Revenue{}, err
 	}
-	paidPayables, err := parseFloatField(df, rowIdx, "Valor Restos a Pagar Pagos (R$)")
-	if err != nil {
-		return model.ExpenseExecution{}, err
+
+	revenue := decoded.Revenue
+	if !decoded.LaunchDate.IsZero() {
+		revenue.ReferenceMonth = decoded.LaunchDate.Format("2006/01")
 	}
+	return revenue, nil
+}
+
+func DfRowToTrip(row Record) (model.Trip, error) {
+	return decodeRecord(row, model.Trip{})
+}
+
+func DfRowToTripPayment(row Record) (model.TripPayment, error) {
+	return decodeRecord(row, model.TripPayment{})
+}
 
-	return model.ExpenseExecution{
-		YearAndMonth:                utils.GetStr("Ano e mês do lançamento", rowIdx, &df),
-		SuperiorOrganCode:           utils.GetInt32("Código Órgão Superior", rowIdx, &df),
-		SuperiorOrganName:           utils.GetStr("Nome Órgão Superior", rowIdx, &df),
-		SubordinatedOrganCode:       utils.GetInt32("Código Órgão Subordinado", rowIdx, &df),
-		SubordinatedOrganName:       utils.GetStr("Nome Órgão Subordinado", rowIdx, &df),
-		ManagementUnitCode:          utils.GetInt32("Código Unidade Gestora", rowIdx, &df),
-		ManagementUnitName:          utils.GetStr("Nome Unidade Gestora", rowIdx, &df),
-		ManagementCode:              utils.GetInt32("Código Gestão", rowIdx, &df),
-		ManagementName:              utils.GetStr("Nome Gestão", rowIdx, &df),
-		ActionCode:                  utils.GetStr("Código Ação", rowIdx, &df),
-		ActionName:                  utils.GetStr("Nome Ação", rowIdx, &df),
-		BudgetPlanCode:              utils.GetInt32("Código Plano Orçamentário", rowIdx, &df),
-		BudgetPlanName:              utils.GetStr("Plano Orçamentário", rowIdx, &df),
-		FederativeUnit:              utils.GetStr("UF", rowIdx, &df),
-		Municipality:                utils.GetStr("Município", rowIdx, &df),
-		AuthorAmendamentCode:        utils.GetInt32("Código Autor Emenda", rowIdx, &df),
-		AuthorAmendamentName:        utils.GetStr("Nome Autor Emenda", rowIdx, &df),
-		EconomicCategoryCode:        utils.GetInt32("Código Categoria Econômica", rowIdx, &df),
-		EconomicCategoryName:        utils.GetStr("Nome Categoria Econômica", rowIdx, &df),
-		ExpenseGroupCode:            utils.GetInt32("Código Grupo de Despesa", rowIdx, &df),
-		ExpenseGroupName:            utils.GetStr("Nome Grupo de Despesa", rowIdx, &df),
-		ExpenseCategoryCode:         utils.GetInt32("Código Elemento de Despesa", rowIdx, &df),
-		ExpenseCategoryName:         utils.GetStr("Nome Elemento de Despesa", rowIdx, &df),
-		ExpenseModalityCode:         utils.GetInt32("Código Modalidade da Despesa", rowIdx, &df),
-		ExpenseModalityName:         utils.GetStr("Modalidade da Despesa", rowIdx, &df),
-		CommittedValueBRL:           committedValue,
-		LiquidatedValueBRL:          liquidatedValue,
-		PaidValueBRL:                paidValue,
-		RegisteredPayablesAmountBRL: registeredPayables,
-		CancelledPayablesAmountBRL:  cancelledPayables,
-		PaidPayablesAmountBRL:       paidPayables,
-	}, nil
+func DfRowToTripPassage(row Record) (model.TripPassage, error) {
+	return decodeRecord(row, model.TripPassage{})
 }
@@ -1,37 +1,75 @@
 package portal
 
 import (
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"sync"
+	"path/filepath"
 	"time"
 
 	"github.com/farxc/envelopa-transparencia/internal/domain/model"
 	"github.com/farxc/envelopa-transparencia/internal/domain/service"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
-	"github.com/go-gota/gota/dataframe"
 )
 
 type transparencyPortalClient struct {
-	logger  *logger.Logger
-	baseUrl string
-	client  *http.Client
-	debug   bool
+	logger *logger.Logger
+	source Source
+	// zipDir is where fetched archives are kept, laid out by Archive.Name.
+	zipDir      string
+	debug       bool
+	driftPolicy DriftPolicy
 }
 
-var PortalTransparenciaURL = "https://portaldatransparencia.gov.br/download-de-dados/"
+const DefaultZipDir = "tmp/zips"
 
-func NewTransparencyClient(logger *logger.Logger, debug bool) service.TransparencyPortalClient {
+// ClientOptions tunes a client; the zero value is usable.
+type ClientOptions struct {
+	// ZipDir defaults to DefaultZipDir.
+	ZipDir string
+	// Debug saves matched rows to tmp/debug.
+	Debug bool
+	// DriftPolicy defaults to DriftFail.
+	DriftPolicy DriftPolicy
+}
+
+// NewTransparencyClient builds a client that reads archives from source, e.g.
+// NewPortalSource.
+func NewTransparencyClient(logger *logger.Logger, source Source, opts ClientOptions) service.TransparencyPortalClient {
+	if opts.ZipDir == "" {
+		opts.ZipDir = DefaultZipDir
+	}
+	if opts.DriftPolicy == "" {
+		opts.DriftPolicy = DriftFail
+	}
 	return &transparencyPortalClient{
-		logger:  logger,
-		baseUrl: PortalTransparenciaURL,
-		client:  &http.Client{},
-		debug:   debug,
+		logger:      logger,
+		source:      source,
+		zipDir:      opts.ZipDir,
+		debug:       opts.Debug,
+		driftPolicy: opts.DriftPolicy,
 	}
+}
 
+// fetch retrieves archive from the source into the zip directory.
+func (c *transparencyPortalClient) fetch(ctx context.Context, archive Archive) (service.DownloadResult, error) {
+	const component = "Downloader"
+	outputPath := filepath.Join(c.zipDir, archive.Name)
+	if err := os.MkdirAll(filepath.Dir(outputPath), os.ModePerm); err != nil {
+		return service.DownloadResult{}, &service.DownloadError{Err: fmt.Errorf("failed to create zip directory: %w", err)}
+	}
+	result, err := c.source.Fetch(ctx, archive, outputPath)
+	if err != nil {
+		return service.DownloadResult{}, err
+	}
+	result.Key = archive.Key
+	result.Name = archive.Name
+	// Kept for a later run reusing the archive to record where it came from.
+	if err := writeArchiveMeta(outputPath, result); err != nil {
+		c.logger.Warn(component, "Failed to save archive validators: path=%s error=%v", outputPath, err)
+	}
+	return result, nil
 }
 
 type MatchColumn string
@@ -39,144 +77,95 @@ type MatchColumn string
 const (
 	MatchByManagingCode       MatchColumn = "Código Gestão"
 	MatchByManagementUnitCode MatchColumn = "Código Unidade Gestora"
+	// MatchByUGCode is the managing unit column of the compras and licitações
+	// files, which do not carry the management (gestão) code.
+	MatchByUGCode MatchColumn = "Código UG"
+	// MatchByGrantorCode is the granting unit column of the convênios file.
+	MatchByGrantorCode MatchColumn = "CÓDIGO CONCEDENTE"
+	// MatchByCardUnitCode is the managing unit column of the CPGF file, upper-cased there.
+	MatchByCardUnitCode MatchColumn = "CÓDIGO UNIDADE GESTORA"
+	// MatchByRevenueUnitCode is the managing unit column of the receitas file.
+	MatchByRevenueUnitCode MatchColumn = "CÓDIGO UNIDADE GESTORA"
+	// MatchByTravelPayingUnitCode is the paying unit column of the viagens payments file.
+	MatchByTravelPayingUnitCode MatchColumn = "Código da unidade gestora pagadora"
+	// MatchByTripID links the viagens files to each other.
+	MatchByTripID MatchColumn = "Identificador do processo de viagem"
 )
 
-func (c *transparencyPortalClient) ExtractExpensesExecution(cfg service.ExpensesExecutionExtractionConfig) (*service.ExpensesExecutionPayload, error) {
+func (c *transparencyPortalClient) ExtractExpensesExecution(ctx context.Context, cfg service.ExpensesExecutionExtractionConfig) (*service.ExpensesExecutionPayload, error) {
+	const component = "DataExtractor"
+	rejects := &Rejections{}
 	var units_expenses_executions []service.UnitExpenseExecution
-	ee_df, err := filesystem.OpenFileAndDecode(cfg.Extraction.File)
-	if err != nil {
-		return nil, err
-	}
+
 	var match_column MatchColumn
 	if cfg.IsManagingCode {
 		match_column = MatchByManagingCode
 	} else {
 		match_column = MatchByManagementUnitCode
 	}
-	filtered_ee := FindRowsSync(ee_df, service.DespesasExecucao, cfg.Codes, string(match_column), c.debug)
-	fmt.Printf("Filtered expense execution rows: %d\n", filtered_ee.Nrow())
-	for i := 0; i < filtered_ee.Nrow(); i++ {
-		expense_execution, err := DfRowToExpenseExecution(filtered_ee, i)
+
+	matched, err := FindRows(ctx, cfg.Extraction.File, service.DespesasExecucao, cfg.Codes, string(match_column), c.debug, rejects, func(row Record) error {
+		expense_execution, err := DfRowToExpenseExecution(row)
 		if err != nil {
-			return nil, fmt.Errorf("failed to map expense execution row %d: %w", i, err)
+			return fmt.Errorf("failed to map expense execution row: %w", err)
 		}
-		uee := service.UnitExpenseExecution{
+		units_expenses_executions = append(units_expenses_executions, service.UnitExpenseExecution{
 			UgCode:           expense_execution.ManagementUnitCode,
 			UgName:           expense_execution.ManagementUnitName,
 			ExpenseExecution: expense_execution,
-		}
-		units_expenses_executions = append(units_expenses_executions, uee)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	c.logger.Info(component, "Filtered expense execution rows: month=%s year=%s rows=%d", cfg.Extraction.Month, cfg.Extraction.Year, matched)
+
 	payload := service.ExpensesExecutionPayload{
 		ExtractionDate: cfg.Extraction.Month + "/" + cfg.Extraction.Year,
 		UnitsExpenses:  units_expenses_executions,
+		Rejections:     rejects.List(),
 	}
 	return &payload, nil
 }
 
-func (c *transparencyPortalClient) FetchExpensesExecution(month, year string) service.DownloadResult {
-	const component = "Downloader"
-	url := c.baseUrl + "despesas-execucao/" + year + month
-	output_path := "tmp/zips/expenses_execution/" + year + month + "_Despesas.zip"
-
-	c.logger.Debug(component, "Starting download for month=%s year=%s url=%s", month, year, url)
-
-	c.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-		req.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3")
-		return nil
-	}
-	// Create a new request with a custom User-Agent header
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		c.logger.Error(component, "Failed to create HTTP request: month=%s year=%s error=%v", month, year, err)
-		return service.DownloadResult{Success: false}
-	}
-
-	resp, err := c.client.Do(req)
-
-	if err != nil {
-		c.logger.Error(component, "HTTP request failed: month=%s year=%s error=%v", month, year, err)
-		return service.DownloadResult{Success: false}
-	}
-
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		c.logger.Warn(component, "Non-OK HTTP response: month=%s year=%s status=%s statusCode=%d", month, year, resp.Status, resp.StatusCode)
-		return service.DownloadResult{Success: false}
-	}
-
-	out, err := os.Create(output_path)
-
-	if err != nil {
-		c.logger.Error(component, "Failed to create output file: month=%s year=%s path=%s error=%v", month, year, output_path, err)
-		return service.DownloadResult{Success: false}
-	}
-	defer out.Close()
-
-	bytesWritten, err := io.Copy(out, resp.Body)
-	if err != nil {
-		c.logger.Error(component, "Failed to write data to file: month=%s year=%s error=%v", month, year, err)
-		return service.DownloadResult{Success: false}
-	}
-
-	c.logger.Info(component, "Download completed: month=%s year=%s path=%s size=%d bytes", month, year, output_path, bytesWritten)
-	return service.DownloadResult{Success: true, OutputPath: output_path}
+func (c *transparencyPortalClient) FetchExpensesExecution(ctx context.Context, month, year string) (service.DownloadResult, error) {
+	return c.fetch(ctx, Archive{Key: "despesas-execucao/" + year + month, Name: "expenses_execution/" + year + month + "_Despesas.zip"})
 }
 
-func (c *transparencyPortalClient) FetchExpensesData(date string) service.DownloadResult {
-	component := "Downloader"
-	url := c.baseUrl + "despesas/" + date
-	output_path := "tmp/zips/expenses/despesas_" + date + ".zip"
-
-	c.logger.Debug(component, "Starting download for date=%s url=%s", date, url)
-
-	c.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-		req.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/58.0.3029.110 Safari/537.3")
-		return nil
-	}
-	// Create a new request with a custom User-Agent header
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		c.logger.Error(component, "Failed to create HTTP request: date=%s error=%v", date, err)
-		return service.DownloadResult{Success: false}
-	}
-
-	resp, err := c.client.Do(req)
-
-	if err != nil {
-		c.logger.Error(component, "HTTP request failed: date=%s error=%v", date, err)
-		return service.DownloadResult{Success: false}
-	}
-
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		c.logger.Warn(component, "Non-OK HTTP response: date=%s status=%s statusCode=%d", date, resp.Status, resp.StatusCode)
-		return service.DownloadResult{Success: false}
-	}
-
-	out, err := os.Create(output_path)
-
-	if err != nil {
-		c.logger.Error(component, "Failed to create output file: date=%s path=%s error=%v", date, output_path, err)
-		return service.DownloadResult{Success: false}
-	}
-	defer out.Close()
-
-	bytesWritten, err := io.Copy(out, resp.Body)
-	if err != nil {
-		c.logger.Error(component, "Failed to write data to file: date=%s error=%v", date, err)
-		return service.DownloadResult{Success: false}
+func (c *transparencyPortalClient) FetchExpensesData(ctx context.Context, date string) (service.DownloadResult, error) {
+	archive := Archive{Key: "despesas/" + date, Name: "expenses/despesas_" + date + ".zip"}
+
+	// A complete archive left by an earlier run is reused with the validators
+	// it was downloaded with, so its rechecks stay conditional; a recheck
+	// removes it when the portal republishes the day. Without validators it is
+	// downloaded again.
+	outputPath := filepath.Join(c.zipDir, archive.Name)
+	if meta, err := readArchiveMeta(outputPath); err == nil && filesystem.ValidateZip(outputPath) == nil {
+		size, sum, err := filesystem.FileDigest(outputPath)
+		if err == nil {
+			return service.DownloadResult{
+				Key:          archive.Key,
+				Name:         archive.Name,
+				URL:          meta.URL,
+				OutputPath:   outputPath,
+				ETag:         meta.ETag,
+				LastModified: meta.LastModified,
+				Size:         size,
+				SHA256:       sum,
+			}, nil
+		}
 	}
+	return c.fetch(ctx, archive)
+}
 
-	c.logger.Info(component, "Download completed: date=%s path=%s size=%d bytes", date, output_path, bytesWritten)
-	return service.DownloadResult{Success: true, OutputPath: output_path}
+func (c *transparencyPortalClient) FetchContracts(ctx context.Context, month, year string) (service.DownloadResult, error) {
+	return c.fetch(ctx, Archive{Key: "compras/" + year + month, Name: "contracts/" + year + month + "_Compras.zip"})
 }
 
-func (c *transparencyPortalClient) ExtractExpenses(cfg service.ExpensesExtractionConfig) (*service.ExpensesPayload, error) {
-	var wg sync.WaitGroup
+func (c *transparencyPortalClient) ExtractExpenses(ctx context.Context, cfg service.ExpensesExtractionConfig) (*service.ExpensesPayload, error) {
 	component := "DataExtractor"
+	rejects := &Rejections{}
 
 	extractionDate, err := time.Parse("20060102", cfg.Extraction.Date)
 	if err != nil {
@@ -186,187 +175,163 @@ func (c *transparencyPortalClient) ExtractExpenses(cfg service.ExpensesExtractio
 
 	c.logger.Info(component, "Starting data extraction: date=%s codesCount=%d", formattedDate, len(cfg.Codes))
 
-	// Channel for collect DataFrames based in Unit Codes
-	ugMatches := make(chan service.MatchingDataframe, 3)
-
-	// Channel for collect DataFrames based in Commitment Codes
-	commitmentMatches := make(chan service.MatchingDataframe, 3)
-
-	hasUgCodeAsColumn := []service.DataType{
-		service.DespesasEmpenho,
-		service.DespesasLiquidacao,
-		service.DespesasPagamento,
-	}
-
-	hasCommitmentCodeAsColumn := []service.DataType{
-		service.DespesasItemEmpenho,
-		service.DespesasItemEmpenhoHistorico,
-	}
-
-	c.logger.Debug(component, "Phase 1: Filtering by UG codes: date=%s", extractionDate)
-	// First, find all Commitments based in Unit Codes
+	matchColumn := string(MatchByManagementUnitCode)
 	if cfg.IsManagingCode {
-		FilterExtractionByColumn(cfg.Extraction, hasUgCodeAsColumn, cfg.Codes, "Código Gestão", ugMatches, &wg, c.logger)
-	} else {
-		FilterExtractionByColumn(cfg.Extraction, hasUgCodeAsColumn, cfg.Codes, "Código Unidade Gestora", ugMatches, &wg, c.logger)
+		matchColumn = string(MatchByManagingCode)
 	}
 
-	wg.Wait()
-	close(ugMatches)
-
-	// Collect DataFrames by type
-	empenhosDf := dataframe.New()
-	liquidacoesDf := dataframe.New()
-	pagamentosDf := dataframe.New()
-	for extracted := range ugMatches {
-		transformedDf, err := SelectDataframeColumns(extracted.Dataframe, extracted.Type)
-
-		if err != nil {
-			c.logger.Error(component, "DataFrame transformation error: date=%s type=%s error=%v", extractionDate, service.DataTypeNames[extracted.Type], err)
-			continue
-		}
-
-		c.logger.Debug(component, "DataFrame transformed: date=%s type=%s rows=%d", extractionDate, service.DataTypeNames[extracted.Type], transformedDf.Nrow())
+	// Phase 1: stream the files keyed by unit codes and map matching rows straight to models.
+	var commitments []model.Commitment
+	var liquidations []model.Liquidation
+	var payments []model.Payment
 
-		switch extracted.Type {
-		case service.DespesasEmpenho:
-			empenhosDf = transformedDf
-		case service.DespesasLiquidacao:
-			liquidacoesDf = transformedDf
-		case service.DespesasPagamento:
-			pagamentosDf = transformedDf
-		}
+	c.logger.Debug(component, "Phase 1: Filtering by UG codes: date=%s", formattedDate)
+	err = scanFiles(ctx, cfg.Extraction.Files, cfg.Extraction.Date, []rowScan{
+		{dfType: service.DespesasEmpenho, codes: cfg.Codes, column: matchColumn, handle: func(row Record) error {
+			commitment, err := DfRowToCommitment(row)
+			if err != nil {
+				return fmt.Errorf("failed to map commitment: %w", err)
+			}
+			commitments = append(commitments, commitment)
+			return nil
+		}},
+		{dfType: service.DespesasLiquidacao, codes: cfg.Codes, column: matchColumn, handle: func(row Record) error {
+			liquidation, err := DfRowToLiquidation(row)
+			if err != nil {
+				return fmt.Errorf("failed to map liquidation: %w", err)
+			}
+			liquidations = append(liquidations, liquidation)
+			return nil
+		}},
+		{dfType: service.DespesasPagamento, codes: cfg.Codes, column: matchColumn, handle: func(row Record) error {
+			payment, err := DfRowToPayment(row)
+			if err != nil {
+				return fmt.Errorf("failed to map payment: %w", err)
+			}
+			payments = append(payments, payment)
+			return nil
+		}},
+	}, c.debug, rejects, c.logger)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if we have ANY data at all
-	hasAnyData := empenhosDf.Nrow() > 0 || liquidacoesDf.Nrow() > 0 || pagamentosDf.Nrow() > 0
-	c.logger.Info(component, "Phase 1 completed: date=%s empenhos=%d liquidacoes=%d pagamentos=%d", extractionDate, empenhosDf.Nrow(), liquidacoesDf.Nrow(), pagamentosDf.Nrow())
+	hasAnyData := len(commitments) > 0 || len(liquidations) > 0 || len(payments) > 0
+	c.logger.Info(component, "Phase 1 completed: date=%s empenhos=%d liquidacoes=%d pagamentos=%d", formattedDate, len(commitments), len(liquidations), len(payments))
 
-	if !hasAnyData {
-		c.logger.Warn(component, "No matching data found: date=%s", extractionDate)
-		return nil, fmt.Errorf("no matching data found for extraction date %s", extractionDate.Format("2006-01-02"))
+	// Rows that were all rejected still make a PARTIAL ingestion, not an empty one.
+	if !hasAnyData && rejects.Len() == 0 {
+		c.logger.Warn(component, "No matching data found: date=%s", formattedDate)
+		return nil, fmt.Errorf("no matching data found for extraction date %s: %w", formattedDate, service.ErrEmptyDataset)
+	}
+
+	// Phase 2: stream the child files keyed by the commitment and liquidation codes found above.
+	commitmentCodes := make([]string, 0, len(commitments))
+	for _, commitment := range commitments {
+		commitmentCodes = append(commitmentCodes, commitment.CommitmentCode)
+	}
+	liquidationCodes := make([]string, 0, len(liquidations))
+	for _, liquidation := range liquidations {
+		liquidationCodes = append(liquidationCodes, liquidation.LiquidationCode)
+	}
+	paymentCodes := make([]string, 0, len(payments))
+	for _, payment := range payments {
+		paymentCodes = append(paymentCodes, payment.PaymentCode)
 	}
 
-	// Extract impacted commitments for liquidations
 	var liImpacts []model.LiquidationImpactedCommitment
-	if liquidacoesDf.Nrow() > 0 {
-		ugsLiquidations := liquidacoesDf.Col("Código Liquidação").Records()
-		if p, ok := cfg.Extraction.Files[service.DespesasLiquidacaoEmpenhosImpactados]; ok {
-			df, err := filesystem.OpenFileAndDecode(p)
+	var paImpacts []model.PaymentImpactedCommitment
+	var items []model.CommitmentItem
+	var history []model.CommitmentItemsHistory
+	var finalBeneficiaries []model.PaymentFinalBeneficiary
+	var bankTransfers []model.PaymentBankTransfer
+	var invoices []model.PaymentInvoice
+	var courtOrders []model.PaymentCourtOrder
+
+	var childScans []rowScan
+	if len(liquidationCodes) > 0 {
+		childScans = append(childScans, rowScan{dfType: service.DespesasLiquidacaoEmpenhosImpactados, codes: liquidationCodes, column: "Código Liquidação", handle: func(row Record) error {
+			imp, err := DfRowToLiquidationImpactedCommitment(row)
 			if err != nil {
-				return nil, err
+				return fmt.Errorf("failed to map liquidation impacted commitment: %w", err)
 			}
-			matchedDf := FindRowsSync(df, service.DespesasLiquidacaoEmpenhosImpactados, ugsLiquidations, "Código Liquidação", c.debug)
-			for i := 0; i < matchedDf.Nrow(); i++ {
-				imp, err := DfRowToLiquidationImpactedCommitment(matchedDf, i)
+			liImpacts = append(liImpacts, imp)
+			return nil
+		}})
+	}
+	if len(paymentCodes) > 0 {
+		childScans = append(childScans,
+			rowScan{dfType: service.DespesasPagamentoFavoricidosFinais, codes: paymentCodes, column: "Código Pagamento", handle: func(row Record) error {
+				beneficiary, err := DfRowToPaymentFinalBeneficiary(row)
 				if err != nil {
-					return nil, fmt.Errorf("failed to map liquidation impacted commitment row %d: %w", i, err)
+					return fmt.Errorf("failed to map payment final beneficiary: %w", err)
 				}
-				liImpacts = append(liImpacts, imp)
-			}
-		}
+				finalBeneficiaries = append(finalBeneficiaries, beneficiary)
+				return nil
+			}},
+			rowScan{dfType: service.DespesasPagamentoListaBancos, codes: paymentCodes, column: "Código Pagamento", handle: func(row Record) error {
+				transfer, err := DfRowToPaymentBankTransfer(row)
+				if err != nil {
+					return fmt.Errorf("failed to map payment bank transfer: %w", err)
+				}
+				bankTransfers = append(bankTransfers, transfer)
+				return nil
+			}},
+			rowScan{dfType: service.DespesasPagamentoListaFaturas, codes: paymentCodes, column: "Código Pagamento", handle: func(row Record) error {
+				invoice, err := DfRowToPaymentInvoice(row)
+				if err != nil {
+					return fmt.Errorf("failed to map payment invoice: %w", err)
+				}
+				invoices = append(invoices, invoice)
+				return nil
+			}},
+			rowScan{dfType: service.DespesasPagamentoListaPrecatorios, codes: paymentCodes, column: "Código Pagamento", handle: func(row Record) error {
+				courtOrder, err := DfRowToPaymentCourtOrder(row)
+				if err != nil {
+					return fmt.Errorf("failed to map payment court order: %w", err)
+				}
+				courtOrders = append(courtOrders, courtOrder)
+				return nil
+			}},
+		)
 	}
-
-	// Extract impacted commitments for payments
-	var paImpacts []model.PaymentImpactedCommitment
-	if empenhosDf.Nrow() > 0 {
-		ugsCommitments := empenhosDf.Col("Código Empenho").Records()
-		if p, ok := cfg.Extraction.Files[service.DespesasPagamentoEmpenhosImpactados]; ok {
-			df, err := filesystem.OpenFileAndDecode(p)
-			if err != nil {
-				return nil, err
-			}
-
-			matchedDf := FindRowsSync(df, service.DespesasPagamentoEmpenhosImpactados, ugsCommitments, "Código Empenho", c.debug)
-			if matchedDf.Error() != nil {
-				return nil, fmt.Errorf("failed to filter payment impacted commitments: %w", matchedDf.Error())
-			}
-			c.logger.Info(component, "Payment impacts matched: date=%s commitments=%d impactedRows=%d", extractionDate, len(ugsCommitments), matchedDf.Nrow())
-			if matchedDf.Nrow() == 0 {
-				c.logger.Warn(component, "No impacted commitments matched for payment commitments: date=%s commitments=%d", extractionDate, len(ugsCommitments))
-			}
-			for i := 0; i < matchedDf.Nrow(); i++ {
-				imp, err := DfRowToPaymentImpactedCommitment(matchedDf, i)
+	if len(commitmentCodes) > 0 {
+		childScans = append(childScans,
+			rowScan{dfType: service.DespesasPagamentoEmpenhosImpactados, codes: commitmentCodes, column: "Código Empenho", handle: func(row Record) error {
+				imp, err := DfRowToPaymentImpactedCommitment(row)
 				if err != nil {
-					return nil, fmt.Errorf("failed to map payment impacted commitment row %d: %w", i, err)
+					return fmt.Errorf("failed to map payment impacted commitment: %w", err)
 				}
 				paImpacts = append(paImpacts, imp)
-			}
-		} else {
-			c.logger.Warn(component, "Payment impacted commitments file not found: date=%s", extractionDate)
-		}
-	}
-
-	// Only extract commitment items if we have commitments
-	var items []model.CommitmentItem
-	var history []model.CommitmentItemsHistory
-
-	if empenhosDf.Nrow() > 0 {
-		// Get commitment codes for sub-extraction
-		ugsCommitments := empenhosDf.Col("Código Empenho").Records()
-		c.logger.Debug(component, "Phase 2: Extracting commitment items: date=%s commitmentCodes=%d", extractionDate, len(ugsCommitments))
-
-		// Extract commitment items and history
-		FilterExtractionByColumn(cfg.Extraction, hasCommitmentCodeAsColumn,
-			ugsCommitments, "Código Empenho", commitmentMatches, &wg, c.logger)
-		wg.Wait()
-		close(commitmentMatches)
-
-		for extracted := range commitmentMatches {
-			transformedDf, err := SelectDataframeColumns(extracted.Dataframe, extracted.Type)
-			if err != nil {
-				c.logger.Error(component, "Commitment items transformation error: date=%s type=%s error=%v", extractionDate, service.DataTypeNames[extracted.Type], err)
-				continue
-			}
-
-			switch extracted.Type {
-			case service.DespesasItemEmpenho:
-				for i := 0; i < transformedDf.Nrow(); i++ {
-					item, err := DfRowToCommitmentItem(transformedDf, i)
-					if err != nil {
-						return nil, fmt.Errorf("failed to map commitment item row %d: %w", i, err)
-					}
-					items = append(items, item)
+				return nil
+			}},
+			rowScan{dfType: service.DespesasItemEmpenho, codes: commitmentCodes, column: "Código Empenho", handle: func(row Record) error {
+				item, err := DfRowToCommitmentItem(row)
+				if err != nil {
+					return fmt.Errorf("failed to map commitment item: %w", err)
 				}
-			case service.DespesasItemEmpenhoHistorico:
-				for i := 0; i < transformedDf.Nrow(); i++ {
-					entry, err := DfRowToCommitmentItemHistory(transformedDf, i)
-					if err != nil {
-						return nil, fmt.Errorf("failed to map commitment item history row %d: %w", i, err)
-					}
-					history = append(history, entry)
+				items = append(items, item)
+				return nil
+			}},
+			rowScan{dfType: service.DespesasItemEmpenhoHistorico, codes: commitmentCodes, column: "Código Empenho", handle: func(row Record) error {
+				entry, err := DfRowToCommitmentItemHistory(row)
+				if err != nil {
+					return fmt.Errorf("failed to map commitment item history: %w", err)
 				}
-			}
-		}
-		c.logger.Info(component, "Phase 2 completed: date=%s items=%d history=%d", extractionDate, len(items), len(history))
-	} else {
-		c.logger.Debug(component, "Skipping Phase 2 (no commitments): date=%s", extractionDate)
-		// Close the channel since we won't use it
-		close(commitmentMatches)
+				history = append(history, entry)
+				return nil
+			}},
+		)
 	}
 
-	// Map raw rows to models
-	var commitments []model.Commitment
-	for i := 0; i < empenhosDf.Nrow(); i++ {
-		commitment, err := DfRowToCommitment(empenhosDf, i)
-		if err != nil {
-			return nil, fmt.Errorf("failed to map commitment row %d: %w", i, err)
-		}
-		commitments = append(commitments, commitment)
-	}
-
-	var liquidations []model.Liquidation
-	for i := 0; i < liquidacoesDf.Nrow(); i++ {
-		liquidations = append(liquidations, DfRowToLiquidation(liquidacoesDf, i))
+	c.logger.Debug(component, "Phase 2: Extracting child records: date=%s commitmentCodes=%d liquidationCodes=%d paymentCodes=%d", formattedDate, len(commitmentCodes), len(liquidationCodes), len(paymentCodes))
+	if err := scanFiles(ctx, cfg.Extraction.Files, cfg.Extraction.Date, childScans, c.debug, rejects, c.logger); err != nil {
+		return nil, err
 	}
-
-	var payments []model.Payment
-	for i := 0; i < pagamentosDf.Nrow(); i++ {
-		payment, err := DfRowToPayment(pagamentosDf, i)
-		if err != nil {
-			return nil, fmt.Errorf("failed to map payment row %d: %w", i, err)
-		}
-		payments = append(payments, payment)
+	c.logger.Info(component, "Phase 2 completed: date=%s items=%d history=%d liquidationImpacts=%d paymentImpacts=%d finalBeneficiaries=%d bankTransfers=%d invoices=%d courtOrders=%d", formattedDate, len(items), len(history), len(liImpacts), len(paImpacts), len(finalBeneficiaries), len(bankTransfers), len(invoices), len(courtOrders))
+	if len(commitmentCodes) > 0 && len(paImpacts) == 0 {
+		c.logger.Warn(component, "No impacted commitments matched for payment commitments: date=%s commitments=%d", formattedDate, len(commitmentCodes))
 	}
 
 	// Build the hierarchical structure using the agnostic domain service
@@ -378,12 +343,17 @@ func (c *transparencyPortalClient) ExtractExpenses(cfg service.ExpensesExtractio
 		liImpacts,
 		payments,
 		paImpacts,
+		finalBeneficiaries,
+		bankTransfers,
+		invoices,
+		courtOrders,
 	)
 
 	// Build the hierarchical JSON structure
 	payload := &service.ExpensesPayload{
 		ExtractionDate: formattedDate,
 		UnitsExpenses:  []service.UnitsExpenses{},
+		Rejections:     rejects.List(),
 	}
 
 	// Convert map to slice
@@ -391,6 +361,6 @@ func (c *transparencyPortalClient) ExtractExpenses(cfg service.ExpensesExtractio
 		payload.UnitsExpenses = append(payload.UnitsExpenses, *unit)
 	}
 
-	c.logger.Info(component, "Extraction completed: date=%s unitsProcessed=%d", extractionDate, len(payload.UnitsExpenses))
+	c.logger.Info(component, "Extraction completed: date=%s unitsProcessed=%d rejectedRows=%d", formattedDate, len(payload.UnitsExpenses), len(payload.Rejections))
 	return payload, nil
 }
@@ -0,0 +1,377 @@
+/*
+Package portaltest provides a fake Transparency Portal for tests.
+
+The server answers the download routes the ETL uses, despesas/YYYYMMDD and
+despesas-execucao/YYYYMM, with zips generated on the fly. Their CSVs are
+Windows-1252 encoded, ';' separated and fully quoted like the real files, and
+carry the headers the extractors expect. Every unit gets one commitment,
+liquidation and payment per day, and one execution row per month.
+
+Point the ETL at it through PORTAL_URL (or portal.NewPortalSource) with URL().
+*/
+package portaltest
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/client/portal"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Unit is a managing unit (UG) the fake portal publishes data for.
+type Unit struct {
+	Code       int64
+	Management int64
+	Name       string
+}
+
+// DefaultUnits are two units of the same management, matching the ETL's default codes.
+var DefaultUnits = []Unit{
+	{Code: 158454, Management: 26421, Name: "INSTITUTO FEDERAL DE EDUCAÇÃO, CIÊNCIA E TECNOLOGIA"},
+	{Code: 158148, Management: 26421, Name: "CAMPUS AVANÇADO SÃO JOÃO"},
+}
+
+type Server struct {
+	server *httptest.Server
+	units  []Unit
+
+	mu          sync.Mutex
+	unpublished map[string]bool
+	stalled     map[string]bool
+	requests    map[string]int
+}
+
+// NewServer starts a fake portal publishing data for units (DefaultUnits when
+// none are given). Close it when done.
+func NewServer(units ...Unit) *Server {
+	if len(units) == 0 {
+		units = DefaultUnits
+	}
+	s := &Server{
+		units:       units,
+		unpublished: make(map[string]bool),
+		stalled:     make(map[string]bool),
+		requests:    make(map[string]int),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL is the download root, to be used as the portal base URL.
+func (s *Server) URL() string {
+	return s.server.URL + "/"
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Unpublish makes key, e.g. "despesas/20250102", answer 404 like a period the
+// portal has not published yet.
+func (s *Server) Unpublish(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unpublished[key] = true
+}
+
+// Stall makes requests for key hang without answering until the client gives
+// up, like a download in flight, until Release is called.
+func (s *Server) Stall(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stalled[key] = true
+}
+
+// Release serves key normally again after Stall.
+func (s *Server) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.stalled, key)
+}
+
+// Requests returns how many times key was requested.
+func (s *Server) Requests(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[key]
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+
+	s.mu.Lock()
+	s.requests[key]++
+	unpublished := s.unpublished[key]
+	stalled := s.stalled[key]
+	s.mu.Unlock()
+
+	if unpublished {
+		http.NotFound(w, r)
+		return
+	}
+	if stalled {
+		<-r.Context().Done()
+		return
+	}
+
+	var archive []byte
+	var err error
+	dataset, ref, _ := strings.Cut(key, "/")
+	switch dataset {
+	case "despesas":
+		var date time.Time
+		if date, err = time.Parse("20060102", ref); err == nil {
+			archive, err = s.expensesArchive(date)
+		}
+	case "despesas-execucao":
+		var month time.Time
+		if month, err = time.Parse("200601", ref); err == nil {
+			archive, err = s.executionArchive(month)
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("ETag", fmt.Sprintf(`"%s"`, ref))
+	http.ServeContent(w, r, ref+".zip", time.Time{}, bytes.NewReader(archive))
+}
+
+// file is one CSV inside a generated archive.
+type file struct {
+	name   string
+	dfType service.DataType
+	rows   []map[string]string
+}
+
+func (s *Server) expensesArchive(date time.Time) ([]byte, error) {
+	day := date.Format("20060102")
+	emission := date.Format("02/01/2006")
+
+	files := map[service.DataType]*file{}
+	for dt, suffix := range map[service.DataType]string{
+		service.DespesasEmpenho:                      service.DespesasEmpenhoDataType,
+		service.DespesasItemEmpenho:                  service.DespesasItemEmpenhoDataType,
+		service.DespesasItemEmpenhoHistorico:         service.DespesasItemEmpenhoHistoricoDataType,
+		service.DespesasLiquidacao:                   service.DespesasLiquidacaoDataType,
+		service.DespesasLiquidacaoEmpenhosImpactados: service.DespesasLiquidacaoEmpenhosImpactadosDataType,
+		service.DespesasPagamento:                    service.DespesasPagamentoDataType,
+		service.DespesasPagamentoEmpenhosImpactados:  service.DespesasPagamentoEmpenhosImpactadosDataType,
+		service.DespesasPagamentoFavoricidosFinais:   service.DespesasPagamentoFavorecidosFinaisDataType,
+		service.DespesasPagamentoListaBancos:         service.DespesasPagamentoListaBancosDataType,
+		service.DespesasPagamentoListaFaturas:        service.DespesasPagamentoListaFaturasDataType,
+		service.DespesasPagamentoListaPrecatorios:    service.DespesasPagamentoListaPrecatoriosDataType,
+	} {
+		files[dt] = &file{name: day + suffix, dfType: dt}
+	}
+
+	for i, u := range s.units {
+		seq := date.YearDay()*100 + i
+		id := fmt.Sprintf("%s%03d", day, i)
+		unit := map[string]string{
+			"Código Unidade Gestora": fmt.Sprint(u.Code),
+			"Unidade Gestora":        u.Name,
+			"Código Gestão":          fmt.Sprint(u.Management),
+			"Gestão":                 "Instituto Federal",
+			"Código Favorecido":      "12345678000190",
+			"Favorecido":             "FORNECEDOR DE MATERIAIS LTDA",
+			"Data Emissão":           emission,
+		}
+		commitment := documentCode(u, date, "NE", seq)
+		liquidation := documentCode(u, date, "NS", seq)
+		payment := documentCode(u, date, "OB", seq)
+
+		files[service.DespesasEmpenho].add(unit, map[string]string{
+			"Id Empenho":                         id,
+			"Código Empenho":                     commitment,
+			"Código Empenho Resumido":            commitment[len(commitment)-12:],
+			"Tipo Empenho":                       "Ordinário",
+			"Valor Original do Empenho":          "1.500,00",
+			"Valor do Empenho Convertido pra R$": "1.500,00",
+			"Valor Utilizado na Conversão":       "0,00",
+		})
+		files[service.DespesasItemEmpenho].add(map[string]string{
+			"Id Empenho":     id,
+			"Código Empenho": commitment,
+			"Descrição":      "MATERIAL DE EXPEDIENTE",
+			"Quantidade":     "10",
+			"Valor Unitário": "150,00",
+			"Valor Total":    "1.500,00",
+			"Sequencial":     "1",
+			"Valor Atual":    "1.500,00",
+		})
+		files[service.DespesasItemEmpenhoHistorico].add(map[string]string{
+			"Id Empenho":          id,
+			"Código Empenho":      commitment,
+			"Sequencial":          "1",
+			"Tipo Operação":       "INCLUSAO",
+			"Data Operação":       emission,
+			"Quantidade Item":     "10",
+			"Valor Unitário Item": "150,00",
+			"Valor Total Item":    "1.500,00",
+		})
+		files[service.DespesasLiquidacao].add(unit, map[string]string{
+			"Código Liquidação":          liquidation,
+			"Código Liquidação Resumido": liquidation[len(liquidation)-12:],
+		})
+		files[service.DespesasLiquidacaoEmpenhosImpactados].add(map[string]string{
+			"Código Liquidação":    liquidation,
+			"Código Empenho":       commitment,
+			"Subitem":              "16",
+			"Valor Liquidado (R$)": "1.500,00",
+		})
+		files[service.DespesasPagamento].add(unit, map[string]string{
+			"Código Pagamento":                     payment,
+			"Código Pagamento Resumido":            payment[len(payment)-12:],
+			"Extraorçamentário":                    "Não",
+			"Valor Original do Pagamento":          "1.500,00",
+			"Valor do Pagamento Convertido pra R$": "1.500,00",
+			"Valor Utilizado na Conversão":         "0,00",
+		})
+		files[service.DespesasPagamentoEmpenhosImpactados].add(map[string]string{
+			"Código Pagamento": payment,
+			"Código Empenho":   commitment,
+			"Subitem":          "16",
+			"Valor Pago (R$)":  "1.500,00",
+		})
+		files[service.DespesasPagamentoFavoricidosFinais].add(map[string]string{
+			"Código Pagamento":        payment,
+			"Código Favorecido Final": "12345678000190",
+			"Favorecido Final":        "FORNECEDOR DE MATERIAIS LTDA",
+			"Valor Recebido (R$)":     "1.500,00",
+		})
+	}
+
+	list := make([]*file, 0, len(files))
+	for _, f := range files {
+		list = append(list, f)
+	}
+	return buildArchive(list)
+}
+
+func (s *Server) executionArchive(month time.Time) ([]byte, error) {
+	f := &file{name: month.Format("200601") + service.DespesasExecucaoDataType, dfType: service.DespesasExecucao}
+	for _, u := range s.units {
+		f.add(map[string]string{
+			"Ano e mês do lançamento":             month.Format("2006/01"),
+			"Código Órgão Superior":               "26000",
+			"Nome Órgão Superior":                 "Ministério da Educação",
+			"Código Órgão Subordinado":            fmt.Sprint(u.Management),
+			"Nome Órgão Subordinado":              "Instituto Federal",
+			"Código Unidade Gestora":              fmt.Sprint(u.Code),
+			"Nome Unidade Gestora":                u.Name,
+			"Código Gestão":                       fmt.Sprint(u.Management),
+			"Nome Gestão":                         "Instituto Federal",
+			"Código Ação":                         "20RL",
+			"Nome Ação":                           "Funcionamento de Instituições Federais de Educação",
+			"UF":                                  "DF",
+			"Município":                           "BRASÍLIA",
+			"Código Categoria Econômica":          "3",
+			"Código Grupo de Despesa":             "3",
+			"Código Elemento de Despesa":          "30",
+			"Código Modalidade da Despesa":        "90",
+			"Valor Empenhado (R$)":                "10.000,00",
+			"Valor Liquidado (R$)":                "7.500,00",
+			"Valor Pago (R$)":                     "5.000,00",
+			"Valor Restos a Pagar Inscritos (R$)": "0,00",
+			"Valor Restos a Pagar Cancelado (R$)": "0,00",
+			"Valor Restos a Pagar Pagos (R$)":     "0,00",
+		})
+	}
+	return buildArchive([]*file{f})
+}
+
+// documentCode builds a SIAFI-like code: unit, management, year, kind and sequence.
+func documentCode(u Unit, date time.Time, kind string, seq int) string {
+	return fmt.Sprintf("%06d%05d%d%s%06d", u.Code, u.Management, date.Year(), kind, seq)
+}
+
+// add appends a row made of the given values merged in order.
+func (f *file) add(values ...map[string]string) {
+	row := map[string]string{}
+	for _, v := range values {
+		for k, val := range v {
+			row[k] = val
+		}
+	}
+	f.rows = append(f.rows, row)
+}
+
+func buildArchive(files []*file) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		content, err := f.encode()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encode renders the file as the portal does: quoted fields, ';' separators,
+// CRLF line endings and Windows-1252 text.
+func (f *file) encode() ([]byte, error) {
+	columns := portal.Columns(f.dfType)
+	var b strings.Builder
+	writeRecord(&b, columns)
+	for _, row := range f.rows {
+		values := make([]string, len(columns))
+		for i, col := range columns {
+			value, ok := row[col]
+			if !ok {
+				value = defaultValue(col)
+			}
+			values[i] = value
+		}
+		writeRecord(&b, values)
+	}
+	return charmap.Windows1252.NewEncoder().Bytes([]byte(b.String()))
+}
+
+func writeRecord(b *strings.Builder, values []string) {
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(`"` + strings.ReplaceAll(v, `"`, `""`) + `"`)
+	}
+	b.WriteString("\r\n")
+}
+
+// defaultValue fills the columns a row does not set, by the column's kind.
+func defaultValue(col string) string {
+	switch {
+	case strings.HasPrefix(col, "Valor"):
+		return "0,00"
+	case strings.HasPrefix(col, "Código"):
+		return "0"
+	case strings.HasPrefix(col, "Data"):
+		return ""
+	default:
+		return "Sem informação"
+	}
+}
@@ -0,0 +1,86 @@
+package portaltest
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/client/portal"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+)
+
+// TestServerArchivesMatchExtractors checks that the generated files go through
+// the real client and extractors.
+func TestServerArchivesMatchExtractors(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.Unpublish("despesas/20250103")
+
+	appLogger := &logger.Logger{MinLevel: logger.LevelError}
+	client := portal.NewTransparencyClient(appLogger, portal.NewPortalSource(appLogger, server.URL()), portal.ClientOptions{ZipDir: t.TempDir()})
+	codes := []string{"158454", "158148"}
+	ctx := context.Background()
+
+	download, err := client.FetchExpensesData(ctx, "20250102")
+	if err != nil {
+		t.Fatalf("expected expenses download to succeed: %v", err)
+	}
+	extraction := filesystem.UnzipFile(download.OutputPath, filepath.Join(t.TempDir(), "despesas"), appLogger)
+	if !extraction.Success {
+		t.Fatal("expected expenses archive to unzip")
+	}
+	expenses, err := client.ExtractExpenses(ctx, service.ExpensesExtractionConfig{
+		Codes: codes,
+		Extraction: service.OutputExpensesExtractionFiles{
+			Date:  "20250102",
+			Files: filesystem.BuildFilesForDate("20250102", extraction.OutputDir),
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected expenses extraction to succeed: %v", err)
+	}
+	if len(expenses.Rejections) > 0 {
+		t.Fatalf("expected no rejected expense rows, got %+v", expenses.Rejections)
+	}
+	if got := len(expenses.UnitsExpenses); got != len(DefaultUnits) {
+		t.Fatalf("units = %d, want %d", got, len(DefaultUnits))
+	}
+	for _, unit := range expenses.UnitsExpenses {
+		if len(unit.Commitments) != 1 || len(unit.Liquidations) != 1 || len(unit.Payments) != 1 {
+			t.Fatalf("unit %s: commitments=%d liquidations=%d payments=%d, want 1 each", unit.UgCode, len(unit.Commitments), len(unit.Liquidations), len(unit.Payments))
+		}
+		if unit.Commitments[0].CommitmentOriginalValue != 1500 {
+			t.Fatalf("unit %s: commitment value = %v, want 1500", unit.UgCode, unit.Commitments[0].CommitmentOriginalValue)
+		}
+	}
+
+	download, err = client.FetchExpensesExecution(ctx, "01", "2025")
+	if err != nil {
+		t.Fatalf("expected execution download to succeed: %v", err)
+	}
+	extraction = filesystem.UnzipFile(download.OutputPath, filepath.Join(t.TempDir(), "execucao"), appLogger)
+	execution, err := client.ExtractExpensesExecution(ctx, service.ExpensesExecutionExtractionConfig{
+		Codes: codes,
+		Extraction: service.OutputExpensesExecutionExtractionFiles{
+			Month: "01",
+			Year:  "2025",
+			File:  filepath.Join(extraction.OutputDir, "202501"+service.DespesasExecucaoDataType),
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected execution extraction to succeed: %v", err)
+	}
+	if len(execution.Rejections) > 0 {
+		t.Fatalf("expected no rejected execution rows, got %+v", execution.Rejections)
+	}
+	if got := len(execution.UnitsExpenses); got != len(DefaultUnits) {
+		t.Fatalf("execution rows = %d, want %d", got, len(DefaultUnits))
+	}
+
+	if _, err := client.FetchExpensesData(ctx, "20250103"); !errors.Is(err, service.ErrNotPublished) {
+		t.Fatalf("unpublished day: error = %v, want %v", err, service.ErrNotPublished)
+	}
+}
@@ -1,315 +1,274 @@
 package portal
 
 import (
+	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
 	"github.com/farxc/envelopa-transparencia/internal/domain/service"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
-	"github.com/go-gota/gota/dataframe"
-	"github.com/go-gota/gota/series"
 )
 
-var columnsForDataType = map[service.DataType][]string{
-	service.DespesasPagamento: {
-		"Código Pagamento",
-		"Código Pagamento Resumido",
-		"Data Emissão",
-		"Código Tipo Documento",
-		"Tipo Documento",
-		"Tipo OB",
-		"Extraorçamentário",
-		"Processo",
-		"Código Unidade Gestora",
-		"Código Categoria de Despesa",
-		"Categoria de Despesa",
-		"Código Grupo de Despesa",
-		"Grupo de Despesa",
-		"Código Modalidade de Aplicação",
-		"Modalidade de Aplicação",
-		"Código Elemento de Despesa",
-		"Elemento de Despesa",
-		"Plano Orçamentário",
-		"Código Plano Orçamentário",
-		"Observação",
-		"Unidade Gestora",
-		"Código Gestão",
-		"Gestão",
-		"Código Favorecido",
-		"Favorecido",
-		"Valor Original do Pagamento",
-		"Valor do Pagamento Convertido pra R$",
-		"Valor Utilizado na Conversão",
-	},
-	service.DespesasPagamentoEmpenhosImpactados: {
-		"Código Pagamento",
-		"Código Empenho",
-		"Código Natureza Despesa Completa",
-		"Subitem",
-		"Valor Pago (R$)",
-		"Valor Restos a Pagar Inscritos (R$)",
-		"Valor Restos a Pagar Cancelado (R$)",
-		"Valor Restos a Pagar Pagos (R$)",
-	},
-	service.DespesasLiquidacao: {
-		"Código Liquidação",
-		"Código Liquidação Resumido",
-		"Data Emissão",
-		"Código Tipo Documento",
-		"Tipo Documento",
-		"Código Unidade Gestora",
-		"Unidade Gestora",
-		"Código Gestão",
-		"Gestão",
-		"Código Categoria de Despesa",
-		"Categoria de Despesa",
-		"Código Grupo de Despesa",
-		"Grupo de Despesa",
-		"Código Modalidade de Aplicação",
-		"Modalidade de Aplicação",
-		"Código Elemento de Despesa",
-		"Elemento de Despesa",
-		"Plano Orçamentário",
-		"Código Plano Orçamentário",
-		"Código Favorecido",
-		"Favorecido",
-		"Observação",
-	},
-	service.DespesasLiquidacaoEmpenhosImpactados: {
-		"Código Liquidação",
-		"Código Empenho",
-		"Código Natureza Despesa Completa",
-		"Subitem",
-		"Valor Liquidado (R$)",
-		"Valor Restos a Pagar Inscritos (R$)",
-		"Valor Restos a Pagar Cancelado (R$)",
-		"Valor Restos a Pagar Pagos (R$)",
-	},
-	service.DespesasEmpenho: {
-		"Id Empenho",
-		"Código Empenho",
-		"Código Empenho Resumido",
-		"Código Tipo Documento",
-		"Tipo Documento",
-		"Data Emissão",
-		"Tipo Empenho",
-		"Código Unidade Gestora",
-		"Unidade Gestora",
-		"Código Gestão",
-		"Gestão",
-		"Processo",
-		"Plano Orçamentário",
-		"Código Plano Orçamentário",
-		"Favorecido",
-		"Código Favorecido",
-		"Observação",
-		"Tipo Crédito",
-		"Código Grupo Fonte Recurso",
-		"Grupo Fonte Recurso",
-		"Código Categoria de Despesa",
-		"Categoria de Despesa",
-		"Código Grupo de Despesa",
-		"Grupo de Despesa",
-		"Código Modalidade de Aplicação",
-		"Modalidade de Aplicação",
-		"Código Elemento de Despesa",
-		"Elemento de Despesa",
-		"Modalidade de Licitação",
-		"Valor Original do Empenho",
-		"Valor do Empenho Convertido pra R$",
-		"Valor Utilizado na Conversão",
-	},
-	service.DespesasItemEmpenho: {
-		"Id Empenho",
-		"Código Empenho",
-		"Código Categoria de Despesa",
-		"Categoria de Despesa",
-		"Código Grupo de Despesa",
-		"Grupo de Despesa",
-		"Código Modalidade de Aplicação",
-		"Modalidade de Aplicação",
-		"Código Elemento de Despesa",
-		"Elemento de Despesa",
-		"Código SubElemento de Despesa",
-		"SubElemento de Despesa",
-		"Descrição",
-		"Quantidade",
-		"Valor Unitário",
-		"Valor Total",
-		"Sequencial",
-		"Valor Atual",
-	},
-	service.DespesasItemEmpenhoHistorico: {
-		"Id Empenho",
-		"Código Empenho",
-		"Sequencial",
-		"Tipo Operação",
-		"Data Operação",
-		"Quantidade Item",
-		"Valor Unitário Item",
-		"Valor Total Item",
-	},
-	service.DespesasExecucao: {
-		"Ano e mês do lançamento",
-		"Código Órgão Superior",
-		"Nome Órgão Superior",
-		"Código Órgão Subordinado",
-		"Nome Órgão Subordinado",
-		"Código Unidade Gestora",
-		"Nome Unidade Gestora",
-		"Código Gestão",
-		"Nome Gestão",
-		"Código Ação",
-		"Nome Ação",
-		"Código Plano Orçamentário",
-		"Plano Orçamentário",
-		"UF",
-		"Município",
-		"Código Autor Emenda",
-		"Nome Autor Emenda",
-		"Código Categoria Econômica",
-		"Nome Categoria Econômica",
-		"Código Grupo de Despesa",
-		"Nome Grupo de Despesa",
-		"Código Elemento de Despesa",
-		"Nome Elemento de Despesa",
-		"Código Modalidade da Despesa",
-		"Modalidade da Despesa",
-		"Valor Empenhado (R$)",
-		"Valor Liquidado (R$)",
-		"Valor Pago (R$)",
-		"Valor Restos a Pagar Inscritos (R$)",
-		"Valor Restos a Pagar Cancelado (R$)",
-		"Valor Restos a Pagar Pagos (R$)",
-	},
+// Columns returns the header the extractors expect in a dfType file, as given
+// by its newest layout.
+func Columns(dfType service.DataType) []string {
+	versions := layoutsFor(dfType)
+	if len(versions) == 0 {
+		return nil
+	}
+	return versions[0].ColumnNames()
 }
 
 // Validates if the data type is supported for transformation
 func validateDataTypeForTransformation(dfType service.DataType) error {
-	if _, ok := columnsForDataType[dfType]; !ok {
+	if len(layoutsFor(dfType)) == 0 {
 		return fmt.Errorf("unsupported data type for transformation: %v", dfType)
 	}
 	return nil
 }
 
-func SelectDataframeColumns(df dataframe.DataFrame, dfType service.DataType) (dataframe.DataFrame, error) {
+// codeSet indexes the codes to match. Codes are compared as written in the
+// file, like the DataFrame filter this replaced.
+type codeSet map[string]struct{}
 
-	if err := validateDataTypeForTransformation(dfType); err != nil {
-		return dataframe.DataFrame{}, err
+func newCodeSet(codes []string) codeSet {
+	set := make(codeSet, len(codes))
+	for _, c := range codes {
+		set[c] = struct{}{}
 	}
-
-	selectedCols := columnsForDataType[dfType]
-	result := df.Select(selectedCols)
-
-	if result.Error() != nil {
-		return dataframe.DataFrame{}, fmt.Errorf("error selecting columns: %v", result.Error())
-	}
-
-	return result, nil
+	return set
 }
 
-/*
-Finds rows in the dataframe that match the given codes in the specified column and sends the resulting dataframe to the provided channel.
-(This function runs as a goroutine and signals completion via the WaitGroup.)
-*/
-func FindRows(df dataframe.DataFrame, dfType service.DataType, codes []string, codeColumn string, date string, ch chan service.MatchingDataframe, wg *sync.WaitGroup, appLogger *logger.Logger) {
-	const component = "DataFilter"
-	defer wg.Done()
-
-	appLogger.Debug(component, "Starting row search: date=%s type=%s column=%s codesCount=%d", date, service.DataTypeNames[dfType], codeColumn, len(codes))
-
-	filter := dataframe.F{
-		Colname:    codeColumn,
-		Comparator: series.In,
-		Comparando: codes,
-	}
-
-	matchingRows := df.Filter(
-		filter,
-	)
-
-	if df.Error() != nil {
-		appLogger.Error(component, "DataFrame filter error: date=%s type=%s error=%v", date, service.DataTypeNames[dfType], df.Error())
-		return
-	}
+func (s codeSet) contains(code string) bool {
+	_, ok := s[code]
+	return ok
+}
 
-	appLogger.Info(component, "Row search completed: date=%s type=%s matchingRows=%d", date, service.DataTypeNames[dfType], matchingRows.Nrow())
-	if matchingRows.Nrow() > 0 {
-		ch <- service.MatchingDataframe{Dataframe: matchingRows, Type: dfType}
-	}
+// debugWriter writes matched rows to tmp/debug/<dfType>_<sanitizedColumn>_<timestamp>.csv.
+// A nil *debugWriter is a no-op, which is what newDebugWriter returns when debug is false
+// or the file cannot be created.
+type debugWriter struct {
+	file    *os.File
+	writer  *csv.Writer
+	columns []string
 }
 
-// debugSaveDf writes df to tmp/debug/<dfType>_<sanitizedColumn>_<timestamp>.csv.
-// It is a no-op when debug is false, the dataframe is empty, or the file cannot be created.
-func debugSaveDf(df dataframe.DataFrame, dfType service.DataType, codeColumn string, debug bool) {
-	if !debug || df.Nrow() == 0 {
-		return
+func newDebugWriter(dfType service.DataType, codeColumn string, columns []string, debug bool) *debugWriter {
+	if !debug {
+		return nil
 	}
 	_ = os.MkdirAll("tmp/debug", os.ModePerm)
 	col := strings.NewReplacer(" ", "_", "/", "-").Replace(codeColumn)
 	name := fmt.Sprintf("tmp/debug/%d_%s_%s.csv", dfType, col, time.Now().Format("20060102_150405"))
 	f, err := os.Create(name)
 	if err != nil {
+		return nil
+	}
+	w := &debugWriter{file: f, writer: csv.NewWriter(f), columns: columns}
+	_ = w.writer.Write(w.columns)
+	return w
+}
+
+func (w *debugWriter) write(row filesystem.Row) {
+	if w == nil {
 		return
 	}
-	defer f.Close()
-	err = df.WriteCSV(f, dataframe.WriteHeader(true))
-	if err != nil {
-		fmt.Println("Error writing CSV:", err)
+	_ = w.writer.Write(row.Select(w.columns))
+}
+
+func (w *debugWriter) close() {
+	if w == nil {
 		return
 	}
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		fmt.Println("Error writing CSV:", err)
+	}
+	w.file.Close()
 }
 
 /*
-Finds rows in the dataframe that match the given codes in the specified column synchronously.
+FindRows streams the file at path and calls handle for every row whose codeColumn value is one of codes.
+The layout version of the file is picked from its header, and codeColumn is translated to that version.
+Rows are never accumulated, so memory usage stays bounded regardless of the file size.
+It returns the number of matching rows, or filesystem.ErrEmptyFile when the file has no data rows.
 */
-func FindRowsSync(df dataframe.DataFrame, dfType service.DataType, codes []string, codeColumn string, debug bool) dataframe.DataFrame {
+func FindRows(ctx context.Context, path string, dfType service.DataType, codes []string, codeColumn string, debug bool, rejects *Rejections, handle func(Record) error) (int, error) {
+	return streamRows(ctx, path, dfType, codeColumn, newCodeSet(codes), debug, rejects, handle)
+}
 
-	filter := dataframe.F{
-		Colname:    codeColumn,
-		Comparator: series.In,
-		Comparando: codes,
-	}
+// ScanRows is FindRows without filtering, for datasets that are not keyed by unit.
+func ScanRows(ctx context.Context, path string, dfType service.DataType, debug bool, rejects *Rejections, handle func(Record) error) (int, error) {
+	return streamRows(ctx, path, dfType, "", nil, debug, rejects, handle)
+}
+
+// cancelCheckRows is how many rows streamRows reads between checks of its
+// context, so a stopped ETL does not finish scanning a large file first.
+const cancelCheckRows = 1024
 
-	matchingRows := df.Filter(
-		filter,
-	)
+/*
+streamRows calls handle for the rows whose codeColumn value is in wanted, or
+for every row when wanted is nil. A row whose handle fails with a *FieldError
+is added to rejects and the scan goes on; any other error stops it, as does
+cancelling ctx.
+*/
+func streamRows(ctx context.Context, path string, dfType service.DataType, codeColumn string, wanted codeSet, debug bool, rejects *Rejections, handle func(Record) error) (int, error) {
+	reader, err := filesystem.OpenCSV(path)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
 
-	if matchingRows.Error() != nil {
-		return dataframe.DataFrame{}
+	// Column differences are reported by CheckSchema under its drift policy;
+	// only the match column is essential here.
+	layout, err := selectLayout(dfType, reader.Header())
+	if err != nil {
+		return 0, err
+	}
+	codeColumn = resolveColumn(layout, codeColumn)
+	if codeColumn != "" && !reader.HasColumn(codeColumn) {
+		return 0, fmt.Errorf("match column %q not found in %s", codeColumn, dfType)
 	}
-	if matchingRows.Nrow() > 0 {
-		matchingDf, err := SelectDataframeColumns(matchingRows, dfType)
+
+	dw := newDebugWriter(dfType, codeColumn, layout.ColumnNames(), debug)
+	defer dw.close()
+
+	matched := 0
+	for read := 0; ; read++ {
+		if read%cancelCheckRows == 0 && ctx.Err() != nil {
+			return matched, ctx.Err()
+		}
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
 		if err != nil {
-			fmt.Println("Error selecting columns:", err)
-			return dataframe.DataFrame{}
+			return matched, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if wanted != nil && !wanted.contains(row.Get(codeColumn)) {
+			continue
+		}
+		matched++
+		dw.write(row)
+		if err := handle(Record{Row: row, Layout: layout}); err != nil {
+			if rejects.add(dfType, path, err) {
+				continue
+			}
+			return matched, err
 		}
-		debugSaveDf(matchingDf, dfType, codeColumn, debug)
-		return matchingDf
 	}
 
-	return dataframe.DataFrame{}
+	if reader.Rows() == 0 {
+		return 0, filesystem.ErrEmptyFile
+	}
+	return matched, nil
 }
 
-func FilterExtractionByColumn(extraction service.OutputExpensesExtractionFiles, targetDataservice []service.DataType, codes []string, matchColumn string, chToRelease chan service.MatchingDataframe, wg *sync.WaitGroup, appLogger *logger.Logger) {
-	const component = "ExtractionFilter"
-	appLogger.Info(component, "Starting extraction filter: column=%s", matchColumn)
-	for _, dt := range targetDataservice {
-		if p, ok := extraction.Files[dt]; ok {
-			appLogger.Debug(component, "Processing file: date=%s type=%s path=%s", extraction.Date, service.DataTypeNames[dt], p)
-			df, err := filesystem.OpenFileAndDecode(p)
-			if err != nil {
-				appLogger.Warn(component, "Failed to open/decode file: date=%s type=%s path=%s error=%v", extraction.Date, service.DataTypeNames[dt], p, err)
-				continue
-			}
-			wg.Add(1)
-			go FindRows(df, dt, codes, matchColumn, extraction.Date, chToRelease, wg, appLogger)
+// Rejections collects the rows an extraction leaves out because a value did
+// not parse. It is safe for concurrent scans. A nil *Rejections rejects
+// nothing, so mapping errors abort the scan.
+type Rejections struct {
+	mu   sync.Mutex
+	rows []model.RowRejection
+}
+
+// add records err when it is a *FieldError and reports whether it did.
+func (r *Rejections) add(dfType service.DataType, path string, err error) bool {
+	var fieldErr *FieldError
+	if r == nil || !errors.As(err, &fieldErr) {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows = append(r.rows, model.RowRejection{
+		DataType:   dfType.String(),
+		SourceFile: filepath.Base(path),
+		Line:       fieldErr.Line,
+		Column:     fieldErr.Column,
+		RawValue:   fieldErr.Value,
+		Reason:     fieldErr.Reason,
+	})
+	return true
+}
+
+// List returns the rejected rows so far.
+func (r *Rejections) List() []model.RowRejection {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]model.RowRejection(nil), r.rows...)
+}
+
+// Len returns the number of rejected rows so far.
+func (r *Rejections) Len() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.rows)
+}
+
+// rowScan describes one file to be streamed by scanFiles.
+type rowScan struct {
+	dfType service.DataType
+	codes  []string
+	column string
+	handle func(Record) error
+}
+
+/*
+scanFiles streams every requested file of the extraction concurrently, one goroutine per file.
+Missing or empty files are logged and treated as having no matching rows; any other error aborts the scan.
+Each handle is only ever called from its own goroutine.
+*/
+func scanFiles(ctx context.Context, files map[service.DataType]string, reference string, scans []rowScan, debug bool, rejects *Rejections, appLogger *logger.Logger) error {
+	const component = "DataFilter"
+	var wg sync.WaitGroup
+	errs := make(chan error, len(scans))
+
+	for _, scan := range scans {
+		p, ok := files[scan.dfType]
+		if !ok {
+			appLogger.Warn(component, "File not configured: ref=%s type=%s", reference, scan.dfType)
+			continue
 		}
+
+		wg.Add(1)
+		go func(scan rowScan, path string) {
+			defer wg.Done()
+			appLogger.Debug(component, "Starting row search: ref=%s type=%s column=%s codesCount=%d", reference, scan.dfType, scan.column, len(scan.codes))
+
+			matched, err := FindRows(ctx, path, scan.dfType, scan.codes, scan.column, debug, rejects, scan.handle)
+			switch {
+			case errors.Is(err, os.ErrNotExist), errors.Is(err, filesystem.ErrEmptyFile):
+				appLogger.Warn(component, "No rows available: ref=%s type=%s path=%s error=%v", reference, scan.dfType, path, err)
+			case err != nil:
+				appLogger.Error(component, "Row search failed: ref=%s type=%s error=%v", reference, scan.dfType, err)
+				errs <- fmt.Errorf("%s: %w", scan.dfType, err)
+			default:
+				appLogger.Info(component, "Row search completed: ref=%s type=%s matchingRows=%d", reference, scan.dfType, matched)
+			}
+		}(scan, p)
 	}
 
-	appLogger.Info(component, "Extraction filter completed")
+	wg.Wait()
+	close(errs)
+
+	var failures []error
+	for err := range errs {
+		failures = append(failures, err)
+	}
+	return errors.Join(failures...)
 }
@@ -0,0 +1,107 @@
+package portal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+// RecheckSource asks the source whether the file behind fp was republished
+// since it was ingested. A changed file's local copy is removed so the next run
+// fetches it again.
+func (c *transparencyPortalClient) RecheckSource(ctx context.Context, fp model.SourceFingerprint) (bool, error) {
+	const component = "Recheck"
+
+	changed, err := c.source.Recheck(ctx, fp)
+	if err != nil {
+		return false, err
+	}
+	if changed {
+		c.logger.Info(component, "Source republished upstream: key=%s url=%s", fp.Key, fp.URL)
+		if fp.Path != "" {
+			if err := os.Remove(fp.Path); err != nil && !os.IsNotExist(err) {
+				c.logger.Warn(component, "Failed to remove stale archive: path=%s error=%v", fp.Path, err)
+			}
+			os.Remove(fp.Path + ".json")
+		}
+	}
+	return changed, nil
+}
+
+/*
+Recheck issues a request conditional on the recorded ETag and Last-Modified, so
+an unchanged file costs a 304. When the server ignores the validators, the ETag
+or Last-Modified of the response decides, and as a last resort the body is
+hashed and compared with the recorded SHA-256.
+*/
+func (s *httpSource) Recheck(ctx context.Context, fp model.SourceFingerprint) (bool, error) {
+	url := fp.URL
+	if fp.Key != "" {
+		url = s.urlFor(Archive{Key: fp.Key, Name: fp.Name})
+	}
+
+	req, err := s.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, &service.DownloadError{URL: url, Err: fmt.Errorf("failed to create HTTP request: %w", err)}
+	}
+	if fp.ETag != "" {
+		req.Header.Set("If-None-Match", fp.ETag)
+	}
+	if fp.LastModified != "" {
+		req.Header.Set("If-Modified-Since", fp.LastModified)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, &service.DownloadError{Kind: service.ErrNetwork, URL: url, Err: err}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		s.logger.Debug("Recheck", "Source unchanged: url=%s", url)
+		return false, nil
+	case http.StatusOK:
+	default:
+		return false, &service.DownloadError{
+			Kind:       statusKind(resp.StatusCode),
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	etag, lastModified := s.validators(resp.Header)
+	switch {
+	case fp.ETag != "" && etag != "":
+		return etag != fp.ETag, nil
+	case fp.LastModified != "" && lastModified != "":
+		return lastModified != fp.LastModified, nil
+	case resp.ContentLength >= 0 && fp.Size > 0 && resp.ContentLength != fp.Size:
+		return true, nil
+	}
+
+	changed, err := digestDiffers(fp, resp.Body)
+	if err != nil {
+		return false, &service.DownloadError{Kind: service.ErrNetwork, URL: url, Err: err}
+	}
+	return changed, nil
+}
+
+// digestDiffers hashes r and compares it with the recorded size and SHA-256.
+func digestDiffers(fp model.SourceFingerprint, r io.Reader) (bool, error) {
+	h := sha256.New()
+	size, err := io.Copy(h, r)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash source: %w", err)
+	}
+	return size != fp.Size || hex.EncodeToString(h.Sum(nil)) != fp.SHA256, nil
+}
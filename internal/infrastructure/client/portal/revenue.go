@@ -0,0 +1,78 @@
+package portal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+func (c *transparencyPortalClient) FetchRevenue(ctx context.Context, year string) (service.DownloadResult, error) {
+	return c.fetch(ctx, Archive{Key: "receitas/" + year, Name: "revenue/" + year + "_Receitas.zip"})
+}
+
+// revenueKey identifies a revenue row within a unit once launches of the same
+// month and classification are summed.
+type revenueKey struct {
+	referenceMonth string
+	category       string
+	origin         string
+	species        string
+	detail         string
+}
+
+// ExtractRevenue reads the yearly receitas file for the requested units and sums
+// rows sharing the same month and classification.
+func (c *transparencyPortalClient) ExtractRevenue(ctx context.Context, cfg service.RevenueExtractionConfig) (*service.RevenuePayload, error) {
+	const component = "DataExtractor"
+	rejects := &Rejections{}
+
+	fiscalYear, err := strconv.Atoi(cfg.Extraction.Year)
+	if err != nil {
+		return nil, fmt.Errorf("invalid revenue year %q: %w", cfg.Extraction.Year, err)
+	}
+
+	unitIndex := make(map[int32]int)
+	rowIndex := make(map[int32]map[revenueKey]int)
+	var units []service.UnitRevenues
+
+	matched, err := FindRows(ctx, cfg.Extraction.File, service.Receitas, cfg.Codes, string(MatchByRevenueUnitCode), c.debug, rejects, func(row Record) error {
+		revenue, err := DfRowToRevenue(row)
+		if err != nil {
+			return fmt.Errorf("failed to map revenue row: %w", err)
+		}
+		revenue.FiscalYear = int32(fiscalYear)
+
+		i, ok := unitIndex[revenue.ManagementUnitCode]
+		if !ok {
+			i = len(units)
+			unitIndex[revenue.ManagementUnitCode] = i
+			rowIndex[revenue.ManagementUnitCode] = make(map[revenueKey]int)
+			units = append(units, service.UnitRevenues{UgCode: revenue.ManagementUnitCode})
+		}
+
+		key := revenueKey{revenue.ReferenceMonth, revenue.EconomicCategory, revenue.RevenueOrigin, revenue.RevenueSpecies, revenue.RevenueDetail}
+		if j, ok := rowIndex[revenue.ManagementUnitCode][key]; ok {
+			existing := &units[i].Revenues[j]
+			existing.ForecastValue += revenue.ForecastValue
+			existing.LaunchedValue += revenue.LaunchedValue
+			existing.CollectedValue += revenue.CollectedValue
+			return nil
+		}
+		rowIndex[revenue.ManagementUnitCode][key] = len(units[i].Revenues)
+		units[i].Revenues = append(units[i].Revenues, revenue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Info(component, "Filtered revenue rows: year=%s rows=%d units=%d", cfg.Extraction.Year, matched, len(units))
+
+	return &service.RevenuePayload{
+		ExtractionDate: cfg.Extraction.Year,
+		FiscalYear:     int32(fiscalYear),
+		UnitsRevenues:  units,
+		Rejections:     rejects.List(),
+	}, nil
+}
@@ -0,0 +1,184 @@
+package portal
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+)
+
+// S3Config points at a bucket of an S3-compatible store such as MinIO.
+// Objects are addressed path-style as <Endpoint>/<Bucket>/<Prefix><Archive.Name>.
+type S3Config struct {
+	Endpoint  string // e.g. "http://localhost:9000"
+	Bucket    string
+	Prefix    string
+	Region    string // "us-east-1" when empty
+	AccessKey string // requests are unsigned when empty
+	SecretKey string
+}
+
+// s3Source reads archives from a bucket with the same resumable downloader
+// as the portal, signing requests with AWS Signature Version 4.
+type s3Source struct {
+	*httpSource
+	signer *s3Signer
+}
+
+const (
+	s3MetaOriginETag         = "X-Amz-Meta-Origin-Etag"
+	s3MetaOriginLastModified = "X-Amz-Meta-Origin-Last-Modified"
+)
+
+func NewS3Source(logger *logger.Logger, cfg S3Config) (Source, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 source requires an endpoint and a bucket")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	base := strings.TrimRight(cfg.Endpoint, "/") + "/" + cfg.Bucket + "/" + cfg.Prefix
+
+	source := &s3Source{httpSource: newHTTPSource(logger, func(a Archive) string { return base + a.Name })}
+	// Mirrored objects carry the portal's validators as metadata, so a recheck
+	// compares against the upstream version rather than the upload.
+	source.validators = func(h http.Header) (string, string) {
+		if etag, modified := h.Get(s3MetaOriginETag), h.Get(s3MetaOriginLastModified); etag != "" || modified != "" {
+			return etag, modified
+		}
+		return headerValidators(h)
+	}
+	if cfg.AccessKey != "" {
+		source.signer = &s3Signer{region: cfg.Region, accessKey: cfg.AccessKey, secretKey: cfg.SecretKey, now: time.Now}
+		source.sign = source.signer.sign
+	}
+	return source, nil
+}
+
+func (s *s3Source) store(ctx context.Context, a Archive, path string, origin service.DownloadResult) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.urlFor(a), f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Content-Type", "application/zip")
+	if origin.ETag != "" {
+		req.Header.Set(s3MetaOriginETag, origin.ETag)
+	}
+	if origin.LastModified != "" {
+		req.Header.Set(s3MetaOriginLastModified, origin.LastModified)
+	}
+	// Metadata headers must be signed, so sign only once they are all set.
+	if s.signer != nil {
+		s.signer.sign(req)
+	}
+	return s.do(req)
+}
+
+func (s *s3Source) evict(ctx context.Context, a Archive) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, s.urlFor(a), nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req)
+}
+
+func (s *s3Source) do(req *http.Request) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("s3 %s %s: unexpected status %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+	return nil
+}
+
+// s3Signer implements AWS Signature Version 4 for single-chunk S3 requests.
+// Payloads are sent as UNSIGNED-PAYLOAD, which S3 and MinIO accept.
+type s3Signer struct {
+	region    string
+	accessKey string
+	secretKey string
+	now       func() time.Time
+}
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+func (s *s3Signer) sign(req *http.Request) {
+	t := s.now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	day := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	// Host and every x-amz-* header are signed; others (Range, conditionals) are not.
+	names := []string{"host"}
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-amz-") {
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		value := req.URL.Host
+		if name != "host" {
+			value = strings.TrimSpace(req.Header.Get(name))
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), day)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
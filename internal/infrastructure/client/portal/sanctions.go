@@ -0,0 +1,56 @@
+package portal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+var sanctionListPaths = map[service.DataType]string{
+	service.SancoesCEIS: "ceis",
+	service.SancoesCNEP: "cnep",
+}
+
+// FetchSanctions downloads one daily snapshot of a sanction list.
+func (c *transparencyPortalClient) FetchSanctions(ctx context.Context, list service.DataType, date string) (service.DownloadResult, error) {
+	path, ok := sanctionListPaths[list]
+	if !ok {
+		return service.DownloadResult{}, fmt.Errorf("unknown sanction list: %s", list)
+	}
+	return c.fetch(ctx, Archive{Key: path + "/" + date, Name: "sanctions/" + date + "_" + service.SanctionDataTypeNames[list] + ".zip"})
+}
+
+// ExtractSanctions reads every entry of the CEIS and CNEP files. The lists are
+// not scoped to units, so cfg.Codes does not apply.
+func (c *transparencyPortalClient) ExtractSanctions(ctx context.Context, cfg service.SanctionsExtractionConfig) (*service.SanctionsPayload, error) {
+	const component = "DataExtractor"
+	rejects := &Rejections{}
+
+	var sanctions []model.Sanction
+	for _, list := range []service.DataType{service.SancoesCEIS, service.SancoesCNEP} {
+		path, ok := cfg.Extraction.Files[list]
+		if !ok {
+			continue
+		}
+		matched, err := ScanRows(ctx, path, list, c.debug, rejects, func(row Record) error {
+			sanction, err := DfRowToSanction(row)
+			if err != nil {
+				return fmt.Errorf("failed to map %s row: %w", list, err)
+			}
+			sanctions = append(sanctions, sanction)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.logger.Info(component, "Read sanction rows: list=%s date=%s rows=%d", list, cfg.Extraction.Date, matched)
+	}
+
+	return &service.SanctionsPayload{
+		ExtractionDate: cfg.Extraction.Date,
+		Sanctions:      sanctions,
+		Rejections:     rejects.List(),
+	}, nil
+}
@@ -0,0 +1,220 @@
+package portal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/filesystem"
+)
+
+// DriftPolicy decides what a schema drift does to the ingestion.
+type DriftPolicy string
+
+const (
+	// DriftFail rejects files with removed or renamed columns. New columns are
+	// only reported, since the extractors ignore them.
+	DriftFail DriftPolicy = "fail"
+	// DriftWarn reports every drift and loads the file anyway; missing columns
+	// load as empty values.
+	DriftWarn DriftPolicy = "warn"
+)
+
+// ParseDriftPolicy reads a policy name, defaulting to DriftFail.
+func ParseDriftPolicy(name string) (DriftPolicy, error) {
+	switch DriftPolicy(strings.ToLower(name)) {
+	case "", DriftFail:
+		return DriftFail, nil
+	case DriftWarn:
+		return DriftWarn, nil
+	default:
+		return "", fmt.Errorf("unknown schema drift policy: %s", name)
+	}
+}
+
+// renameThreshold is the minimum similarity for a new column to be suggested
+// as the rename of a missing one.
+const renameThreshold = 0.75
+
+func (c *transparencyPortalClient) CheckSchema(ctx context.Context, files map[service.DataType]string) ([]model.SchemaDrift, error) {
+	const component = "SchemaCheck"
+
+	var drifts []model.SchemaDrift
+	var failures []string
+	for dfType, path := range files {
+		if err := validateDataTypeForTransformation(dfType); err != nil {
+			return nil, err
+		}
+
+		reader, err := filesystem.OpenCSV(path)
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, filesystem.ErrEmptyFile) {
+			// Missing and empty files are handled by the extraction itself.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		header := reader.Header()
+		reader.Close()
+
+		// Drift is measured against the closest known layout, so a file in
+		// any registered layout version passes.
+		layout, err := selectLayout(dfType, header)
+		if err != nil {
+			return nil, err
+		}
+		drift := detectDrift(layout.ColumnNames(), header)
+		if len(drift.Added) == 0 && len(drift.Removed) == 0 && len(drift.Renamed) == 0 {
+			continue
+		}
+		drift.DataType = dfType.String()
+		drift.File = filepath.Base(path)
+		drift.LayoutVersion = layout.Version
+		drifts = append(drifts, drift)
+
+		c.logger.Warn(component, "Schema drift detected: type=%s file=%s layout=v%d added=%v removed=%v renamed=%s",
+			drift.DataType, drift.File, drift.LayoutVersion, drift.Added, drift.Removed, formatRenames(drift.Renamed))
+		if len(drift.Removed) > 0 || len(drift.Renamed) > 0 {
+			failures = append(failures, drift.DataType)
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].DataType < drifts[j].DataType })
+	if c.driftPolicy == DriftFail && len(failures) > 0 {
+		sort.Strings(failures)
+		return drifts, fmt.Errorf("columns removed or renamed in %s: %w", strings.Join(failures, ", "), service.ErrSchemaDrift)
+	}
+	return drifts, nil
+}
+
+/*
+detectDrift compares a file header with the expected columns. Every missing
+column is paired with the most similar unexpected one (greedily, best pairs
+first) when they are at least renameThreshold alike; the rest are reported as
+removed and added.
+*/
+func detectDrift(expected, header []string) model.SchemaDrift {
+	inHeader := make(map[string]bool, len(header))
+	for _, col := range header {
+		inHeader[col] = true
+	}
+	isExpected := make(map[string]bool, len(expected))
+	var removed []string
+	for _, col := range expected {
+		isExpected[col] = true
+		if !inHeader[col] {
+			removed = append(removed, col)
+		}
+	}
+	var added []string
+	for _, col := range header {
+		if !isExpected[col] {
+			added = append(added, col)
+		}
+	}
+
+	type candidate struct {
+		expected, found string
+		score           float64
+	}
+	var candidates []candidate
+	for _, r := range removed {
+		for _, a := range added {
+			if score := columnSimilarity(r, a); score >= renameThreshold {
+				candidates = append(candidates, candidate{r, a, score})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	var drift model.SchemaDrift
+	paired := make(map[string]bool)
+	for _, c := range candidates {
+		if paired[c.expected] || paired[c.found] {
+			continue
+		}
+		paired[c.expected], paired[c.found] = true, true
+		drift.Renamed = append(drift.Renamed, model.ColumnRename{Expected: c.expected, Found: c.found, Similarity: c.score})
+	}
+	for _, r := range removed {
+		if !paired[r] {
+			drift.Removed = append(drift.Removed, r)
+		}
+	}
+	for _, a := range added {
+		if !paired[a] {
+			drift.Added = append(drift.Added, a)
+		}
+	}
+	return drift
+}
+
+// columnSimilarity is 1 minus the edit distance between the normalized names,
+// relative to the longer one. Case, accents and punctuation do not count.
+func columnSimilarity(a, b string) float64 {
+	ra, rb := []rune(normalizeColumn(a)), []rune(normalizeColumn(b))
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+)
+
+func normalizeColumn(name string) string {
+	name = accentReplacer.Replace(strings.ToLower(name))
+	var b strings.Builder
+	space := false
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			space = false
+		} else if !space && b.Len() > 0 {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func formatRenames(renames []model.ColumnRename) string {
+	parts := make([]string, len(renames))
+	for i, r := range renames {
+		parts[i] = fmt.Sprintf("%q->%q(%.2f)", r.Expected, r.Found, r.Similarity)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
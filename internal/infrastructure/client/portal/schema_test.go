@@ -0,0 +1,36 @@
+package portal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+func TestDetectDrift(t *testing.T) {
+	expected := []string{"Código Empenho", "Código Unidade Gestora", "Valor do Empenho Convertido pra R$", "Observação"}
+	header := []string{"Código do Empenho", "Código Unidade Gestora", "Valor do Empenho Convertido pra R$", "Data de Registro"}
+
+	drift := detectDrift(expected, header)
+
+	if !reflect.DeepEqual(drift.Added, []string{"Data de Registro"}) {
+		t.Errorf("added = %v, want [Data de Registro]", drift.Added)
+	}
+	if !reflect.DeepEqual(drift.Removed, []string{"Observação"}) {
+		t.Errorf("removed = %v, want [Observação]", drift.Removed)
+	}
+	if len(drift.Renamed) != 1 {
+		t.Fatalf("renamed = %v, want one rename", drift.Renamed)
+	}
+	if got := drift.Renamed[0]; got.Expected != "Código Empenho" || got.Found != "Código do Empenho" {
+		t.Errorf("rename = %+v, want Código Empenho -> Código do Empenho", got)
+	}
+}
+
+func TestDetectDriftMatchingHeader(t *testing.T) {
+	columns := []string{"Código Empenho", "Observação"}
+	drift := detectDrift(columns, columns)
+	if !reflect.DeepEqual(drift, model.SchemaDrift{}) {
+		t.Errorf("drift = %+v, want none", drift)
+	}
+}
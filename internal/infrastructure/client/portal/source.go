@@ -0,0 +1,133 @@
+package portal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
+)
+
+// Archive identifies one downloadable file. Key is its path under the portal's
+// download root, e.g. "despesas/20250101". Name is where it lives in the zip
+// directory and in mirrors, e.g. "expenses/despesas_20250101.zip"; unlike Key
+// it is unique per ingested period, since some portal paths always serve the
+// latest snapshot.
+type Archive struct {
+	Key  string
+	Name string
+}
+
+// Source retrieves portal archives into a local file.
+//
+// Fetch must only leave outputPath in place once it holds a complete, valid
+// zip, and reports failures as a *service.DownloadError. A missing archive is
+// reported with kind service.ErrNotPublished.
+type Source interface {
+	Fetch(ctx context.Context, a Archive, outputPath string) (service.DownloadResult, error)
+	// Recheck reports whether the archive behind fp changed since it was fetched.
+	Recheck(ctx context.Context, fp model.SourceFingerprint) (bool, error)
+}
+
+// cacheSource is a Source that can also keep archives fetched elsewhere.
+type cacheSource interface {
+	Source
+	// store keeps the file at path as a, along with the upstream validators of origin.
+	store(ctx context.Context, a Archive, path string, origin service.DownloadResult) error
+	// evict drops a so the next fetch goes upstream again.
+	evict(ctx context.Context, a Archive) error
+}
+
+// mirrorSource serves archives from a cache and falls back to the origin,
+// storing what it downloads, so several hosts can share one download cache.
+type mirrorSource struct {
+	logger *logger.Logger
+	cache  cacheSource
+	origin Source
+}
+
+// NewMirrorSource reads through cache, a directory or S3 source, to origin.
+func NewMirrorSource(logger *logger.Logger, cache Source, origin Source) (Source, error) {
+	c, ok := cache.(cacheSource)
+	if !ok {
+		return nil, fmt.Errorf("source %T cannot be used as a mirror", cache)
+	}
+	return &mirrorSource{logger: logger, cache: c, origin: origin}, nil
+}
+
+func (m *mirrorSource) Fetch(ctx context.Context, a Archive, outputPath string) (service.DownloadResult, error) {
+	const component = "Mirror"
+
+	result, err := m.cache.Fetch(ctx, a, outputPath)
+	if err == nil {
+		m.logger.Debug(component, "Served from mirror: name=%s", a.Name)
+		return result, nil
+	}
+	m.logger.Debug(component, "Mirror miss, fetching upstream: name=%s error=%v", a.Name, err)
+
+	result, err = m.origin.Fetch(ctx, a, outputPath)
+	if err != nil {
+		return service.DownloadResult{}, err
+	}
+	if err := m.cache.store(ctx, a, outputPath, result); err != nil {
+		m.logger.Warn(component, "Failed to store archive in mirror: name=%s error=%v", a.Name, err)
+	}
+	return result, nil
+}
+
+// Recheck asks the origin, since the mirror only changes when it does, and
+// evicts republished archives from the mirror.
+func (m *mirrorSource) Recheck(ctx context.Context, fp model.SourceFingerprint) (bool, error) {
+	changed, err := m.origin.Recheck(ctx, fp)
+	if err != nil || !changed {
+		return changed, err
+	}
+	if err := m.cache.evict(ctx, Archive{Key: fp.Key, Name: fp.Name}); err != nil {
+		m.logger.Warn("Mirror", "Failed to evict republished archive: name=%s error=%v", fp.Name, err)
+	}
+	return true, nil
+}
+
+// SourceConfig selects the archive backend.
+type SourceConfig struct {
+	// Backend is "portal" (default), "dir" or "s3".
+	Backend string
+	// PortalURL overrides PortalTransparenciaURL.
+	PortalURL string
+	// Dir is the archive directory of the "dir" backend.
+	Dir string
+	S3  S3Config
+	// Mirror makes a "dir" or "s3" backend fall back to the portal and keep
+	// what it downloads.
+	Mirror bool
+}
+
+// NewSource builds the Source described by cfg.
+func NewSource(logger *logger.Logger, cfg SourceConfig) (Source, error) {
+	portal := NewPortalSource(logger, cfg.PortalURL)
+
+	var source Source
+	switch cfg.Backend {
+	case "", "portal":
+		return portal, nil
+	case "dir":
+		if cfg.Dir == "" {
+			return nil, fmt.Errorf("dir source requires a directory")
+		}
+		source = NewDirSource(cfg.Dir)
+	case "s3":
+		s3, err := NewS3Source(logger, cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		source = s3
+	default:
+		return nil, fmt.Errorf("unknown source backend: %s", cfg.Backend)
+	}
+
+	if cfg.Mirror {
+		return NewMirrorSource(logger, source, portal)
+	}
+	return source, nil
+}
@@ -0,0 +1,125 @@
+package portal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+)
+
+func (c *transparencyPortalClient) FetchTravel(ctx context.Context, year string) (service.DownloadResult, error) {
+	return c.fetch(ctx, Archive{Key: "viagens/" + year, Name: "travel/" + year + "_Viagens.zip"})
+}
+
+// ExtractTravel runs in two passes: payments are matched on the paying unit,
+// then trips and tickets are matched on the trip ids those payments reference.
+func (c *transparencyPortalClient) ExtractTravel(ctx context.Context, cfg service.TravelExtractionConfig) (*service.TravelPayload, error) {
+	const component = "DataExtractor"
+	rejects := &Rejections{}
+	ref := cfg.Extraction.Year
+
+	fiscalYear, err := strconv.Atoi(cfg.Extraction.Year)
+	if err != nil {
+		return nil, fmt.Errorf("invalid travel year %q: %w", cfg.Extraction.Year, err)
+	}
+
+	c.logger.Info(component, "Starting travel extraction: ref=%s codesCount=%d", ref, len(cfg.Codes))
+
+	paymentsFile, ok := cfg.Extraction.Files[service.ViagemPagamento]
+	if !ok {
+		return nil, fmt.Errorf("travel payments file not configured for %s", ref)
+	}
+
+	paymentsByTrip := make(map[string][]model.TripPayment)
+	var tripIDs []string
+	matched, err := FindRows(ctx, paymentsFile, service.ViagemPagamento, cfg.Codes, string(MatchByTravelPayingUnitCode), c.debug, rejects, func(row Record) error {
+		payment, err := DfRowToTripPayment(row)
+		if err != nil {
+			return fmt.Errorf("failed to map travel payment row: %w", err)
+		}
+		if _, seen := paymentsByTrip[payment.TripID]; !seen {
+			tripIDs = append(tripIDs, payment.TripID)
+		}
+		paymentsByTrip[payment.TripID] = append(paymentsByTrip[payment.TripID], payment)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Info(component, "Filtered travel payment rows: ref=%s rows=%d trips=%d", ref, matched, len(tripIDs))
+
+	if len(tripIDs) == 0 {
+		c.logger.Warn(component, "No trips paid by the provided codes: ref=%s", ref)
+		return &service.TravelPayload{ExtractionDate: ref, Rejections: rejects.List()}, nil
+	}
+
+	var (
+		mu       sync.Mutex
+		trips    []model.Trip
+		passages []model.TripPassage
+	)
+	column := string(MatchByTripID)
+	err = scanFiles(ctx, cfg.Extraction.Files, ref, []rowScan{
+		{dfType: service.Viagem, codes: tripIDs, column: column, handle: func(row Record) error {
+			trip, err := DfRowToTrip(row)
+			if err != nil {
+				return fmt.Errorf("failed to map trip row: %w", err)
+			}
+			trip.FiscalYear = int32(fiscalYear)
+			mu.Lock()
+			trips = append(trips, trip)
+			mu.Unlock()
+			return nil
+		}},
+		{dfType: service.ViagemPassagem, codes: tripIDs, column: column, handle: func(row Record) error {
+			passage, err := DfRowToTripPassage(row)
+			if err != nil {
+				return fmt.Errorf("failed to map travel passage row: %w", err)
+			}
+			mu.Lock()
+			passages = append(passages, passage)
+			mu.Unlock()
+			return nil
+		}},
+	}, c.debug, rejects, c.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	passagesByTrip := make(map[string][]model.TripPassage)
+	for _, p := range passages {
+		passagesByTrip[p.TripID] = append(passagesByTrip[p.TripID], p)
+	}
+
+	// A trip paid by several of the requested units is kept under the first one.
+	unitIndex := make(map[int32]int)
+	var units []service.UnitTrips
+	for _, trip := range trips {
+		payments := paymentsByTrip[trip.TripID]
+		trip.Payments = payments
+		trip.Passages = passagesByTrip[trip.TripID]
+		if len(payments) > 0 {
+			trip.ManagementUnitCode = payments[0].ManagementUnitCode
+			trip.ManagementUnitName = payments[0].ManagementUnitName
+		}
+
+		i, ok := unitIndex[trip.ManagementUnitCode]
+		if !ok {
+			i = len(units)
+			unitIndex[trip.ManagementUnitCode] = i
+			units = append(units, service.UnitTrips{UgCode: trip.ManagementUnitCode})
+		}
+		units[i].Trips = append(units[i].Trips, trip)
+	}
+
+	c.logger.Info(component, "Travel extraction completed: ref=%s trips=%d passages=%d units=%d", ref, len(trips), len(passages), len(units))
+
+	return &service.TravelPayload{
+		ExtractionDate: ref,
+		UnitsTrips:     units,
+		Rejections:     rejects.List(),
+	}, nil
+}
@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 func GetString(key, fallback string) string {
@@ -26,3 +27,17 @@ func GetInt(key string, fallback int) int {
 	}
 	return valInt
 }
+
+func GetDuration(key string, fallback time.Duration) time.Duration {
+	val, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	valDuration, err := time.ParseDuration(val)
+
+	if err != nil {
+		return fallback
+	}
+	return valDuration
+}
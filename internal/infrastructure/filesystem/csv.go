@@ -0,0 +1,122 @@
+package filesystem
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ErrEmptyFile is returned when a CSV file has no header or no data rows.
+// It wraps service.ErrEmptyDataset so pipelines can skip it by kind.
+var ErrEmptyFile = fmt.Errorf("file is empty: %w", service.ErrEmptyDataset)
+
+// Row is a single decoded CSV record addressable by column name.
+// Values are kept as raw strings; typing is left to the mappers.
+type Row struct {
+	Line   int
+	index  map[string]int
+	values []string
+}
+
+// Get returns the raw value of col, or "" when the column is absent.
+func (r Row) Get(col string) string {
+	i, ok := r.index[col]
+	if !ok || i >= len(r.values) {
+		return ""
+	}
+	return r.values[i]
+}
+
+// Select returns the values of cols in the given order.
+func (r Row) Select(cols []string) []string {
+	out := make([]string, len(cols))
+	for i, col := range cols {
+		out[i] = r.Get(col)
+	}
+	return out
+}
+
+// CSVReader streams a portal CSV file one record at a time, so memory usage
+// does not depend on the size of the file.
+type CSVReader struct {
+	file   *os.File
+	reader *csv.Reader
+	header []string
+	index  map[string]int
+	rows   int
+}
+
+// OpenCSV opens path and reads its header. The caller must Close the reader.
+func OpenCSV(path string) (*CSVReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", path, err)
+	}
+
+	// Using Windows1252 because it is the encoding used by the original CSV files
+	decoded := charmap.Windows1252.NewDecoder().Reader(bufio.NewReaderSize(file, 64*1024))
+	reader := csv.NewReader(decoded)
+	reader.Comma = ';'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		file.Close()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmptyFile
+		}
+		return nil, fmt.Errorf("failed to read header of %s: %w", path, err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, exists := index[name]; !exists {
+			index[name] = i
+		}
+	}
+
+	return &CSVReader{
+		file:   file,
+		reader: reader,
+		header: header,
+		index:  index,
+	}, nil
+}
+
+// Header returns the column names in file order.
+func (r *CSVReader) Header() []string {
+	return r.header
+}
+
+// HasColumn reports whether the header contains col.
+func (r *CSVReader) HasColumn(col string) bool {
+	_, ok := r.index[col]
+	return ok
+}
+
+// Next returns the next record. It returns io.EOF when the file is exhausted.
+func (r *CSVReader) Next() (Row, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		return Row{}, err
+	}
+	r.rows++
+	line, _ := r.reader.FieldPos(0)
+	return Row{Line: line, index: r.index, values: record}, nil
+}
+
+// Rows returns the number of data rows read so far.
+func (r *CSVReader) Rows() int {
+	return r.rows
+}
+
+func (r *CSVReader) Close() error {
+	return r.file.Close()
+}
@@ -0,0 +1,69 @@
+package filesystem
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func writeWindows1252(t *testing.T, content string) string {
+	t.Helper()
+	encoded, err := charmap.Windows1252.NewEncoder().String(content)
+	if err != nil {
+		t.Fatalf("failed to encode fixture: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "fixture.csv")
+	if err := os.WriteFile(path, []byte(encoded), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	return path
+}
+
+func TestCSVReader(t *testing.T) {
+	path := writeWindows1252(t, "Código Unidade Gestora;Observação\n158454;\"Pagamento \"referente\" a março\"\n158148;Serviço\n")
+
+	reader, err := OpenCSV(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer reader.Close()
+
+	if !reader.HasColumn("Código Unidade Gestora") {
+		t.Fatalf("expected decoded header, got %v", reader.Header())
+	}
+
+	var codes []string
+	var lines []int
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		codes = append(codes, row.Get("Código Unidade Gestora"))
+		lines = append(lines, row.Line)
+		if row.Get("missing") != "" {
+			t.Fatalf("expected empty value for missing column")
+		}
+	}
+
+	if len(codes) != 2 || codes[0] != "158454" || codes[1] != "158148" {
+		t.Fatalf("unexpected codes: %v", codes)
+	}
+	if lines[0] != 2 || lines[1] != 3 {
+		t.Fatalf("unexpected line numbers: %v", lines)
+	}
+}
+
+func TestOpenCSVEmpty(t *testing.T) {
+	path := writeWindows1252(t, "")
+	if _, err := OpenCSV(path); !errors.Is(err, ErrEmptyFile) {
+		t.Fatalf("expected ErrEmptyFile, got %v", err)
+	}
+}
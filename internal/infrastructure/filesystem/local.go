@@ -2,6 +2,8 @@ package filesystem
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -10,8 +12,6 @@ import (
 
 	"github.com/farxc/envelopa-transparencia/internal/domain/service"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
-	"github.com/go-gota/gota/dataframe"
-	"golang.org/x/text/encoding/charmap"
 )
 
 type ExtractionResult struct {
@@ -31,22 +31,7 @@ var dataserviceuffix = map[service.DataType]string{
 	service.DespesasPagamentoListaBancos:         service.DespesasPagamentoListaBancosDataType,
 	service.DespesasPagamentoListaFaturas:        service.DespesasPagamentoListaFaturasDataType,
 	service.DespesasPagamentoListaPrecatorios:    service.DespesasPagamentoListaPrecatoriosDataType,
-}
-
-var notUsedFiles = []service.DataType{
-	service.DespesasPagamentoListaBancos,
-	service.DespesasPagamentoListaFaturas,
-	service.DespesasPagamentoListaPrecatorios,
-}
-
-func isFileUsed(filename string) bool {
-	for _, v := range notUsedFiles {
-		if strings.HasSuffix(filename, dataserviceuffix[v]) {
-			return false
-		}
-	}
-
-	return true
+	service.DespesasPagamentoFavoricidosFinais:   service.DespesasPagamentoFavorecidosFinaisDataType,
 }
 
 func UnzipFile(zipPath string, destDir string, appLogger *logger.Logger) ExtractionResult {
@@ -72,7 +57,6 @@ func UnzipFile(zipPath string, destDir string, appLogger *logger.Logger) Extract
 	defer r.Close()
 
 	extractedCount := 0
-	skippedCount := 0
 
 	for _, f := range r.File {
 		filePath := filepath.Join(destDir, f.Name)
@@ -82,12 +66,6 @@ func UnzipFile(zipPath string, destDir string, appLogger *logger.Logger) Extract
 			return ExtractionResult{Success: false}
 		}
 
-		if !isFileUsed(f.Name) {
-			skippedCount++
-			appLogger.Debug(component, "Skipping unused file: file=%s", f.Name)
-			continue
-		}
-
 		destFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
 		if err != nil {
 			appLogger.Error(component, "Failed to create destination file: file=%s error=%v", filePath, err)
@@ -109,7 +87,7 @@ func UnzipFile(zipPath string, destDir string, appLogger *logger.Logger) Extract
 		extractedCount++
 	}
 
-	appLogger.Info(component, "Extraction completed: destDir=%s extractedFiles=%d skippedFiles=%d", destDir, extractedCount, skippedCount)
+	appLogger.Info(component, "Extraction completed: destDir=%s extractedFiles=%d", destDir, extractedCount)
 	return ExtractionResult{Success: true, Data: service.DespesasEmpenho, OutputDir: destDir}
 }
 
@@ -122,23 +100,33 @@ func BuildFilesForDate(date, dir string) map[service.DataType]string {
 	return m
 }
 
-func OpenFileAndDecode(path string) (dataframe.DataFrame, error) {
-	const component = "FileDecoder"
-
-	file, err := os.Open(path)
+// ValidateZip checks that path is a readable zip archive with at least one
+// entry. A truncated download fails here because its central directory is missing.
+func ValidateZip(path string) error {
+	r, err := zip.OpenReader(path)
 	if err != nil {
-		return dataframe.DataFrame{}, fmt.Errorf("failed to open file %s: %v", path, err)
+		return fmt.Errorf("invalid zip archive %s: %w", path, err)
 	}
+	defer r.Close()
 
-	defer file.Close()
+	if len(r.File) == 0 {
+		return fmt.Errorf("zip archive %s has no entries", path)
+	}
+	return nil
+}
 
-	// Using Windows1252 because it is the encoding used by the original CSV files
-	decoded := charmap.Windows1252.NewDecoder().Reader(file)
-	df := dataframe.ReadCSV(decoded, dataframe.WithDelimiter(';'), dataframe.WithLazyQuotes(true))
-	// If dataframe is empty return
-	if df.Nrow() == 0 {
-		return dataframe.DataFrame{}, fmt.Errorf("dataframe is empty")
+// FileDigest returns the size and hex-encoded SHA-256 of the file at path.
+func FileDigest(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
 	}
+	defer f.Close()
 
-	return df, df.Error()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
 }
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/lib/pq"
+)
+
+type AgreementStore struct {
+	db GenericQueryer
+}
+
+func (as *AgreementStore) InsertAgreement(ctx context.Context, agreement *model.Agreement) error {
+	query := `INSERT INTO agreements (
+		agreement_number,
+		process_number,
+		instrument_type,
+		situation,
+		object,
+		superior_organ_code,
+		superior_organ_name,
+		grantor_code,
+		grantor_name,
+		grantee_code,
+		grantee_name,
+		grantee_type,
+		federative_unit,
+		municipality_code,
+		municipality_name,
+		agreement_value,
+		released_value,
+		counterpart_value,
+		publication_date,
+		start_date,
+		end_date,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:agreement_number,
+		:process_number,
+		:instrument_type,
+		:situation,
+		:object,
+		:superior_organ_code,
+		:superior_organ_name,
+		:grantor_code,
+		:grantor_name,
+		:grantee_code,
+		:grantee_name,
+		:grantee_type,
+		:federative_unit,
+		:municipality_code,
+		:municipality_name,
+		:agreement_value,
+		:released_value,
+		:counterpart_value,
+		:publication_date,
+		:start_date,
+		:end_date,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (agreement_number) DO UPDATE SET
+		process_number = EXCLUDED.process_number,
+		instrument_type = EXCLUDED.instrument_type,
+		situation = EXCLUDED.situation,
+		object = EXCLUDED.object,
+		superior_organ_code = EXCLUDED.superior_organ_code,
+		superior_organ_name = EXCLUDED.superior_organ_name,
+		grantor_code = EXCLUDED.grantor_code,
+		grantor_name = EXCLUDED.grantor_name,
+		grantee_code = EXCLUDED.grantee_code,
+		grantee_name = EXCLUDED.grantee_name,
+		grantee_type = EXCLUDED.grantee_type,
+		federative_unit = EXCLUDED.federative_unit,
+		municipality_code = EXCLUDED.municipality_code,
+		municipality_name = EXCLUDED.municipality_name,
+		agreement_value = EXCLUDED.agreement_value,
+		released_value = EXCLUDED.released_value,
+		counterpart_value = EXCLUDED.counterpart_value,
+		publication_date = EXCLUDED.publication_date,
+		start_date = EXCLUDED.start_date,
+		end_date = EXCLUDED.end_date,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := as.db.NamedExec(query, agreement)
+	return err
+}
+
+// InsertAgreementDisbursement records a release. Releases are never deleted:
+// each monthly snapshot only carries the latest one, so older releases would
+// be lost on reconcile. A release seen again in a later snapshot has the same
+// released total and is not duplicated.
+func (as *AgreementStore) InsertAgreementDisbursement(ctx context.Context, disbursement *model.AgreementDisbursement) error {
+	query := `INSERT INTO agreement_disbursements (
+		agreement_number,
+		release_date,
+		value,
+		released_total,
+		reference_month,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:agreement_number,
+		:release_date,
+		:value,
+		:released_total,
+		:reference_month,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (agreement_number, release_date, value, released_total) DO UPDATE SET
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := as.db.NamedExec(query, disbursement)
+	return err
+}
+
+func (as *AgreementStore) GetAgreements(ctx context.Context, filter service.GetAgreementsFilter) ([]service.AgreementInformation, error) {
+	whereClause := "WHERE start_date BETWEEN $1 AND $2"
+	args := []interface{}{filter.StartDate, filter.EndDate}
+	argIndex := 3
+
+	if len(filter.ManagementUnitCodes) > 0 {
+		whereClause += fmt.Sprintf(" AND grantor_code::text = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.ManagementUnitCodes))
+		argIndex++
+	}
+
+	if filter.Situation != "" {
+		whereClause += fmt.Sprintf(" AND situation = $%d", argIndex)
+		args = append(args, filter.Situation)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			agreement_number, grantor_code, grantor_name,
+			grantee_code, grantee_name, instrument_type, situation, object,
+			federative_unit, municipality_name,
+			agreement_value, released_value, counterpart_value,
+			start_date, end_date
+		FROM agreements
+		%s
+		ORDER BY start_date DESC, agreement_number;
+	`, whereClause)
+
+	agreements := make([]service.AgreementInformation, 0)
+	if err := as.db.SelectContext(ctx, &agreements, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to query agreements: %w", err)
+	}
+	return agreements, nil
+}
+
+func (as *AgreementStore) GetAgreementDisbursements(ctx context.Context, agreementNumber string) ([]service.AgreementDisbursementInformation, error) {
+	query := `
+		SELECT agreement_number, release_date, value, released_total, reference_month
+		FROM agreement_disbursements
+		WHERE agreement_number = $1
+		ORDER BY release_date, released_total;
+	`
+
+	disbursements := make([]service.AgreementDisbursementInformation, 0)
+	if err := as.db.SelectContext(ctx, &disbursements, query, agreementNumber); err != nil {
+		return nil, fmt.Errorf("failed to query agreement disbursements: %w", err)
+	}
+	return disbursements, nil
+}
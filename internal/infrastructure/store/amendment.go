@@ -0,0 +1,193 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/lib/pq"
+)
+
+type AmendmentStore struct {
+	db GenericQueryer
+}
+
+// UpsertAuthor keeps the author dimension up to date. Blank names never
+// overwrite a known one, since execution rows sometimes omit it.
+func (as *AmendmentStore) UpsertAuthor(ctx context.Context, author *model.AmendmentAuthor) error {
+	query := `INSERT INTO amendment_authors (
+		author_code,
+		author_name,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:author_code,
+		:author_name,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (author_code) DO UPDATE SET
+		author_name = COALESCE(NULLIF(EXCLUDED.author_name, ''), amendment_authors.author_name),
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := as.db.NamedExec(query, author)
+	return err
+}
+
+func (as *AmendmentStore) InsertAmendment(ctx context.Context, amendment *model.Amendment) error {
+	query := `INSERT INTO amendments (
+		amendment_code,
+		year,
+		type,
+		author_code,
+		amendment_number,
+		locality,
+		municipality_code,
+		municipality,
+		federative_unit,
+		function_code,
+		function_name,
+		subfunction_code,
+		subfunction_name,
+		program_code,
+		program_name,
+		action_code,
+		action_name,
+		committed_value_brl,
+		liquidated_value_brl,
+		paid_value_brl,
+		registered_payables_value_brl,
+		canceled_payables_value_brl,
+		paid_payables_value_brl,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:amendment_code,
+		:year,
+		:type,
+		:author_code,
+		:amendment_number,
+		:locality,
+		:municipality_code,
+		:municipality,
+		:federative_unit,
+		:function_code,
+		:function_name,
+		:subfunction_code,
+		:subfunction_name,
+		:program_code,
+		:program_name,
+		:action_code,
+		:action_name,
+		:committed_value_brl,
+		:liquidated_value_brl,
+		:paid_value_brl,
+		:registered_payables_value_brl,
+		:canceled_payables_value_brl,
+		:paid_payables_value_brl,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (amendment_code) DO UPDATE SET
+		year = EXCLUDED.year,
+		type = EXCLUDED.type,
+		author_code = EXCLUDED.author_code,
+		amendment_number = EXCLUDED.amendment_number,
+		locality = EXCLUDED.locality,
+		municipality_code = EXCLUDED.municipality_code,
+		municipality = EXCLUDED.municipality,
+		federative_unit = EXCLUDED.federative_unit,
+		function_code = EXCLUDED.function_code,
+		function_name = EXCLUDED.function_name,
+		subfunction_code = EXCLUDED.subfunction_code,
+		subfunction_name = EXCLUDED.subfunction_name,
+		program_code = EXCLUDED.program_code,
+		program_name = EXCLUDED.program_name,
+		action_code = EXCLUDED.action_code,
+		action_name = EXCLUDED.action_name,
+		committed_value_brl = EXCLUDED.committed_value_brl,
+		liquidated_value_brl = EXCLUDED.liquidated_value_brl,
+		paid_value_brl = EXCLUDED.paid_value_brl,
+		registered_payables_value_brl = EXCLUDED.registered_payables_value_brl,
+		canceled_payables_value_brl = EXCLUDED.canceled_payables_value_brl,
+		paid_payables_value_brl = EXCLUDED.paid_payables_value_brl,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := as.db.NamedExec(query, amendment)
+	return err
+}
+
+func (as *AmendmentStore) GetAuthors(ctx context.Context) ([]service.AmendmentAuthorInformation, error) {
+	query := `
+		SELECT
+			aa.author_code,
+			aa.author_name,
+			COUNT(a.amendment_code) AS amendments_count
+		FROM amendment_authors aa
+		LEFT JOIN amendments a ON a.author_code = aa.author_code
+		GROUP BY aa.author_code, aa.author_name
+		ORDER BY aa.author_name;
+	`
+
+	authors := make([]service.AmendmentAuthorInformation, 0)
+	if err := as.db.SelectContext(ctx, &authors, query); err != nil {
+		return nil, fmt.Errorf("failed to query amendment authors: %w", err)
+	}
+	return authors, nil
+}
+
+func (as *AmendmentStore) GetAmendmentExecution(ctx context.Context, filter service.AmendmentsFilter) ([]service.AmendmentExecutionRow, error) {
+	whereClause := "WHERE e.management_code = $1 AND e.author_amendament_code <> 0"
+	args := []interface{}{filter.ManagementCode}
+	argIndex := 2
+
+	if len(filter.ManagementUnitCodes) > 0 {
+		whereClause += fmt.Sprintf(" AND e.management_unit_code = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.ManagementUnitCodes))
+		argIndex++
+	}
+
+	if len(filter.AuthorCodes) > 0 {
+		whereClause += fmt.Sprintf(" AND e.author_amendament_code = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.AuthorCodes))
+		argIndex++
+	}
+
+	if !filter.StartDate.IsZero() {
+		whereClause += fmt.Sprintf(" AND e.year_and_month >= $%d", argIndex)
+		args = append(args, filter.StartDate.Format("2006/01"))
+		argIndex++
+	}
+
+	if !filter.EndDate.IsZero() {
+		whereClause += fmt.Sprintf(" AND e.year_and_month <= $%d", argIndex)
+		args = append(args, filter.EndDate.Format("2006/01"))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			e.author_amendament_code AS author_code,
+			COALESCE(aa.author_name, MAX(e.author_amendament_name), '') AS author_name,
+			e.action_code,
+			MAX(e.action_name) AS action_name,
+			COALESCE(e.federative_unit, '') AS federative_unit,
+			COALESCE(e.municipality, '') AS municipality,
+			SUM(e.committed_value_brl) AS committed_value_brl,
+			SUM(e.liquidated_value_brl) AS liquidated_value_brl,
+			SUM(e.paid_value_brl) AS paid_value_brl
+		FROM expenses_execution e
+		LEFT JOIN amendment_authors aa ON aa.author_code = e.author_amendament_code
+		%s
+		GROUP BY e.author_amendament_code, aa.author_name, e.action_code, e.federative_unit, e.municipality
+		ORDER BY committed_value_brl DESC;
+	`, whereClause)
+
+	rows := make([]service.AmendmentExecutionRow, 0)
+	if err := as.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to query amendment execution: %w", err)
+	}
+	return rows, nil
+}
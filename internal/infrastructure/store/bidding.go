@@ -0,0 +1,163 @@
+package store
+
+import (
+	"context"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+type BiddingStore struct {
+	db GenericQueryer
+}
+
+func (bs *BiddingStore) InsertBidding(ctx context.Context, bidding *model.Bidding) error {
+	query := `INSERT INTO biddings (
+		bidding_number,
+		management_unit_code,
+		management_unit_name,
+		modality_code,
+		modality,
+		process,
+		object,
+		situation,
+		superior_organ_code,
+		superior_organ_name,
+		organ_code,
+		organ_name,
+		federative_unit,
+		municipality,
+		result_date,
+		opening_date,
+		value,
+		reference_month,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:bidding_number,
+		:management_unit_code,
+		:management_unit_name,
+		:modality_code,
+		:modality,
+		:process,
+		:object,
+		:situation,
+		:superior_organ_code,
+		:superior_organ_name,
+		:organ_code,
+		:organ_name,
+		:federative_unit,
+		:municipality,
+		:result_date,
+		:opening_date,
+		:value,
+		:reference_month,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (bidding_number, management_unit_code, modality_code) DO UPDATE SET
+		management_unit_name = EXCLUDED.management_unit_name,
+		modality = EXCLUDED.modality,
+		process = EXCLUDED.process,
+		object = EXCLUDED.object,
+		situation = EXCLUDED.situation,
+		superior_organ_code = EXCLUDED.superior_organ_code,
+		superior_organ_name = EXCLUDED.superior_organ_name,
+		organ_code = EXCLUDED.organ_code,
+		organ_name = EXCLUDED.organ_name,
+		federative_unit = EXCLUDED.federative_unit,
+		municipality = EXCLUDED.municipality,
+		result_date = EXCLUDED.result_date,
+		opening_date = EXCLUDED.opening_date,
+		value = EXCLUDED.value,
+		reference_month = EXCLUDED.reference_month,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := bs.db.NamedExec(query, bidding)
+	return err
+}
+
+func (bs *BiddingStore) InsertBiddingItem(ctx context.Context, item *model.BiddingItem) error {
+	query := `INSERT INTO bidding_items (
+		bidding_number,
+		management_unit_code,
+		modality_code,
+		item_code,
+		description,
+		quantity,
+		value,
+		winner_code,
+		winner_name,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:bidding_number,
+		:management_unit_code,
+		:modality_code,
+		:item_code,
+		:description,
+		:quantity,
+		:value,
+		:winner_code,
+		:winner_name,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (bidding_number, management_unit_code, modality_code, item_code) DO UPDATE SET
+		description = EXCLUDED.description,
+		quantity = EXCLUDED.quantity,
+		value = EXCLUDED.value,
+		winner_code = EXCLUDED.winner_code,
+		winner_name = EXCLUDED.winner_name,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := bs.db.NamedExec(query, item)
+	return err
+}
+
+func (bs *BiddingStore) InsertBiddingParticipant(ctx context.Context, participant *model.BiddingParticipant) error {
+	query := `INSERT INTO bidding_participants (
+		bidding_number,
+		management_unit_code,
+		modality_code,
+		item_code,
+		participant_code,
+		participant_name,
+		winner,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:bidding_number,
+		:management_unit_code,
+		:modality_code,
+		:item_code,
+		:participant_code,
+		:participant_name,
+		:winner,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (bidding_number, management_unit_code, modality_code, item_code, participant_code) DO UPDATE SET
+		participant_name = EXCLUDED.participant_name,
+		winner = EXCLUDED.winner,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := bs.db.NamedExec(query, participant)
+	return err
+}
+
+func (bs *BiddingStore) DeleteBiddingChildren(ctx context.Context, bidding *model.Bidding) error {
+	args := []interface{}{bidding.BiddingNumber, bidding.ManagementUnitCode, bidding.ModalityCode}
+
+	if _, err := bs.db.ExecContext(ctx, `DELETE FROM bidding_participants WHERE bidding_number = $1 AND management_unit_code = $2 AND modality_code = $3`, args...); err != nil {
+		return err
+	}
+
+	if _, err := bs.db.ExecContext(ctx, `DELETE FROM bidding_items WHERE bidding_number = $1 AND management_unit_code = $2 AND modality_code = $3`, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
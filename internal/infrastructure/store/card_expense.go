@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+type CardExpenseStore struct {
+	db GenericQueryer
+}
+
+func (cs *CardExpenseStore) InsertCardExpense(ctx context.Context, expense *model.CardExpense) error {
+	query := `INSERT INTO card_expenses (
+		superior_organ_code,
+		superior_organ_name,
+		organ_code,
+		organ_name,
+		management_unit_code,
+		management_unit_name,
+		management_code,
+		reference_month,
+		cardholder_cpf,
+		cardholder_name,
+		favored_code,
+		favored_name,
+		transaction_type,
+		transaction_date,
+		value,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:superior_organ_code,
+		:superior_organ_name,
+		:organ_code,
+		:organ_name,
+		:management_unit_code,
+		:management_unit_name,
+		:management_code,
+		:reference_month,
+		:cardholder_cpf,
+		:cardholder_name,
+		:favored_code,
+		:favored_name,
+		:transaction_type,
+		:transaction_date,
+		:value,
+		:inserted_at,
+		:updated_at
+	)`
+
+	_, err := cs.db.NamedExec(query, expense)
+	return err
+}
+
+// ManagementCode resolves the management (gestão) of a unit, which the CPGF
+// file does not carry, from the expenses already loaded for it. Units without
+// loaded expenses fall back to organCode, the management code of autarquias
+// and foundations.
+func (cs *CardExpenseStore) ManagementCode(ctx context.Context, managementUnitCode, organCode int32) (int32, error) {
+	query := `
+		SELECT COALESCE(
+			(SELECT management_code FROM expenses_execution WHERE management_unit_code = $1 ORDER BY id DESC LIMIT 1),
+			(SELECT management_code FROM payments WHERE management_unit_code = $1 AND management_code IS NOT NULL ORDER BY id DESC LIMIT 1),
+			$2
+		)`
+	var code int32
+	if err := cs.db.GetContext(ctx, &code, query, managementUnitCode, organCode); err != nil {
+		return 0, fmt.Errorf("failed to resolve management code of unit %d: %w", managementUnitCode, err)
+	}
+	return code, nil
+}
+
+// DeleteCardExpenses clears a unit's month before it is reloaded, which keeps
+// the ingestion idempotent without a natural key.
+func (cs *CardExpenseStore) DeleteCardExpenses(ctx context.Context, managementUnitCode int32, referenceMonth string) error {
+	_, err := cs.db.ExecContext(ctx, `DELETE FROM card_expenses WHERE management_unit_code = $1 AND reference_month = $2`, managementUnitCode, referenceMonth)
+	return err
+}
@@ -41,6 +41,7 @@ func (cs *CommitmentStore) InsertCommitment(ctx context.Context, commitment *mod
 		budget_plan,
 		budget_plan_code,
 		observation,
+		bidding_modality,
 		commitment_original_value,
 		commitment_value_converted_to_brl,
 		conversion_value_used,
@@ -72,6 +73,7 @@ func (cs *CommitmentStore) InsertCommitment(ctx context.Context, commitment *mod
 		:budget_plan,
 		:budget_plan_code,
 		:observation,
+		:bidding_modality,
 		:commitment_original_value,
 		:commitment_value_converted_to_brl,
 		:conversion_value_used,
@@ -102,6 +104,7 @@ func (cs *CommitmentStore) InsertCommitment(ctx context.Context, commitment *mod
 		budget_plan = EXCLUDED.budget_plan,
 		budget_plan_code = EXCLUDED.budget_plan_code,
 		observation = EXCLUDED.observation,
+		bidding_modality = EXCLUDED.bidding_modality,
 		commitment_original_value = EXCLUDED.commitment_original_value,
 		commitment_value_converted_to_brl = EXCLUDED.commitment_value_converted_to_brl,
 		conversion_value_used = EXCLUDED.conversion_value_used,
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/lib/pq"
+)
+
+type ContractStore struct {
+	db GenericQueryer
+}
+
+func (cs *ContractStore) InsertContract(ctx context.Context, contract *model.Contract) error {
+	query := `INSERT INTO contracts (
+		contract_number,
+		object,
+		legal_basis,
+		purchase_modality,
+		situation,
+		superior_organ_code,
+		superior_organ_name,
+		organ_code,
+		organ_name,
+		management_unit_code,
+		management_unit_name,
+		process,
+		signature_date,
+		publication_date,
+		start_date,
+		end_date,
+		contractor_code,
+		contractor_name,
+		initial_value,
+		final_value,
+		bidding_number,
+		reference_month,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:contract_number,
+		:object,
+		:legal_basis,
+		:purchase_modality,
+		:situation,
+		:superior_organ_code,
+		:superior_organ_name,
+		:organ_code,
+		:organ_name,
+		:management_unit_code,
+		:management_unit_name,
+		:process,
+		:signature_date,
+		:publication_date,
+		:start_date,
+		:end_date,
+		:contractor_code,
+		:contractor_name,
+		:initial_value,
+		:final_value,
+		:bidding_number,
+		:reference_month,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (contract_number, management_unit_code) DO UPDATE SET
+		object = EXCLUDED.object,
+		legal_basis = EXCLUDED.legal_basis,
+		purchase_modality = EXCLUDED.purchase_modality,
+		situation = EXCLUDED.situation,
+		superior_organ_code = EXCLUDED.superior_organ_code,
+		superior_organ_name = EXCLUDED.superior_organ_name,
+		organ_code = EXCLUDED.organ_code,
+		organ_name = EXCLUDED.organ_name,
+		management_unit_name = EXCLUDED.management_unit_name,
+		process = EXCLUDED.process,
+		signature_date = EXCLUDED.signature_date,
+		publication_date = EXCLUDED.publication_date,
+		start_date = EXCLUDED.start_date,
+		end_date = EXCLUDED.end_date,
+		contractor_code = EXCLUDED.contractor_code,
+		contractor_name = EXCLUDED.contractor_name,
+		initial_value = EXCLUDED.initial_value,
+		final_value = EXCLUDED.final_value,
+		bidding_number = EXCLUDED.bidding_number,
+		reference_month = EXCLUDED.reference_month,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := cs.db.NamedExec(query, contract)
+	return err
+}
+
+func (cs *ContractStore) InsertContractItem(ctx context.Context, item *model.ContractItem) error {
+	query := `INSERT INTO contract_items (
+		contract_number,
+		management_unit_code,
+		item_code,
+		description,
+		complement_description,
+		quantity,
+		value,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:contract_number,
+		:management_unit_code,
+		:item_code,
+		:description,
+		:complement_description,
+		:quantity,
+		:value,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (contract_number, management_unit_code, item_code) DO UPDATE SET
+		description = EXCLUDED.description,
+		complement_description = EXCLUDED.complement_description,
+		quantity = EXCLUDED.quantity,
+		value = EXCLUDED.value,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := cs.db.NamedExec(query, item)
+	return err
+}
+
+func (cs *ContractStore) DeleteContractItems(ctx context.Context, contractNumber string, managementUnitCode int32) error {
+	_, err := cs.db.ExecContext(ctx, `DELETE FROM contract_items WHERE contract_number = $1 AND management_unit_code = $2`, contractNumber, managementUnitCode)
+	return err
+}
+
+func (cs *ContractStore) GetContracts(ctx context.Context, filter service.GetContractsFilter) ([]service.ContractInformation, error) {
+	whereClause := "WHERE ct.signature_date BETWEEN $1 AND $2"
+	args := []interface{}{filter.StartDate, filter.EndDate}
+	argIndex := 3
+
+	if len(filter.ManagementUnitCodes) > 0 {
+		whereClause += fmt.Sprintf(" AND ct.management_unit_code::text = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.ManagementUnitCodes))
+		argIndex++
+	}
+
+	if len(filter.ContractNumbers) > 0 {
+		whereClause += fmt.Sprintf(" AND ct.contract_number = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.ContractNumbers))
+	}
+
+	q := fmt.Sprintf(`
+	SELECT
+		ct.contract_number,
+		ct.management_unit_code,
+		ct.management_unit_name,
+		ct.object,
+		ct.situation,
+		ct.process,
+		ct.signature_date,
+		ct.start_date,
+		ct.end_date,
+		ct.contractor_code,
+		ct.contractor_name,
+		ct.initial_value,
+		ct.final_value,
+		COALESCE(ARRAY_AGG(cc.commitment_code) FILTER (WHERE cc.commitment_code IS NOT NULL), '{}') AS commitment_codes
+	FROM
+		contracts ct
+	LEFT JOIN
+		contract_commitments cc ON cc.contract_number = ct.contract_number AND cc.management_unit_code = ct.management_unit_code
+	%s
+	GROUP BY
+		ct.contract_number, ct.management_unit_code, ct.management_unit_name, ct.object, ct.situation, ct.process,
+		ct.signature_date, ct.start_date, ct.end_date, ct.contractor_code, ct.contractor_name, ct.initial_value, ct.final_value
+	ORDER BY ct.signature_date DESC, ct.contract_number;
+	`, whereClause)
+
+	contracts := make([]service.ContractInformation, 0)
+	if err := cs.db.SelectContext(ctx, &contracts, q, args...); err != nil {
+		return nil, fmt.Errorf("failed to get contracts: %w", err)
+	}
+	return contracts, nil
+}
@@ -284,12 +284,14 @@ func (es *ExpensesStore) GetBudgetExecutionSummaryByManagement(ctx context.Conte
 
 func (es *ExpensesStore) GetTopFavored(ctx context.Context, e service.ExpensesFilter, limit int) ([]service.TopFavored, error) {
 	whereClause := "WHERE p.management_code = $1"
+	cardWhereClause := "WHERE ce.management_code = $1"
 	args := []interface{}{e.ManagementCode}
 	argIndex := 2
 
 	// Optional management unit codes filter
 	if len(e.ManagementUnitCodes) > 0 {
 		whereClause += fmt.Sprintf(" AND p.management_unit_code = ANY($%d)", argIndex)
+		cardWhereClause += fmt.Sprintf(" AND ce.management_unit_code = ANY($%d)", argIndex)
 		args = append(args, pq.Array(e.ManagementUnitCodes))
 		argIndex++
 	}
@@ -297,27 +299,60 @@ func (es *ExpensesStore) GetTopFavored(ctx context.Context, e service.ExpensesFi
 	// Optional date range filter
 	if !e.StartDate.IsZero() && !e.EndDate.IsZero() {
 		whereClause += fmt.Sprintf(" AND p.payment_emission_date BETWEEN $%d AND $%d", argIndex, argIndex+1)
+		cardWhereClause += fmt.Sprintf(" AND ce.transaction_date BETWEEN $%d AND $%d", argIndex, argIndex+1)
 		args = append(args, e.StartDate, e.EndDate)
 		argIndex += 2
 	}
 
 	query := fmt.Sprintf(`
-	SELECT 
-		p.favored_code,
-		p.favored_name,
-		SUM(pic.paid_value_brl) AS total_paid_value,
-		COUNT(p.id) AS payments_count
-	FROM 
-		payments p
-	JOIN 
-		payment_impacted_commitments pic ON p.payment_code = pic.payment_code
-	%s
-	GROUP BY 
-		p.favored_code, p.favored_name
+	WITH favored AS (
+		SELECT 
+			p.favored_code,
+			p.favored_name,
+			SUM(pic.paid_value_brl) AS payments_paid_value,
+			COUNT(p.id) AS payments_count,
+			0 AS card_paid_value,
+			0 AS card_transactions_count,
+			BOOL_OR(is_sanctioned(p.favored_code, p.favored_name, p.payment_emission_date::date)) AS sanctioned
+		FROM 
+			payments p
+		JOIN 
+			payment_impacted_commitments pic ON p.payment_code = pic.payment_code
+		%s
+		GROUP BY 
+			p.favored_code, p.favored_name
+		UNION ALL
+		SELECT
+			ce.favored_code,
+			ce.favored_name,
+			0,
+			0,
+			SUM(ce.value),
+			COUNT(*),
+			BOOL_OR(is_sanctioned(ce.favored_code, ce.favored_name, ce.transaction_date))
+		FROM
+			card_expenses ce
+		%s
+		GROUP BY
+			ce.favored_code, ce.favored_name
+	)
+	SELECT
+		favored_code,
+		MAX(favored_name) AS favored_name,
+		SUM(payments_paid_value) + SUM(card_paid_value) AS total_paid_value,
+		SUM(payments_count) AS payments_count,
+		SUM(payments_paid_value) AS payments_paid_value,
+		SUM(card_paid_value) AS card_paid_value,
+		SUM(card_transactions_count) AS card_transactions_count,
+		COALESCE(BOOL_OR(sanctioned), false) AS sanctioned
+	FROM
+		favored
+	GROUP BY
+		favored_code
 	ORDER BY 
 		total_paid_value DESC
 	LIMIT $%d;
-	`, whereClause, argIndex)
+	`, whereClause, cardWhereClause, argIndex)
 
 	args = append(args, limit)
 
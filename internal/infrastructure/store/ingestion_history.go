@@ -19,34 +19,68 @@ var (
 	ScopeTypeManagement   = "MANAGEMENT"
 )
 
+var (
+	DatasetExpenses          = "expenses"
+	DatasetExpensesExecution = "expenses_execution"
+	DatasetContracts         = "contracts"
+	DatasetBiddings          = "licitacoes"
+	DatasetAgreements        = "convenios"
+	DatasetAmendments        = "emendas"
+	DatasetCardExpenses      = "cpgf"
+	DatasetSanctions         = "sancoes"
+	DatasetRevenue           = "receitas"
+	DatasetTravel            = "viagens"
+)
+
+// Datasets are the kinds of data the ETL ingests, one per pipeline.
+var Datasets = []string{
+	DatasetExpenses,
+	DatasetExpensesExecution,
+	DatasetContracts,
+	DatasetBiddings,
+	DatasetAgreements,
+	DatasetAmendments,
+	DatasetCardExpenses,
+	DatasetSanctions,
+	DatasetRevenue,
+	DatasetTravel,
+}
+
 var (
 	TriggerTypeManual    = "MANUAL"
 	TriggerTypeScheduled = "SCHEDULED"
 )
 
 var (
-	StatusSuccess    = "SUCCESS"
-	StatusFailure    = "FAILURE"
-	StatusPartial    = "PARTIAL"
-	StatusInProgress = "IN_PROGRESS"
-	StatusSkipped    = "SKIPPED"
+	StatusSuccess     = "SUCCESS"
+	StatusFailure     = "FAILURE"
+	StatusPartial     = "PARTIAL"
+	StatusInProgress  = "IN_PROGRESS"
+	StatusSkipped     = "SKIPPED"
+	StatusRescheduled = "RESCHEDULED"
 )
 
+const ingestionHistoryColumns = `id, processed_at, dataset, reference_date, source_file, trigger_type, scope_type, status, processed_codes, source_fingerprints, schema_drift, code_outcomes, job_id, last_error`
+
 func (ih *IngestionHistoryStore) InsertIngestionHistory(ctx context.Context, history *model.IngestionHistory) error {
 	query := `INSERT INTO ingestion_history (
+		dataset,
 		reference_date,
 		source_file,
 		trigger_type,
 		scope_type,
 		status,
-		processed_codes
+		processed_codes,
+		job_id
 	) VALUES (
+		:dataset,
 		:reference_date,
 		:source_file,
 		:trigger_type,
 		:scope_type,
 		:status,
-		:processed_codes
+		:processed_codes,
+		:job_id
 	) RETURNING id, processed_at`
 
 	// Use NamedQuery to get the RETURNING values if needed,
@@ -71,7 +105,7 @@ func (ih *IngestionHistoryStore) InsertIngestionHistory(ctx context.Context, his
 
 func (ih *IngestionHistoryStore) GetLatest(ctx context.Context, limit int) ([]model.IngestionHistory, error) {
 	query := `
-		SELECT id, processed_at, reference_date, source_file, trigger_type, scope_type, status, processed_codes
+		SELECT ` + ingestionHistoryColumns + `
 		FROM ingestion_history
 		ORDER BY processed_at DESC
 		LIMIT $1
@@ -84,25 +118,58 @@ func (ih *IngestionHistoryStore) GetLatest(ctx context.Context, limit int) ([]mo
 	return history, nil
 }
 
-func (ih *IngestionHistoryStore) UpdateIngestionStatus(ctx context.Context, id int64, status string) error {
-	query := `UPDATE ingestion_history SET status = $1 WHERE id = $2`
-	_, err := ih.db.ExecContext(ctx, query, status, id)
+// UpdateIngestionStatus sets the final status of an ingestion and, for a
+// FAILURE, why it failed.
+func (ih *IngestionHistoryStore) UpdateIngestionStatus(ctx context.Context, id int64, status, lastError string) error {
+	query := `UPDATE ingestion_history SET status = $1, last_error = $2 WHERE id = $3`
+	_, err := ih.db.ExecContext(ctx, query, status, lastError, id)
 	if err != nil {
 		return fmt.Errorf("failed to update ingestion status: %w", err)
 	}
 	return nil
 }
 
-func (ih *IngestionHistoryStore) GetHistoryInRange(ctx context.Context, startDate, endDate time.Time, codes []int64) ([]model.IngestionHistory, error) {
+// UpdateIngestionSources stores the fingerprints of the files a job downloaded.
+func (ih *IngestionHistoryStore) UpdateIngestionSources(ctx context.Context, id int64, sources model.SourceFingerprints) error {
+	query := `UPDATE ingestion_history SET source_fingerprints = $1 WHERE id = $2`
+	_, err := ih.db.ExecContext(ctx, query, sources, id)
+	if err != nil {
+		return fmt.Errorf("failed to update ingestion sources: %w", err)
+	}
+	return nil
+}
+
+// UpdateIngestionSchemaDrift stores the header differences found in the job's files.
+func (ih *IngestionHistoryStore) UpdateIngestionSchemaDrift(ctx context.Context, id int64, drift model.SchemaDrifts) error {
+	query := `UPDATE ingestion_history SET schema_drift = $1 WHERE id = $2`
+	_, err := ih.db.ExecContext(ctx, query, drift, id)
+	if err != nil {
+		return fmt.Errorf("failed to update ingestion schema drift: %w", err)
+	}
+	return nil
+}
+
+// UpdateIngestionCodeOutcomes stores the outcome of each code the job covered.
+func (ih *IngestionHistoryStore) UpdateIngestionCodeOutcomes(ctx context.Context, id int64, outcomes model.CodeOutcomes) error {
+	query := `UPDATE ingestion_history SET code_outcomes = $1 WHERE id = $2`
+	_, err := ih.db.ExecContext(ctx, query, outcomes, id)
+	if err != nil {
+		return fmt.Errorf("failed to update ingestion code outcomes: %w", err)
+	}
+	return nil
+}
+
+func (ih *IngestionHistoryStore) GetHistoryInRange(ctx context.Context, dataset string, startDate, endDate time.Time, codes []int64) ([]model.IngestionHistory, error) {
 	query := `
-		SELECT id, processed_at, reference_date, source_file, trigger_type, scope_type, status, processed_codes
+		SELECT ` + ingestionHistoryColumns + `
 		FROM ingestion_history
-		WHERE reference_date BETWEEN $1 AND $2
-		AND processed_codes && $3
+		WHERE dataset = $1
+		AND reference_date BETWEEN $2 AND $3
+		AND processed_codes && $4
 		ORDER BY reference_date ASC, processed_at DESC
 	`
 	var history []model.IngestionHistory
-	err := ih.db.SelectContext(ctx, &history, query, startDate, endDate, pq.Array(codes))
+	err := ih.db.SelectContext(ctx, &history, query, dataset, startDate, endDate, pq.Array(codes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ingestion history in range: %w", err)
 	}
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/repository"
+)
+
+type IngestionJobStore struct {
+	db GenericQueryer
+}
+
+const ingestionJobColumns = `id, kind, start_date, end_date, codes, scope_type, trigger_type, status, attempts, last_error, created_at, started_at, finished_at`
+
+// EnqueueJob queues job and fills in its ID, status and creation time.
+func (js *IngestionJobStore) EnqueueJob(ctx context.Context, job *model.IngestionJob) error {
+	query := `INSERT INTO ingestion_jobs (
+		kind,
+		start_date,
+		end_date,
+		codes,
+		scope_type,
+		trigger_type
+	) VALUES (
+		:kind,
+		:start_date,
+		:end_date,
+		:codes,
+		:scope_type,
+		:trigger_type
+	) RETURNING id, status, created_at`
+
+	rows, err := js.db.NamedQuery(query, job)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue ingestion job: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&job.ID, &job.Status, &job.CreatedAt); err != nil {
+			return fmt.Errorf("failed to enqueue ingestion job: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+// GetJob returns the job with the history records of its periods. It returns
+// sql.ErrNoRows when there is no such job.
+func (js *IngestionJobStore) GetJob(ctx context.Context, id int64) (*model.IngestionJob, error) {
+	var job model.IngestionJob
+	query := `SELECT ` + ingestionJobColumns + ` FROM ingestion_jobs WHERE id = $1`
+	if err := js.db.GetContext(ctx, &job, query, id); err != nil {
+		return nil, err
+	}
+
+	job.Ingestions = []model.IngestionHistory{}
+	query = `
+		SELECT ` + ingestionHistoryColumns + `
+		FROM ingestion_history
+		WHERE job_id = $1
+		ORDER BY reference_date, processed_at
+	`
+	if err := js.db.SelectContext(ctx, &job.Ingestions, query, id); err != nil {
+		return nil, fmt.Errorf("failed to get ingestion job history: %w", err)
+	}
+	return &job, nil
+}
+
+// ClaimJob first fails the running jobs whose worker stopped extending them
+// after their last attempt, then marks the oldest visible job as running for
+// visibility and returns it, or nil when there is none. A running job is
+// visible again once its worker stopped extending it. SKIP LOCKED keeps two
+// workers from claiming the same job.
+func (js *IngestionJobStore) ClaimJob(ctx context.Context, visibility time.Duration, maxAttempts int) (*model.IngestionJob, error) {
+	expire := `
+		UPDATE ingestion_jobs
+		SET status = 'FAILURE', finished_at = NOW(),
+			last_error = 'visibility timeout expired on attempt ' || attempts
+		WHERE status = 'RUNNING' AND visible_at <= NOW() AND attempts >= $1`
+	if _, err := js.db.ExecContext(ctx, expire, maxAttempts); err != nil {
+		return nil, fmt.Errorf("failed to expire ingestion jobs: %w", err)
+	}
+
+	claim := `
+		UPDATE ingestion_jobs
+		SET status = 'RUNNING', attempts = attempts + 1, started_at = NOW(),
+			visible_at = NOW() + $1 * INTERVAL '1 millisecond'
+		WHERE id = (
+			SELECT id FROM ingestion_jobs
+			WHERE status IN ('QUEUED', 'RUNNING') AND visible_at <= NOW()
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + ingestionJobColumns
+
+	var job model.IngestionJob
+	err := js.db.GetContext(ctx, &job, claim, visibility.Milliseconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim ingestion job: %w", err)
+	}
+	return &job, nil
+}
+
+// ExtendJob keeps a running job hidden for another visibility period, as long
+// as it was not claimed again since attempt.
+func (js *IngestionJobStore) ExtendJob(ctx context.Context, id int64, attempt int, visibility time.Duration) error {
+	query := `
+		UPDATE ingestion_jobs
+		SET visible_at = NOW() + $3 * INTERVAL '1 millisecond'
+		WHERE id = $1 AND attempts = $2 AND status = 'RUNNING'`
+	if _, err := js.db.ExecContext(ctx, query, id, attempt, visibility.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to extend ingestion job: %w", err)
+	}
+	return nil
+}
+
+// ReleaseJob puts back a job its worker was stopped before finishing, visible
+// right away for the next worker. The claim that started it counted an
+// attempt, which is given back. It returns repository.ErrJobLost when the job was
+// claimed again since attempt.
+func (js *IngestionJobStore) ReleaseJob(ctx context.Context, id int64, attempt int, lastError string) error {
+	query := `
+		UPDATE ingestion_jobs
+		SET status = 'QUEUED', last_error = $3, attempts = GREATEST(attempts - 1, 0), visible_at = NOW()
+		WHERE id = $1 AND attempts = $2 AND status = 'RUNNING'`
+	result, err := js.db.ExecContext(ctx, query, id, attempt, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to release ingestion job: %w", err)
+	}
+	return stillHeld(result)
+}
+
+// FinishJob records the final status of a job and the error that ended it, if
+// any. It returns repository.ErrJobLost when the job was claimed again since attempt.
+func (js *IngestionJobStore) FinishJob(ctx context.Context, id int64, attempt int, status, lastError string) error {
+	query := `
+		UPDATE ingestion_jobs
+		SET status = $3, last_error = $4, finished_at = NOW()
+		WHERE id = $1 AND attempts = $2 AND status = 'RUNNING'`
+	result, err := js.db.ExecContext(ctx, query, id, attempt, status, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to finish ingestion job: %w", err)
+	}
+	return stillHeld(result)
+}
+
+// stillHeld reports repository.ErrJobLost when an update guarded on the claim
+// of a job matched no row.
+func stillHeld(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read updated jobs: %w", err)
+	}
+	if n == 0 {
+		return repository.ErrJobLost
+	}
+	return nil
+}
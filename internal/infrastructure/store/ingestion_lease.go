@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type IngestionLeaseStore struct {
+	db GenericQueryer
+}
+
+// AcquireLease inserts the lease, or takes over one that expired. The
+// conditional upsert is a single statement, so two runs racing for the same
+// key cannot both win.
+func (ls *IngestionLeaseStore) AcquireLease(ctx context.Context, dataset, key, holder string, ttl time.Duration) (bool, error) {
+	query := `
+		INSERT INTO ingestion_leases (dataset, job_key, holder, acquired_at, expires_at)
+		VALUES ($1, $2, $3, NOW(), NOW() + $4 * INTERVAL '1 millisecond')
+		ON CONFLICT (dataset, job_key) DO UPDATE
+		SET holder = EXCLUDED.holder, acquired_at = EXCLUDED.acquired_at, expires_at = EXCLUDED.expires_at
+		WHERE ingestion_leases.expires_at <= NOW()
+		RETURNING holder`
+
+	var got string
+	err := ls.db.GetContext(ctx, &got, query, dataset, key, holder, ttl.Milliseconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire ingestion lease: %w", err)
+	}
+	return true, nil
+}
+
+// RenewLease pushes the expiry of a lease holder still owns ttl into the future.
+func (ls *IngestionLeaseStore) RenewLease(ctx context.Context, dataset, key, holder string, ttl time.Duration) error {
+	query := `
+		UPDATE ingestion_leases
+		SET expires_at = NOW() + $4 * INTERVAL '1 millisecond'
+		WHERE dataset = $1 AND job_key = $2 AND holder = $3`
+	if _, err := ls.db.ExecContext(ctx, query, dataset, key, holder, ttl.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to renew ingestion lease: %w", err)
+	}
+	return nil
+}
+
+// ReleaseLease drops the lease if holder still owns it.
+func (ls *IngestionLeaseStore) ReleaseLease(ctx context.Context, dataset, key, holder string) error {
+	query := `DELETE FROM ingestion_leases WHERE dataset = $1 AND job_key = $2 AND holder = $3`
+	if _, err := ls.db.ExecContext(ctx, query, dataset, key, holder); err != nil {
+		return fmt.Errorf("failed to release ingestion lease: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+type IngestionRejectionStore struct {
+	db GenericQueryer
+}
+
+// rejectionBatchSize keeps each insert well below the Postgres parameter limit.
+const rejectionBatchSize = 1000
+
+// InsertRejections stores the rows an ingestion left out.
+func (rs *IngestionRejectionStore) InsertRejections(ctx context.Context, ingestionID int64, rejections []model.RowRejection) error {
+	query := `INSERT INTO ingestion_rejections (
+		ingestion_id,
+		data_type,
+		source_file,
+		line,
+		column_name,
+		raw_value,
+		reason
+	) VALUES (
+		:ingestion_id,
+		:data_type,
+		:source_file,
+		:line,
+		:column_name,
+		:raw_value,
+		:reason
+	)`
+
+	for start := 0; start < len(rejections); start += rejectionBatchSize {
+		batch := make([]model.RowRejection, 0, min(rejectionBatchSize, len(rejections)-start))
+		for _, r := range rejections[start:min(start+rejectionBatchSize, len(rejections))] {
+			r.IngestionID = ingestionID
+			batch = append(batch, r)
+		}
+		if _, err := rs.db.NamedExec(query, batch); err != nil {
+			return fmt.Errorf("failed to insert ingestion rejections: %w", err)
+		}
+	}
+	return nil
+}
+
+func (rs *IngestionRejectionStore) GetRejections(ctx context.Context, ingestionID int64, limit, offset int) ([]model.RowRejection, error) {
+	query := `
+		SELECT id, ingestion_id, data_type, source_file, line, column_name, COALESCE(raw_value, '') AS raw_value, reason, rejected_at
+		FROM ingestion_rejections
+		WHERE ingestion_id = $1
+		ORDER BY source_file, line, id
+		LIMIT $2 OFFSET $3
+	`
+	rejections := []model.RowRejection{}
+	err := rs.db.SelectContext(ctx, &rejections, query, ingestionID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingestion rejections: %w", err)
+	}
+	return rejections, nil
+}
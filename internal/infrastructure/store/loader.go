@@ -2,8 +2,10 @@ package store
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
 	"github.com/farxc/envelopa-transparencia/internal/domain/service"
 	"github.com/farxc/envelopa-transparencia/internal/infrastructure/logger"
 )
@@ -24,6 +26,9 @@ func (s *storageLoader) LoadExpenses(ctx context.Context, payload *service.Expen
 	const component = "Loader"
 	s.logger.Info(component, "Starting data load for extraction date: %s", payload.ExtractionDate)
 
+	// Each unit is committed on its own, so a failing unit does not undo or
+	// stop the others; the failures are returned together as a UnitLoadError.
+	failed := make(map[string]error)
 	for _, unit := range payload.UnitsExpenses {
 		err := func() error {
 			tx, err := s.storage.DB.BeginTxx(ctx, nil)
@@ -120,6 +125,54 @@ func (s *storageLoader) LoadExpenses(ctx context.Context, payload *service.Expen
 						s.logger.Error(component, "Failed to reconcile payment impacts for %s: %v", payment.PaymentCode, err)
 						return err
 					}
+					if err := store.DeleteFinalBeneficiaries(ctx, payment.PaymentCode); err != nil {
+						s.logger.Error(component, "Failed to reconcile payment final beneficiaries for %s: %v", payment.PaymentCode, err)
+						return err
+					}
+					if err := store.DeletePaymentAnnexes(ctx, payment.PaymentCode); err != nil {
+						s.logger.Error(component, "Failed to reconcile payment annexes for %s: %v", payment.PaymentCode, err)
+						return err
+					}
+				}
+
+				for _, fb := range payment.FinalBeneficiaries {
+					fb.InsertedAt = time.Now()
+					fb.UpdatedAt = time.Now()
+
+					if err := txStorage.Payment.InsertPaymentFinalBeneficiary(ctx, &fb); err != nil {
+						s.logger.Error(component, "Failed to insert payment final beneficiary %s for %s: %v", fb.FinalBeneficiaryCode, payment.PaymentCode, err)
+						return err
+					}
+				}
+
+				for _, bt := range payment.BankTransfers {
+					bt.InsertedAt = time.Now()
+					bt.UpdatedAt = time.Now()
+
+					if err := txStorage.Payment.InsertPaymentBankTransfer(ctx, &bt); err != nil {
+						s.logger.Error(component, "Failed to insert payment bank transfer %s for %s: %v", bt.BankCode, payment.PaymentCode, err)
+						return err
+					}
+				}
+
+				for _, inv := range payment.Invoices {
+					inv.InsertedAt = time.Now()
+					inv.UpdatedAt = time.Now()
+
+					if err := txStorage.Payment.InsertPaymentInvoice(ctx, &inv); err != nil {
+						s.logger.Error(component, "Failed to insert payment invoice %s for %s: %v", inv.InvoiceNumber, payment.PaymentCode, err)
+						return err
+					}
+				}
+
+				for _, co := range payment.CourtOrders {
+					co.InsertedAt = time.Now()
+					co.UpdatedAt = time.Now()
+
+					if err := txStorage.Payment.InsertPaymentCourtOrder(ctx, &co); err != nil {
+						s.logger.Error(component, "Failed to insert payment court order %s for %s: %v", co.ProcessNumber, payment.PaymentCode, err)
+						return err
+					}
 				}
 			}
 
@@ -135,9 +188,12 @@ func (s *storageLoader) LoadExpenses(ctx context.Context, payload *service.Expen
 			return tx.Commit()
 		}()
 		if err != nil {
-			return err
+			s.logger.Error(component, "Failed to load unit: date=%s ug=%s err=%v", payload.ExtractionDate, unit.UgCode, err)
+			failed[unit.UgCode] = err
 		}
-
+	}
+	if len(failed) > 0 {
+		return &service.UnitLoadError{Units: failed}
 	}
 	s.logger.Info(component, "Data load completed for extraction date: %s", payload.ExtractionDate)
 	return nil
@@ -157,8 +213,423 @@ func (s *storageLoader) LoadExpensesExecution(ctx context.Context, payload *serv
 			s.logger.Error(component, "Failed to insert expense execution for unit %d (%s): %v", unit.UgCode, unit.UgName, err)
 			return err
 		}
+
+		if execution.AuthorAmendamentCode != 0 {
+			author := model.AmendmentAuthor{
+				AuthorCode: execution.AuthorAmendamentCode,
+				AuthorName: execution.AuthorAmendamentName,
+				InsertedAt: now,
+				UpdatedAt:  now,
+			}
+			if err := s.storage.Amendment.UpsertAuthor(ctx, &author); err != nil {
+				s.logger.Error(component, "Failed to upsert amendment author %d: %v", author.AuthorCode, err)
+				return err
+			}
+		}
 	}
 
 	s.logger.Info(component, "Expenses execution load completed for extraction date: %s", payload.ExtractionDate)
 	return nil
 }
+
+func (s *storageLoader) LoadContracts(ctx context.Context, payload *service.ContractsPayload) error {
+	const component = "Loader"
+	s.logger.Info(component, "Starting contracts load for extraction date: %s", payload.ExtractionDate)
+
+	for _, unit := range payload.UnitsContracts {
+		err := func() error {
+			tx, err := s.storage.DB.BeginTxx(ctx, nil)
+			if err != nil {
+				s.logger.Error(component, "Failed to start transaction: %v", err)
+				return err
+			}
+			defer tx.Rollback()
+			txStorage := s.storage.WithTx(tx)
+
+			for _, c := range unit.Contracts {
+				now := time.Now()
+				contract := c
+				contract.InsertedAt = now
+				contract.UpdatedAt = now
+
+				if err := txStorage.Contract.InsertContract(ctx, &contract); err != nil {
+					s.logger.Error(component, "Failed to insert contract %s (UG %d): %v", contract.ContractNumber, contract.ManagementUnitCode, err)
+					return err
+				}
+
+				if store, ok := txStorage.Contract.(*ContractStore); ok {
+					if err := store.DeleteContractItems(ctx, contract.ContractNumber, contract.ManagementUnitCode); err != nil {
+						s.logger.Error(component, "Failed to reconcile contract items for %s: %v", contract.ContractNumber, err)
+						return err
+					}
+				}
+
+				for _, item := range contract.Items {
+					item.InsertedAt = now
+					item.UpdatedAt = now
+
+					if err := txStorage.Contract.InsertContractItem(ctx, &item); err != nil {
+						s.logger.Error(component, "Failed to insert contract item %s for %s: %v", item.ItemCode, contract.ContractNumber, err)
+						return err
+					}
+				}
+			}
+			return tx.Commit()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info(component, "Contracts load completed for extraction date: %s", payload.ExtractionDate)
+	return nil
+}
+
+func (s *storageLoader) LoadBiddings(ctx context.Context, payload *service.BiddingsPayload) error {
+	const component = "Loader"
+	s.logger.Info(component, "Starting biddings load for extraction date: %s", payload.ExtractionDate)
+
+	for _, unit := range payload.UnitsBiddings {
+		err := func() error {
+			tx, err := s.storage.DB.BeginTxx(ctx, nil)
+			if err != nil {
+				s.logger.Error(component, "Failed to start transaction: %v", err)
+				return err
+			}
+			defer tx.Rollback()
+			txStorage := s.storage.WithTx(tx)
+
+			for _, b := range unit.Biddings {
+				now := time.Now()
+				bidding := b
+				bidding.InsertedAt = now
+				bidding.UpdatedAt = now
+
+				if err := txStorage.Bidding.InsertBidding(ctx, &bidding); err != nil {
+					s.logger.Error(component, "Failed to insert bidding %s (UG %d): %v", bidding.BiddingNumber, bidding.ManagementUnitCode, err)
+					return err
+				}
+
+				if store, ok := txStorage.Bidding.(*BiddingStore); ok {
+					if err := store.DeleteBiddingChildren(ctx, &bidding); err != nil {
+						s.logger.Error(component, "Failed to reconcile bidding children for %s: %v", bidding.BiddingNumber, err)
+						return err
+					}
+				}
+
+				for _, item := range bidding.Items {
+					item.InsertedAt = now
+					item.UpdatedAt = now
+
+					if err := txStorage.Bidding.InsertBiddingItem(ctx, &item); err != nil {
+						s.logger.Error(component, "Failed to insert bidding item %s for %s: %v", item.ItemCode, bidding.BiddingNumber, err)
+						return err
+					}
+				}
+
+				for _, participant := range bidding.Participants {
+					participant.InsertedAt = now
+					participant.UpdatedAt = now
+
+					if err := txStorage.Bidding.InsertBiddingParticipant(ctx, &participant); err != nil {
+						s.logger.Error(component, "Failed to insert bidding participant %s for %s: %v", participant.ParticipantCode, bidding.BiddingNumber, err)
+						return err
+					}
+				}
+			}
+			return tx.Commit()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info(component, "Biddings load completed for extraction date: %s", payload.ExtractionDate)
+	return nil
+}
+
+func (s *storageLoader) LoadAgreements(ctx context.Context, payload *service.AgreementsPayload) error {
+	const component = "Loader"
+	s.logger.Info(component, "Starting agreements load for extraction date: %s", payload.ExtractionDate)
+
+	for _, unit := range payload.UnitsAgreements {
+		err := func() error {
+			tx, err := s.storage.DB.BeginTxx(ctx, nil)
+			if err != nil {
+				s.logger.Error(component, "Failed to start transaction: %v", err)
+				return err
+			}
+			defer tx.Rollback()
+			txStorage := s.storage.WithTx(tx)
+
+			for _, a := range unit.Agreements {
+				now := time.Now()
+				agreement := a
+				agreement.InsertedAt = now
+				agreement.UpdatedAt = now
+
+				if err := txStorage.Agreement.InsertAgreement(ctx, &agreement); err != nil {
+					s.logger.Error(component, "Failed to insert agreement %s (UG %d): %v", agreement.AgreementNumber, agreement.GrantorCode, err)
+					return err
+				}
+
+				for _, d := range agreement.Disbursements {
+					d.InsertedAt = now
+					d.UpdatedAt = now
+
+					if err := txStorage.Agreement.InsertAgreementDisbursement(ctx, &d); err != nil {
+						s.logger.Error(component, "Failed to insert disbursement for agreement %s: %v", agreement.AgreementNumber, err)
+						return err
+					}
+				}
+			}
+			return tx.Commit()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info(component, "Agreements load completed for extraction date: %s", payload.ExtractionDate)
+	return nil
+}
+
+func (s *storageLoader) LoadAmendments(ctx context.Context, payload *service.AmendmentsPayload) error {
+	const component = "Loader"
+	s.logger.Info(component, "Starting amendments load for extraction date: %s", payload.ExtractionDate)
+
+	for _, group := range payload.AuthorAmendments {
+		err := func() error {
+			tx, err := s.storage.DB.BeginTxx(ctx, nil)
+			if err != nil {
+				s.logger.Error(component, "Failed to start transaction: %v", err)
+				return err
+			}
+			defer tx.Rollback()
+			txStorage := s.storage.WithTx(tx)
+
+			now := time.Now()
+			author := group.Author
+			author.InsertedAt = now
+			author.UpdatedAt = now
+
+			if err := txStorage.Amendment.UpsertAuthor(ctx, &author); err != nil {
+				s.logger.Error(component, "Failed to upsert amendment author %d: %v", author.AuthorCode, err)
+				return err
+			}
+
+			for _, a := range group.Amendments {
+				amendment := a
+				amendment.InsertedAt = now
+				amendment.UpdatedAt = now
+
+				if err := txStorage.Amendment.InsertAmendment(ctx, &amendment); err != nil {
+					s.logger.Error(component, "Failed to insert amendment %s: %v", amendment.AmendmentCode, err)
+					return err
+				}
+			}
+			return tx.Commit()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info(component, "Amendments load completed for extraction date: %s", payload.ExtractionDate)
+	return nil
+}
+
+func (s *storageLoader) LoadCardExpenses(ctx context.Context, payload *service.CardExpensesPayload) error {
+	const component = "Loader"
+	s.logger.Info(component, "Starting card expenses load for extraction date: %s", payload.ExtractionDate)
+
+	for _, unit := range payload.UnitsCardExpenses {
+		err := func() error {
+			tx, err := s.storage.DB.BeginTxx(ctx, nil)
+			if err != nil {
+				s.logger.Error(component, "Failed to start transaction: %v", err)
+				return err
+			}
+			defer tx.Rollback()
+			txStorage := s.storage.WithTx(tx)
+
+			var managementCode int32
+			if store, ok := txStorage.CardExpense.(*CardExpenseStore); ok {
+				if err := store.DeleteCardExpenses(ctx, unit.UgCode, payload.ReferenceMonth); err != nil {
+					s.logger.Error(component, "Failed to reconcile card expenses for unit %d: %v", unit.UgCode, err)
+					return err
+				}
+				if len(unit.CardExpenses) > 0 {
+					managementCode, err = store.ManagementCode(ctx, unit.UgCode, unit.CardExpenses[0].OrganCode)
+					if err != nil {
+						s.logger.Error(component, "Failed to resolve management of unit %d: %v", unit.UgCode, err)
+						return err
+					}
+				}
+			}
+
+			now := time.Now()
+			for _, e := range unit.CardExpenses {
+				expense := e
+				expense.ManagementCode = managementCode
+				expense.InsertedAt = now
+				expense.UpdatedAt = now
+
+				if err := txStorage.CardExpense.InsertCardExpense(ctx, &expense); err != nil {
+					s.logger.Error(component, "Failed to insert card expense for unit %d (cardholder %s): %v", unit.UgCode, expense.CardholderName, err)
+					return err
+				}
+			}
+			return tx.Commit()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info(component, "Card expenses load completed for extraction date: %s", payload.ExtractionDate)
+	return nil
+}
+
+func (s *storageLoader) LoadSanctions(ctx context.Context, payload *service.SanctionsPayload) error {
+	const component = "Loader"
+	s.logger.Info(component, "Starting sanctions load for extraction date: %s", payload.ExtractionDate)
+
+	lastSeen, err := time.Parse(time.DateOnly, payload.ExtractionDate)
+	if err != nil {
+		return fmt.Errorf("invalid sanctions extraction date %q: %w", payload.ExtractionDate, err)
+	}
+
+	tx, err := s.storage.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.logger.Error(component, "Failed to start transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+	txStorage := s.storage.WithTx(tx)
+
+	now := time.Now()
+	for _, sn := range payload.Sanctions {
+		sanction := sn
+		sanction.LastSeenDate = lastSeen
+		sanction.InsertedAt = now
+		sanction.UpdatedAt = now
+
+		if err := txStorage.Sanction.InsertSanction(ctx, &sanction); err != nil {
+			s.logger.Error(component, "Failed to insert sanction %s/%s: %v", sanction.List, sanction.SanctionCode, err)
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.logger.Info(component, "Sanctions load completed for extraction date: %s (entries=%d)", payload.ExtractionDate, len(payload.Sanctions))
+	return nil
+}
+
+func (s *storageLoader) LoadRevenue(ctx context.Context, payload *service.RevenuePayload) error {
+	const component = "Loader"
+	s.logger.Info(component, "Starting revenue load for extraction date: %s", payload.ExtractionDate)
+
+	for _, unit := range payload.UnitsRevenues {
+		err := func() error {
+			tx, err := s.storage.DB.BeginTxx(ctx, nil)
+			if err != nil {
+				s.logger.Error(component, "Failed to start transaction: %v", err)
+				return err
+			}
+			defer tx.Rollback()
+			txStorage := s.storage.WithTx(tx)
+
+			if store, ok := txStorage.Revenue.(*RevenueStore); ok {
+				if err := store.DeleteRevenues(ctx, unit.UgCode, payload.FiscalYear); err != nil {
+					s.logger.Error(component, "Failed to reconcile revenues for unit %d: %v", unit.UgCode, err)
+					return err
+				}
+			}
+
+			now := time.Now()
+			for _, r := range unit.Revenues {
+				revenue := r
+				revenue.InsertedAt = now
+				revenue.UpdatedAt = now
+
+				if err := txStorage.Revenue.InsertRevenue(ctx, &revenue); err != nil {
+					s.logger.Error(component, "Failed to insert revenue for unit %d (%s): %v", unit.UgCode, revenue.ReferenceMonth, err)
+					return err
+				}
+			}
+			return tx.Commit()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info(component, "Revenue load completed for extraction date: %s", payload.ExtractionDate)
+	return nil
+}
+
+func (s *storageLoader) LoadTravel(ctx context.Context, payload *service.TravelPayload) error {
+	const component = "Loader"
+	s.logger.Info(component, "Starting travel load for extraction date: %s", payload.ExtractionDate)
+
+	for _, unit := range payload.UnitsTrips {
+		err := func() error {
+			tx, err := s.storage.DB.BeginTxx(ctx, nil)
+			if err != nil {
+				s.logger.Error(component, "Failed to start transaction: %v", err)
+				return err
+			}
+			defer tx.Rollback()
+			txStorage := s.storage.WithTx(tx)
+
+			for _, t := range unit.Trips {
+				now := time.Now()
+				trip := t
+				trip.InsertedAt = now
+				trip.UpdatedAt = now
+
+				if err := txStorage.Travel.InsertTrip(ctx, &trip); err != nil {
+					s.logger.Error(component, "Failed to insert trip %s (UG %d): %v", trip.TripID, trip.ManagementUnitCode, err)
+					return err
+				}
+
+				if store, ok := txStorage.Travel.(*TravelStore); ok {
+					if err := store.DeleteTripChildren(ctx, trip.TripID); err != nil {
+						s.logger.Error(component, "Failed to reconcile trip children for %s: %v", trip.TripID, err)
+						return err
+					}
+				}
+
+				for _, payment := range trip.Payments {
+					payment.InsertedAt = now
+					payment.UpdatedAt = now
+
+					if err := txStorage.Travel.InsertTripPayment(ctx, &payment); err != nil {
+						s.logger.Error(component, "Failed to insert payment for trip %s: %v", trip.TripID, err)
+						return err
+					}
+				}
+
+				for _, passage := range trip.Passages {
+					passage.InsertedAt = now
+					passage.UpdatedAt = now
+
+					if err := txStorage.Travel.InsertTripPassage(ctx, &passage); err != nil {
+						s.logger.Error(component, "Failed to insert passage for trip %s: %v", trip.TripID, err)
+						return err
+					}
+				}
+			}
+			return tx.Commit()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info(component, "Travel load completed for extraction date: %s", payload.ExtractionDate)
+	return nil
+}
@@ -2,8 +2,10 @@ package store
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
 )
 
 type PaymentStore struct {
@@ -154,3 +156,216 @@ func (ps *PaymentStore) DeleteImpactedCommitments(ctx context.Context, paymentCo
 	_, err := ps.db.ExecContext(ctx, `DELETE FROM payment_impacted_commitments WHERE payment_code = $1`, paymentCode)
 	return err
 }
+
+func (ps *PaymentStore) InsertPaymentFinalBeneficiary(ctx context.Context, pfb *model.PaymentFinalBeneficiary) error {
+	query := `INSERT INTO payment_final_beneficiaries (
+		payment_code,
+		final_beneficiary_code,
+		final_beneficiary_name,
+		received_value_brl,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:payment_code,
+		:final_beneficiary_code,
+		:final_beneficiary_name,
+		:received_value_brl,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (payment_code, final_beneficiary_code) DO UPDATE SET
+		final_beneficiary_name = EXCLUDED.final_beneficiary_name,
+		received_value_brl = EXCLUDED.received_value_brl,
+		inserted_at = EXCLUDED.inserted_at,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := ps.db.NamedExec(query, pfb)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ps *PaymentStore) DeleteFinalBeneficiaries(ctx context.Context, paymentCode string) error {
+	_, err := ps.db.ExecContext(ctx, `DELETE FROM payment_final_beneficiaries WHERE payment_code = $1`, paymentCode)
+	return err
+}
+
+func (ps *PaymentStore) InsertPaymentBankTransfer(ctx context.Context, pbt *model.PaymentBankTransfer) error {
+	query := `INSERT INTO payment_bank_transfers (
+		payment_code,
+		list_code,
+		bank_code,
+		bank_name,
+		value_brl,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:payment_code,
+		:list_code,
+		:bank_code,
+		:bank_name,
+		:value_brl,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (payment_code, list_code, bank_code) DO UPDATE SET
+		bank_name = EXCLUDED.bank_name,
+		value_brl = EXCLUDED.value_brl,
+		inserted_at = EXCLUDED.inserted_at,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := ps.db.NamedExec(query, pbt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ps *PaymentStore) InsertPaymentInvoice(ctx context.Context, pi *model.PaymentInvoice) error {
+	query := `INSERT INTO payment_invoices (
+		payment_code,
+		list_code,
+		invoice_number,
+		favored_code,
+		favored_name,
+		value_brl,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:payment_code,
+		:list_code,
+		:invoice_number,
+		:favored_code,
+		:favored_name,
+		:value_brl,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (payment_code, list_code, invoice_number) DO UPDATE SET
+		favored_code = EXCLUDED.favored_code,
+		favored_name = EXCLUDED.favored_name,
+		value_brl = EXCLUDED.value_brl,
+		inserted_at = EXCLUDED.inserted_at,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := ps.db.NamedExec(query, pi)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ps *PaymentStore) InsertPaymentCourtOrder(ctx context.Context, pco *model.PaymentCourtOrder) error {
+	query := `INSERT INTO payment_court_orders (
+		payment_code,
+		list_code,
+		process_number,
+		favored_code,
+		favored_name,
+		value_brl,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:payment_code,
+		:list_code,
+		:process_number,
+		:favored_code,
+		:favored_name,
+		:value_brl,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (payment_code, list_code, process_number) DO UPDATE SET
+		favored_code = EXCLUDED.favored_code,
+		favored_name = EXCLUDED.favored_name,
+		value_brl = EXCLUDED.value_brl,
+		inserted_at = EXCLUDED.inserted_at,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := ps.db.NamedExec(query, pco)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeletePaymentAnnexes removes the bank, invoice and court-order lists of a payment
+// so they can be reloaded from the latest file.
+func (ps *PaymentStore) DeletePaymentAnnexes(ctx context.Context, paymentCode string) error {
+	for _, table := range []string{"payment_bank_transfers", "payment_invoices", "payment_court_orders"} {
+		if _, err := ps.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE payment_code = $1`, table), paymentCode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ps *PaymentStore) GetPaymentDetail(ctx context.Context, paymentCode string) (*service.PaymentDetail, error) {
+	var detail service.PaymentDetail
+	err := ps.db.GetContext(ctx, &detail, `
+		SELECT
+			payment_code,
+			payment_emission_date,
+			document_type,
+			favored_code,
+			favored_name,
+			management_unit_code,
+			management_unit_name,
+			management_code,
+			process,
+			observation,
+			converted_payment_value
+		FROM payments
+		WHERE payment_code = $1
+	`, paymentCode)
+	if err != nil {
+		return nil, err
+	}
+
+	detail.FinalBeneficiaries = make([]service.PaymentFinalBeneficiaryInfo, 0)
+	if err := ps.db.SelectContext(ctx, &detail.FinalBeneficiaries, `
+		SELECT final_beneficiary_code, final_beneficiary_name, received_value_brl
+		FROM payment_final_beneficiaries
+		WHERE payment_code = $1
+		ORDER BY received_value_brl DESC
+	`, paymentCode); err != nil {
+		return nil, fmt.Errorf("failed to get payment final beneficiaries: %w", err)
+	}
+
+	detail.BankTransfers = make([]service.PaymentBankTransferInfo, 0)
+	if err := ps.db.SelectContext(ctx, &detail.BankTransfers, `
+		SELECT list_code, bank_code, bank_name, value_brl
+		FROM payment_bank_transfers
+		WHERE payment_code = $1
+		ORDER BY list_code, bank_code
+	`, paymentCode); err != nil {
+		return nil, fmt.Errorf("failed to get payment bank transfers: %w", err)
+	}
+
+	detail.Invoices = make([]service.PaymentInvoiceInfo, 0)
+	if err := ps.db.SelectContext(ctx, &detail.Invoices, `
+		SELECT list_code, invoice_number, favored_code, favored_name, value_brl
+		FROM payment_invoices
+		WHERE payment_code = $1
+		ORDER BY list_code, invoice_number
+	`, paymentCode); err != nil {
+		return nil, fmt.Errorf("failed to get payment invoices: %w", err)
+	}
+
+	detail.CourtOrders = make([]service.PaymentCourtOrderInfo, 0)
+	if err := ps.db.SelectContext(ctx, &detail.CourtOrders, `
+		SELECT list_code, process_number, favored_code, favored_name, value_brl
+		FROM payment_court_orders
+		WHERE payment_code = $1
+		ORDER BY list_code, process_number
+	`, paymentCode); err != nil {
+		return nil, fmt.Errorf("failed to get payment court orders: %w", err)
+	}
+
+	return &detail, nil
+}
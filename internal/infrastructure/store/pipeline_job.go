@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+)
+
+type PipelineJobStore struct {
+	db GenericQueryer
+}
+
+const (
+	PipelineJobQueued  = "QUEUED"
+	PipelineJobRunning = "RUNNING"
+	PipelineJobDone    = "DONE"
+	PipelineJobDead    = "DEAD"
+)
+
+const pipelineJobColumns = `id, dataset, job_key, payload, ingestion_job_id, status, attempts, visible_at, locked_by, last_error, created_at, updated_at`
+
+// EnqueuePipelineJob queues job and fills in its ID, status and creation time.
+// A job whose key is already queued or running is left out, and keeps a zero ID.
+func (ps *PipelineJobStore) EnqueuePipelineJob(ctx context.Context, job *model.PipelineJob) error {
+	query := `INSERT INTO pipeline_jobs (
+		dataset,
+		job_key,
+		payload,
+		ingestion_job_id
+	) VALUES (
+		:dataset,
+		:job_key,
+		:payload,
+		:ingestion_job_id
+	)
+	ON CONFLICT (dataset, job_key) WHERE status IN ('QUEUED', 'RUNNING') DO NOTHING
+	RETURNING id, status, created_at`
+
+	rows, err := ps.db.NamedQuery(query, job)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue pipeline job: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&job.ID, &job.Status, &job.CreatedAt); err != nil {
+			return fmt.Errorf("failed to enqueue pipeline job: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+// ClaimPipelineJob first dead-letters the running jobs of dataset whose worker
+// stopped extending them after their last attempt, then locks the next visible
+// job for worker. SKIP LOCKED lets concurrent workers, in this process or
+// others, claim different jobs without waiting on each other. It returns nil
+// when no job is visible.
+func (ps *PipelineJobStore) ClaimPipelineJob(ctx context.Context, dataset, worker string, visibility time.Duration, maxAttempts int) (*model.PipelineJob, error) {
+	expire := `
+		UPDATE pipeline_jobs
+		SET status = 'DEAD', locked_by = '', updated_at = NOW(),
+			last_error = 'visibility timeout expired on attempt ' || attempts
+		WHERE dataset = $1 AND status = 'RUNNING' AND visible_at <= NOW() AND attempts >= $2`
+	if _, err := ps.db.ExecContext(ctx, expire, dataset, maxAttempts); err != nil {
+		return nil, fmt.Errorf("failed to expire pipeline jobs: %w", err)
+	}
+
+	claim := `
+		UPDATE pipeline_jobs
+		SET status = 'RUNNING', attempts = attempts + 1, locked_by = $2,
+			visible_at = NOW() + $3 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM pipeline_jobs
+			WHERE dataset = $1 AND status IN ('QUEUED', 'RUNNING') AND visible_at <= NOW()
+			ORDER BY visible_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + pipelineJobColumns
+
+	var job model.PipelineJob
+	err := ps.db.GetContext(ctx, &job, claim, dataset, worker, visibility.Milliseconds())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pipeline job: %w", err)
+	}
+	return &job, nil
+}
+
+// ExtendPipelineJob keeps a running job hidden for another visibility period,
+// as long as worker still holds it.
+func (ps *PipelineJobStore) ExtendPipelineJob(ctx context.Context, id int64, worker string, visibility time.Duration) error {
+	query := `
+		UPDATE pipeline_jobs
+		SET visible_at = NOW() + $3 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'RUNNING'`
+	if _, err := ps.db.ExecContext(ctx, query, id, worker, visibility.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to extend pipeline job: %w", err)
+	}
+	return nil
+}
+
+// CompletePipelineJob marks a job DONE. Like the other transitions of a
+// running job, it only applies while worker still holds the job, and returns
+// repository.ErrJobLost otherwise.
+func (ps *PipelineJobStore) CompletePipelineJob(ctx context.Context, id int64, worker string) error {
+	query := `
+		UPDATE pipeline_jobs SET status = 'DONE', locked_by = '', updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'RUNNING'`
+	result, err := ps.db.ExecContext(ctx, query, id, worker)
+	if err != nil {
+		return fmt.Errorf("failed to complete pipeline job: %w", err)
+	}
+	return stillHeld(result)
+}
+
+// RetryPipelineJob puts a job back in the queue as payload, visible again after delay.
+func (ps *PipelineJobStore) RetryPipelineJob(ctx context.Context, id int64, worker string, payload []byte, delay time.Duration, lastError string) error {
+	query := `
+		UPDATE pipeline_jobs
+		SET status = 'QUEUED', locked_by = '', payload = $3, last_error = $4,
+			visible_at = NOW() + $5 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'RUNNING'`
+	result, err := ps.db.ExecContext(ctx, query, id, worker, payload, lastError, delay.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to retry pipeline job: %w", err)
+	}
+	return stillHeld(result)
+}
+
+func (ps *PipelineJobStore) DeadLetterPipelineJob(ctx context.Context, id int64, worker, lastError string) error {
+	query := `
+		UPDATE pipeline_jobs SET status = 'DEAD', locked_by = '', last_error = $3, updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'RUNNING'`
+	result, err := ps.db.ExecContext(ctx, query, id, worker, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter pipeline job: %w", err)
+	}
+	return stillHeld(result)
+}
+
+// ReleasePipelineJob requeues a job its worker did not run, because it was
+// interrupted or the period was leased elsewhere, visible again after delay.
+// The claim that started it counted an attempt, which is given back.
+func (ps *PipelineJobStore) ReleasePipelineJob(ctx context.Context, id int64, worker string, delay time.Duration, lastError string) error {
+	query := `
+		UPDATE pipeline_jobs
+		SET status = 'QUEUED', locked_by = '', last_error = $3, attempts = GREATEST(attempts - 1, 0),
+			visible_at = NOW() + $4 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'RUNNING'`
+	result, err := ps.db.ExecContext(ctx, query, id, worker, lastError, delay.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to release pipeline job: %w", err)
+	}
+	return stillHeld(result)
+}
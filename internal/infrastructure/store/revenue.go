@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/lib/pq"
+)
+
+type RevenueStore struct {
+	db GenericQueryer
+}
+
+func (rs *RevenueStore) InsertRevenue(ctx context.Context, revenue *model.Revenue) error {
+	query := `INSERT INTO revenues (
+		superior_organ_code,
+		superior_organ_name,
+		organ_code,
+		organ_name,
+		management_unit_code,
+		management_unit_name,
+		fiscal_year,
+		reference_month,
+		economic_category,
+		revenue_origin,
+		revenue_species,
+		revenue_detail,
+		forecast_value_brl,
+		launched_value_brl,
+		collected_value_brl,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:superior_organ_code,
+		:superior_organ_name,
+		:organ_code,
+		:organ_name,
+		:management_unit_code,
+		:management_unit_name,
+		:fiscal_year,
+		:reference_month,
+		:economic_category,
+		:revenue_origin,
+		:revenue_species,
+		:revenue_detail,
+		:forecast_value_brl,
+		:launched_value_brl,
+		:collected_value_brl,
+		:inserted_at,
+		:updated_at
+	)`
+
+	_, err := rs.db.NamedExec(query, revenue)
+	return err
+}
+
+// DeleteRevenues clears a unit's fiscal year before it is reloaded. The portal
+// republishes the whole year, so launches may move between months.
+func (rs *RevenueStore) DeleteRevenues(ctx context.Context, managementUnitCode int32, fiscalYear int32) error {
+	_, err := rs.db.ExecContext(ctx, `DELETE FROM revenues WHERE management_unit_code = $1 AND fiscal_year = $2`, managementUnitCode, fiscalYear)
+	return err
+}
+
+// GetBudgetBalance sums collected revenue and executed expenses per month.
+// Revenue carries no management code, so it is scoped to the units that
+// executed expenses under it.
+func (rs *RevenueStore) GetBudgetBalance(ctx context.Context, filter service.ExpensesFilter) ([]service.BudgetBalance, error) {
+	executionWhere := "WHERE e.management_code = $1"
+	revenueWhere := "WHERE r.management_unit_code IN (SELECT DISTINCT management_unit_code FROM expenses_execution WHERE management_code = $1)"
+	args := []interface{}{filter.ManagementCode}
+	argIndex := 2
+
+	if len(filter.ManagementUnitCodes) > 0 {
+		executionWhere += fmt.Sprintf(" AND e.management_unit_code = ANY($%d)", argIndex)
+		revenueWhere += fmt.Sprintf(" AND r.management_unit_code = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.ManagementUnitCodes))
+		argIndex++
+	}
+
+	if !filter.StartDate.IsZero() {
+		executionWhere += fmt.Sprintf(" AND e.year_and_month >= $%d", argIndex)
+		revenueWhere += fmt.Sprintf(" AND r.reference_month >= $%d", argIndex)
+		args = append(args, filter.StartDate.Format("2006/01"))
+		argIndex++
+	}
+
+	if !filter.EndDate.IsZero() {
+		executionWhere += fmt.Sprintf(" AND e.year_and_month <= $%d", argIndex)
+		revenueWhere += fmt.Sprintf(" AND r.reference_month <= $%d", argIndex)
+		args = append(args, filter.EndDate.Format("2006/01"))
+	}
+
+	query := fmt.Sprintf(`
+		WITH monthly AS (
+			SELECT
+				r.reference_month AS year_and_month,
+				SUM(r.collected_value_brl) AS collected_value_brl,
+				0 AS committed_value_brl,
+				0 AS paid_value_brl
+			FROM revenues r
+			%s
+			GROUP BY r.reference_month
+			UNION ALL
+			SELECT
+				e.year_and_month,
+				0,
+				SUM(e.committed_value_brl),
+				SUM(e.paid_value_brl)
+			FROM expenses_execution e
+			%s
+			GROUP BY e.year_and_month
+		)
+		SELECT
+			year_and_month,
+			SUM(collected_value_brl) AS collected_value_brl,
+			SUM(committed_value_brl) AS committed_value_brl,
+			SUM(paid_value_brl) AS paid_value_brl,
+			SUM(collected_value_brl) - SUM(paid_value_brl) AS balance_brl
+		FROM monthly
+		GROUP BY year_and_month
+		ORDER BY year_and_month DESC;
+	`, revenueWhere, executionWhere)
+
+	rows := make([]service.BudgetBalance, 0)
+	if err := rs.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to query budget balance: %w", err)
+	}
+	return rows, nil
+}
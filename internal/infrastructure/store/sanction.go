@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/lib/pq"
+)
+
+type SanctionStore struct {
+	db GenericQueryer
+}
+
+// InsertSanction upserts a list entry and bumps last_seen_date. Entries that
+// drop off the list keep their last state so history can still be screened.
+func (ss *SanctionStore) InsertSanction(ctx context.Context, sanction *model.Sanction) error {
+	query := `INSERT INTO sanctions (
+		list,
+		sanction_code,
+		person_type,
+		document_number,
+		sanctioned_name,
+		process_number,
+		category,
+		start_date,
+		end_date,
+		publication_date,
+		sanctioning_organ,
+		sanctioning_organ_uf,
+		legal_basis,
+		fine_value,
+		last_seen_date,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:list,
+		:sanction_code,
+		:person_type,
+		:document_number,
+		:sanctioned_name,
+		:process_number,
+		:category,
+		NULLIF(:start_date, DATE '0001-01-01'),
+		NULLIF(:end_date, DATE '0001-01-01'),
+		NULLIF(:publication_date, DATE '0001-01-01'),
+		:sanctioning_organ,
+		:sanctioning_organ_uf,
+		:legal_basis,
+		:fine_value,
+		:last_seen_date,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (list, sanction_code) DO UPDATE SET
+		person_type = EXCLUDED.person_type,
+		document_number = EXCLUDED.document_number,
+		sanctioned_name = EXCLUDED.sanctioned_name,
+		process_number = EXCLUDED.process_number,
+		category = EXCLUDED.category,
+		start_date = EXCLUDED.start_date,
+		end_date = EXCLUDED.end_date,
+		publication_date = EXCLUDED.publication_date,
+		sanctioning_organ = EXCLUDED.sanctioning_organ,
+		sanctioning_organ_uf = EXCLUDED.sanctioning_organ_uf,
+		legal_basis = EXCLUDED.legal_basis,
+		fine_value = EXCLUDED.fine_value,
+		last_seen_date = GREATEST(sanctions.last_seen_date, EXCLUDED.last_seen_date),
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := ss.db.NamedExec(query, sanction)
+	return err
+}
+
+// GetSanctionedFavored lists commitments and payments issued to a favored
+// entity while it was under sanction.
+func (ss *SanctionStore) GetSanctionedFavored(ctx context.Context, filter service.ExpensesFilter) ([]service.SanctionedFavored, error) {
+	commitmentWhere := "WHERE c.management_code = $1"
+	paymentWhere := "WHERE p.management_code = $1"
+	args := []interface{}{filter.ManagementCode}
+	argIndex := 2
+
+	if len(filter.ManagementUnitCodes) > 0 {
+		commitmentWhere += fmt.Sprintf(" AND c.management_unit_code = ANY($%d)", argIndex)
+		paymentWhere += fmt.Sprintf(" AND p.management_unit_code = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.ManagementUnitCodes))
+		argIndex++
+	}
+
+	if !filter.StartDate.IsZero() && !filter.EndDate.IsZero() {
+		commitmentWhere += fmt.Sprintf(" AND c.emission_date BETWEEN $%d AND $%d", argIndex, argIndex+1)
+		paymentWhere += fmt.Sprintf(" AND p.payment_emission_date BETWEEN $%d AND $%d", argIndex, argIndex+1)
+		args = append(args, filter.StartDate, filter.EndDate)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			'commitment' AS source,
+			c.commitment_code AS document_code,
+			c.emission_date AS document_date,
+			COALESCE(c.commitment_value_converted_to_brl, 0) AS value,
+			c.favored_code,
+			COALESCE(c.favored_name, '') AS favored_name,
+			s.list,
+			s.sanction_code,
+			s.category,
+			s.sanctioning_organ,
+			s.start_date,
+			s.end_date
+		FROM commitments c
+		JOIN sanctions s
+			ON %s
+			AND c.emission_date::date BETWEEN s.start_date AND COALESCE(s.end_date, 'infinity'::date)
+		%s
+		UNION ALL
+		SELECT
+			'payment',
+			p.payment_code,
+			p.payment_emission_date,
+			COALESCE(p.converted_payment_value, 0),
+			p.favored_code,
+			COALESCE(p.favored_name, ''),
+			s.list,
+			s.sanction_code,
+			s.category,
+			s.sanctioning_organ,
+			s.start_date,
+			s.end_date
+		FROM payments p
+		JOIN sanctions s
+			ON %s
+			AND p.payment_emission_date::date BETWEEN s.start_date AND COALESCE(s.end_date, 'infinity'::date)
+		%s
+		ORDER BY document_date DESC, document_code;
+	`, sanctionMatch("c.favored_code", "c.favored_name"), commitmentWhere, sanctionMatch("p.favored_code", "p.favored_name"), paymentWhere)
+
+	rows := make([]service.SanctionedFavored, 0)
+	if err := ss.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to query sanctioned favored: %w", err)
+	}
+	return rows, nil
+}
+
+// sanctionMatch is the condition under which the favored entity in codeColumn
+// and nameColumn is the one a sanction s names, as in is_sanctioned: a full
+// CNPJ matches on its own, the visible digits of a masked CPF only with the
+// same name, and entries with no document never match.
+func sanctionMatch(codeColumn, nameColumn string) string {
+	return fmt.Sprintf(`s.document_number <> ''
+			AND s.document_number = regexp_replace(%s, '\D', '', 'g')
+			AND (length(s.document_number) = 14 OR upper(trim(s.sanctioned_name)) = upper(trim(%s)))`, codeColumn, nameColumn)
+}
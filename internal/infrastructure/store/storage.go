@@ -17,10 +17,34 @@ type Storage struct {
 
 	IngestionHistory repository.IngestionHistoryInterface
 
+	IngestionRejection repository.IngestionRejectionInterface
+
+	IngestionJob repository.IngestionJobInterface
+
+	IngestionLease repository.IngestionLeaseInterface
+
+	PipelineJob repository.PipelineJobInterface
+
 	Expenses repository.ExpensesInterface
 
 	ExpensesExecution repository.ExpensesExecutionInterface
 
+	Contract repository.ContractInterface
+
+	Bidding repository.BiddingInterface
+
+	Agreement repository.AgreementInterface
+
+	Amendment repository.AmendmentInterface
+
+	CardExpense repository.CardExpenseInterface
+
+	Sanction repository.SanctionInterface
+
+	Revenue repository.RevenueInterface
+
+	Travel repository.TravelInterface
+
 	DB *sqlx.DB
 }
 
@@ -36,23 +60,47 @@ type GenericQueryer interface {
 
 func (s *Storage) WithTx(tx *sqlx.Tx) *Storage {
 	return &Storage{
-		Commitment:        &CommitmentStore{db: tx},
-		Liquidation:       &LiquidationStore{db: tx},
-		Payment:           &PaymentStore{db: tx},
-		IngestionHistory:  &IngestionHistoryStore{db: tx},
-		Expenses:          &ExpensesStore{db: tx},
-		ExpensesExecution: &ExpensesExecutionStore{db: tx},
+		Commitment:         &CommitmentStore{db: tx},
+		Liquidation:        &LiquidationStore{db: tx},
+		Payment:            &PaymentStore{db: tx},
+		IngestionHistory:   &IngestionHistoryStore{db: tx},
+		IngestionRejection: &IngestionRejectionStore{db: tx},
+		IngestionJob:       &IngestionJobStore{db: tx},
+		IngestionLease:     &IngestionLeaseStore{db: tx},
+		PipelineJob:        &PipelineJobStore{db: tx},
+		Expenses:           &ExpensesStore{db: tx},
+		ExpensesExecution:  &ExpensesExecutionStore{db: tx},
+		Contract:           &ContractStore{db: tx},
+		Bidding:            &BiddingStore{db: tx},
+		Agreement:          &AgreementStore{db: tx},
+		Amendment:          &AmendmentStore{db: tx},
+		CardExpense:        &CardExpenseStore{db: tx},
+		Sanction:           &SanctionStore{db: tx},
+		Revenue:            &RevenueStore{db: tx},
+		Travel:             &TravelStore{db: tx},
 	}
 }
 
 func NewStorage(db *sqlx.DB) *Storage {
 	return &Storage{
-		Commitment:        &CommitmentStore{db: db},
-		Liquidation:       &LiquidationStore{db: db},
-		Payment:           &PaymentStore{db: db},
-		IngestionHistory:  &IngestionHistoryStore{db: db},
-		Expenses:          &ExpensesStore{db: db},
-		ExpensesExecution: &ExpensesExecutionStore{db: db},
-		DB:                db,
+		Commitment:         &CommitmentStore{db: db},
+		Liquidation:        &LiquidationStore{db: db},
+		Payment:            &PaymentStore{db: db},
+		IngestionHistory:   &IngestionHistoryStore{db: db},
+		IngestionRejection: &IngestionRejectionStore{db: db},
+		IngestionJob:       &IngestionJobStore{db: db},
+		IngestionLease:     &IngestionLeaseStore{db: db},
+		PipelineJob:        &PipelineJobStore{db: db},
+		Expenses:           &ExpensesStore{db: db},
+		ExpensesExecution:  &ExpensesExecutionStore{db: db},
+		Contract:           &ContractStore{db: db},
+		Bidding:            &BiddingStore{db: db},
+		Agreement:          &AgreementStore{db: db},
+		Amendment:          &AmendmentStore{db: db},
+		CardExpense:        &CardExpenseStore{db: db},
+		Sanction:           &SanctionStore{db: db},
+		Revenue:            &RevenueStore{db: db},
+		Travel:             &TravelStore{db: db},
+		DB:                 db,
 	}
 }
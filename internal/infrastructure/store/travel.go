@@ -0,0 +1,214 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/farxc/envelopa-transparencia/internal/domain/model"
+	"github.com/farxc/envelopa-transparencia/internal/domain/service"
+	"github.com/lib/pq"
+)
+
+type TravelStore struct {
+	db GenericQueryer
+}
+
+func (ts *TravelStore) InsertTrip(ctx context.Context, trip *model.Trip) error {
+	query := `INSERT INTO travel_trips (
+		trip_id,
+		proposal_number,
+		situation,
+		urgent,
+		superior_organ_code,
+		superior_organ_name,
+		requesting_organ_code,
+		requesting_organ_name,
+		management_unit_code,
+		management_unit_name,
+		traveler_name,
+		traveler_role,
+		traveler_function,
+		start_date,
+		end_date,
+		destinations,
+		reason,
+		per_diem_value_brl,
+		tickets_value_brl,
+		returned_value_brl,
+		other_expenses_value_brl,
+		fiscal_year,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:trip_id,
+		:proposal_number,
+		:situation,
+		:urgent,
+		:superior_organ_code,
+		:superior_organ_name,
+		:requesting_organ_code,
+		:requesting_organ_name,
+		:management_unit_code,
+		:management_unit_name,
+		:traveler_name,
+		:traveler_role,
+		:traveler_function,
+		:start_date,
+		:end_date,
+		:destinations,
+		:reason,
+		:per_diem_value_brl,
+		:tickets_value_brl,
+		:returned_value_brl,
+		:other_expenses_value_brl,
+		:fiscal_year,
+		:inserted_at,
+		:updated_at
+	)
+		ON CONFLICT (trip_id) DO UPDATE SET
+		proposal_number = EXCLUDED.proposal_number,
+		situation = EXCLUDED.situation,
+		urgent = EXCLUDED.urgent,
+		superior_organ_code = EXCLUDED.superior_organ_code,
+		superior_organ_name = EXCLUDED.superior_organ_name,
+		requesting_organ_code = EXCLUDED.requesting_organ_code,
+		requesting_organ_name = EXCLUDED.requesting_organ_name,
+		management_unit_code = EXCLUDED.management_unit_code,
+		management_unit_name = EXCLUDED.management_unit_name,
+		traveler_name = EXCLUDED.traveler_name,
+		traveler_role = EXCLUDED.traveler_role,
+		traveler_function = EXCLUDED.traveler_function,
+		start_date = EXCLUDED.start_date,
+		end_date = EXCLUDED.end_date,
+		destinations = EXCLUDED.destinations,
+		reason = EXCLUDED.reason,
+		per_diem_value_brl = EXCLUDED.per_diem_value_brl,
+		tickets_value_brl = EXCLUDED.tickets_value_brl,
+		returned_value_brl = EXCLUDED.returned_value_brl,
+		other_expenses_value_brl = EXCLUDED.other_expenses_value_brl,
+		fiscal_year = EXCLUDED.fiscal_year,
+		updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := ts.db.NamedExec(query, trip)
+	return err
+}
+
+func (ts *TravelStore) InsertTripPayment(ctx context.Context, payment *model.TripPayment) error {
+	query := `INSERT INTO travel_payments (
+		trip_id,
+		paying_organ_code,
+		paying_organ_name,
+		management_unit_code,
+		management_unit_name,
+		payment_type,
+		value,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:trip_id,
+		:paying_organ_code,
+		:paying_organ_name,
+		:management_unit_code,
+		:management_unit_name,
+		:payment_type,
+		:value,
+		:inserted_at,
+		:updated_at
+	)`
+
+	_, err := ts.db.NamedExec(query, payment)
+	return err
+}
+
+func (ts *TravelStore) InsertTripPassage(ctx context.Context, passage *model.TripPassage) error {
+	query := `INSERT INTO travel_passages (
+		trip_id,
+		transport,
+		origin_country,
+		origin_uf,
+		origin_city,
+		destination_country,
+		destination_uf,
+		destination_city,
+		value,
+		service_fee,
+		issue_date,
+		inserted_at,
+		updated_at
+	) VALUES (
+		:trip_id,
+		:transport,
+		:origin_country,
+		:origin_uf,
+		:origin_city,
+		:destination_country,
+		:destination_uf,
+		:destination_city,
+		:value,
+		:service_fee,
+		:issue_date,
+		:inserted_at,
+		:updated_at
+	)`
+
+	_, err := ts.db.NamedExec(query, passage)
+	return err
+}
+
+// DeleteTripChildren clears a trip's payments and tickets before they are
+// reloaded; neither has a natural key in the portal files.
+func (ts *TravelStore) DeleteTripChildren(ctx context.Context, tripID string) error {
+	if _, err := ts.db.ExecContext(ctx, `DELETE FROM travel_payments WHERE trip_id = $1`, tripID); err != nil {
+		return err
+	}
+
+	if _, err := ts.db.ExecContext(ctx, `DELETE FROM travel_passages WHERE trip_id = $1`, tripID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var travelGroupColumns = map[string]string{
+	service.TravelGroupByRole:        "COALESCE(NULLIF(t.traveler_role, ''), 'Sem informação')",
+	service.TravelGroupByDestination: "COALESCE(NULLIF(t.destinations, ''), 'Sem informação')",
+	service.TravelGroupByUnit:        "t.management_unit_code::text || ' - ' || COALESCE(t.management_unit_name, '')",
+}
+
+func (ts *TravelStore) GetTravelCosts(ctx context.Context, filter service.GetTravelCostsFilter) ([]service.TravelCost, error) {
+	groupColumn, ok := travelGroupColumns[filter.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid travel grouping: %q", filter.GroupBy)
+	}
+
+	whereClause := "WHERE t.start_date BETWEEN $1 AND $2"
+	args := []interface{}{filter.StartDate, filter.EndDate}
+	argIndex := 3
+
+	if len(filter.ManagementUnitCodes) > 0 {
+		whereClause += fmt.Sprintf(" AND t.management_unit_code::text = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.ManagementUnitCodes))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s AS group_key,
+			COUNT(*) AS trips_count,
+			SUM(t.per_diem_value_brl) AS per_diem_value_brl,
+			SUM(t.tickets_value_brl) AS tickets_value_brl,
+			SUM(t.other_expenses_value_brl) AS other_expenses_value_brl,
+			SUM(t.returned_value_brl) AS returned_value_brl,
+			SUM(t.per_diem_value_brl + t.tickets_value_brl + t.other_expenses_value_brl - t.returned_value_brl) AS total_value_brl
+		FROM travel_trips t
+		%s
+		GROUP BY 1
+		ORDER BY total_value_brl DESC;
+	`, groupColumn, whereClause)
+
+	rows := make([]service.TravelCost, 0)
+	if err := ts.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to query travel costs: %w", err)
+	}
+	return rows, nil
+}
@@ -73,3 +73,14 @@ func ParseInt16(val int) int16 {
 func ParseBool(valStr string) bool {
 	return strings.EqualFold(valStr, "Sim") || strings.EqualFold(valStr, "Yes") || valStr == "1"
 }
+
+// OnlyDigits strips the formatting from CPF/CNPJ and process numbers.
+func OnlyDigits(valStr string) string {
+	var b strings.Builder
+	for _, r := range valStr {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
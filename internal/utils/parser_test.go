@@ -35,3 +35,18 @@ func TestParseFloat(t *testing.T) {
 		})
 	}
 }
+
+func TestOnlyDigits(t *testing.T) {
+	tests := map[string]string{
+		"":                   "",
+		"12.345.678/0001-90": "12345678000190",
+		"***.456.789-**":     "456789",
+		"12345678000190":     "12345678000190",
+	}
+
+	for input, want := range tests {
+		if got := OnlyDigits(input); got != want {
+			t.Fatalf("OnlyDigits(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
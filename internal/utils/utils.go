@@ -1,80 +1,42 @@
 package utils
 
-import "github.com/go-gota/gota/dataframe"
-
-func containsString(slice []string, s string) bool {
-	for _, v := range slice {
-		if v == s {
-			return true
-		}
-	}
-	return false
+import (
+	"strconv"
+	"strings"
+)
+
+// Row is any decoded record that exposes raw values by column name.
+type Row interface {
+	Get(col string) string
 }
 
-func GetStr(col string, rowIdx int, df *dataframe.DataFrame) string {
-
-	if df == nil {
+func GetStr(col string, row Row) string {
+	if row == nil {
 		return ""
 	}
-
-	if containsString(df.Names(), col) {
-		return df.Col(col).Elem(rowIdx).String()
-	}
-	return ""
+	return row.Get(col)
 }
 
-func GetInt(col string, rowIdx int, df *dataframe.DataFrame) int {
-	if df == nil {
+func getInt(col string, row Row) int64 {
+	val, err := strconv.ParseInt(strings.TrimSpace(GetStr(col, row)), 10, 64)
+	if err != nil {
 		return 0
 	}
-	if idx := df.Names(); containsString(idx, col) {
-		val, err := df.Col(col).Elem(rowIdx).Int()
-		if err != nil {
-			return 0
-		}
-		return val
-	}
-	return 0
+	return val
 }
 
-func GetInt16(col string, rowIdx int, df *dataframe.DataFrame) int16 {
-	if df == nil {
-		return 0
-	}
-	if idx := df.Names(); containsString(idx, col) {
-		val, err := df.Col(col).Elem(rowIdx).Int()
-		if err != nil {
-			return 0
-		}
-		return int16(val)
-	}
-	return 0
+func GetInt(col string, row Row) int {
+	return int(getInt(col, row))
 }
 
-func GetInt32(col string, rowIdx int, df *dataframe.DataFrame) int32 {
-	if df == nil {
-		return 0
-	}
-	if idx := df.Names(); containsString(idx, col) {
-		val, err := df.Col(col).Elem(rowIdx).Int()
-		if err != nil {
-			return 0
-		}
-		return int32(val)
-	}
-	return 0
+func GetInt16(col string, row Row) int16 {
+	return int16(getInt(col, row))
 }
 
-func GetInt64(col string, rowIdx int, df *dataframe.DataFrame) int64 {
-	if df == nil {
-		return 0
-	}
-	if idx := df.Names(); containsString(idx, col) {
-		val, err := df.Col(col).Elem(rowIdx).Int()
-		if err != nil {
-			return 0
-		}
-		return int64(val)
-	}
-	return 0
+func GetInt32(col string, row Row) int32 {
+	return int32(getInt(col, row))
+}
+
+func GetInt64(col string, row Row) int64 {
+	return getInt(col, row)
 }